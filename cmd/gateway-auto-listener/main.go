@@ -1,22 +1,39 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/an0nfunc/gateway-auto-listener/internal/controller"
 )
 
+// minListenerNameLength leaves room for a readable prefix next to the hash
+// suffix of truncated listener names.
+const minListenerNameLength = 16
+
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
@@ -36,7 +53,60 @@ func main() {
 		gatewayNamespace           string
 		allowedDomainSuffix        string
 		validatedNSPrefix          string
+		validatedNSLabelSelector   string
 		allowedHostnamesAnnotation string
+		strictAllowedHostnames     bool
+		rejectClaimConflicts       bool
+		maxListenerNameLength      int
+		reportNewListenerPorts     bool
+		listenerPorts              string
+		validationShadowMode       bool
+		enforceOnPolicyChange      bool
+		requeueAfterSuccess        time.Duration
+		onEmptyHostname            string
+		externalSecretCheck        bool
+		maxAllowedHostnames        int
+		hostnameIncludeRegex       string
+		migrateFromGateway         string
+		configConfigMap            string
+		patchStrategy              string
+		eventTarget                string
+		gatewayMutationRate        float64
+		reconcileSummaryLog        bool
+		additionalGatewaysFlag     string
+		skipCertRefIfDefault       bool
+		recreateOnIssuerChange     bool
+		disambiguateSecretNames    bool
+		gatewayNotFoundRequeue     time.Duration
+		tenantAllowedRoutes        string
+		platformAllowedRoutes      string
+		observeOnly                bool
+		allowApex                  bool
+		instanceID                 string
+		validateListeners          bool
+		defaultListenerPort        int
+		allowedRoutesFrom          string
+		allowedRoutesSelector      string
+		manageCertificates         bool
+		dryRun                     bool
+		listenerNameTemplate       string
+		secretNameTemplate         string
+		createHTTPRedirect         bool
+		maxListeners               int
+		manageGRPCRoutes           bool
+		skipWildcardCovered        bool
+		listenerTLSOptions         []string
+		enableWebhook              bool
+		webhookPort                int
+		webhookCertDir             string
+		eventThrottle              time.Duration
+		defaultHostnameTemplate    string
+		validateIssuer             bool
+		correctListenerDrift       bool
+		allowedSecretNamespaces    string
+		forceIssuerKind            string
+		manageReferenceGrants      bool
+		referenceGrantNamespaces   string
 		showVersion                bool
 	)
 
@@ -46,7 +116,63 @@ func main() {
 	flag.StringVar(&gatewayNamespace, "gateway-namespace", "nginx-gateway", "Namespace of the Gateway.")
 	flag.StringVar(&allowedDomainSuffix, "allowed-domain-suffix", "", "Domain suffix for tenant hostnames (e.g., example.com). Empty disables suffix validation.")
 	flag.StringVar(&validatedNSPrefix, "validated-ns-prefix", "", "Namespace prefix triggering hostname validation. Empty disables validation entirely.")
+	flag.StringVar(&validatedNSLabelSelector, "validated-ns-label-selector", "", "Label selector for namespaces subject to hostname validation, in addition to --validated-ns-prefix. Empty disables label-based selection.")
 	flag.StringVar(&allowedHostnamesAnnotation, "allowed-hostnames-annotation", "gateway-auto-listener/allowed-hostnames", "Namespace annotation key for allowed custom hostnames.")
+	flag.BoolVar(&strictAllowedHostnames, "strict-allowed-hostnames", false, "Record a warning event on namespaces whose allowed-hostnames annotation has empty or malformed entries.")
+	flag.BoolVar(&rejectClaimConflicts, "reject-hostname-claim-conflicts", true, "Reject custom domains claimed by more than one validated namespace for all but the oldest claimant.")
+	flag.IntVar(&maxListenerNameLength, "max-listener-name-length", 63, "Maximum length of generated listener names; longer names are truncated with a hash suffix. 0 disables the limit.")
+	flag.BoolVar(&reportNewListenerPorts, "report-new-listener-ports", false, "Record an event when a listener is added on a port no other Gateway listener uses.")
+	flag.StringVar(&listenerPorts, "listener-ports", "", "Comma-separated ports to create a listener on for each hostname. The first port keeps the plain listener name; others are suffixed with the port. Empty uses --default-listener-port.")
+	flag.BoolVar(&validationShadowMode, "validation-shadow-mode", false, "Admit hostnames that fail validation, only logging, recording events and counting what would have been rejected.")
+	flag.BoolVar(&enforceOnPolicyChange, "enforce-on-policy-change", false, "Remove managed listeners whose hostname no longer passes validation.")
+	flag.DurationVar(&requeueAfterSuccess, "requeue-after-success", 0, "Re-verify a route's listeners this long after a successful reconcile. 0 disables the periodic requeue.")
+	flag.StringVar(&onEmptyHostname, "on-empty-hostname", controller.EmptyHostnameSkip, "How to handle empty hostnames on a route: skip ignores them, error fails the reconcile, event records a warning and ignores them.")
+	flag.BoolVar(&externalSecretCheck, "externalsecret-check", false, "Hold back a listener until the External Secrets Operator ExternalSecret syncing its certificate Secret is Ready.")
+	flag.IntVar(&maxAllowedHostnames, "max-allowed-hostnames", 0, "Maximum number of allowed-hostnames entries evaluated per namespace; further entries are ignored with a warning event. 0 disables the limit.")
+	flag.StringVar(&hostnameIncludeRegex, "hostname-include-regex", "", "Only create listeners for hostnames matching this regular expression. Empty includes all hostnames.")
+	flag.StringVar(&migrateFromGateway, "migrate-from-gateway", "", "Previously managed Gateway as <namespace>/<name>; its managed listeners are moved to the configured Gateway on startup.")
+	flag.StringVar(&configConfigMap, "config-configmap", "", "ConfigMap as <namespace>/<name> watched for allowed-domain-suffix and validated-ns-prefix overrides; changes re-reconcile all routes without a restart.")
+	flag.StringVar(&patchStrategy, "patch-strategy", controller.PatchStrategyMerge, "How listener changes are written to the Gateway: merge, optimistic (resourceVersion-guarded merge) or apply (server-side apply).")
+	flag.StringVar(&eventTarget, "event-target", controller.EventTargetRoute, "Where listener lifecycle events are recorded: route, gateway or both.")
+	flag.Float64Var(&gatewayMutationRate, "gateway-mutation-rate", 0, "Maximum listener patches per second and Gateway; excess changes are requeued. 0 disables the limit.")
+	flag.BoolVar(&reconcileSummaryLog, "reconcile-summary-log", false, "Log one summary line per reconcile with listener changes, validation failures and whether the Gateway was patched.")
+	flag.StringVar(&additionalGatewaysFlag, "additional-gateways", "", "Comma-separated <namespace>/<name> Gateways managed alongside the configured one. Routes get listeners on every managed Gateway their parentRefs reference.")
+	flag.BoolVar(&skipCertRefIfDefault, "skip-cert-ref-if-default", false, "Omit the certificate ref of listeners on Gateways with a gateway-auto-listener/default-cert-secret annotation, relying on the Gateway's default certificate. Only applies to listeners with TLS options.")
+	flag.BoolVar(&recreateOnIssuerChange, "recreate-on-issuer-change", false, "Remove and re-add a route's listeners when its cert-manager issuer annotation changes.")
+	flag.BoolVar(&disambiguateSecretNames, "disambiguate-secret-names", false, "Append a hostname hash to the certificate secret name when another hostname on the Gateway already uses the generated name.")
+	flag.DurationVar(&gatewayNotFoundRequeue, "gateway-notfound-requeue", 30*time.Second, "Fixed requeue interval for routes while a managed Gateway does not exist. 0 falls back to error backoff.")
+	flag.StringVar(&tenantAllowedRoutes, "tenant-allowed-routes", "", "AllowedRoutes namespaces of listeners for routes in validated namespaces: All, Same or Selector. Empty uses --allowed-routes-from.")
+	flag.StringVar(&platformAllowedRoutes, "platform-allowed-routes", "", "AllowedRoutes namespaces of listeners for routes in other namespaces: All, Same or Selector. Empty uses --allowed-routes-from.")
+	flag.BoolVar(&observeOnly, "observe-only", false, "Watch and log every write the controller would make without writing anything to the cluster.")
+	flag.BoolVar(&allowApex, "allow-apex", false, "Allow validated namespaces to use the bare allowed domain suffix (e.g., example.com) as a hostname.")
+	flag.StringVar(&instanceID, "instance-id", "", "Identifier scoping this instance's finalizer, route annotations, field manager and leader election, so several instances can manage disjoint hostnames on one Gateway.")
+	flag.BoolVar(&validateListeners, "validate-listeners", false, "Check each listener against the Gateway API schema before patching, skipping invalid ones with an event instead of failing the whole patch.")
+	flag.IntVar(&defaultListenerPort, "default-listener-port", 443, "Port listeners are created on when --listener-ports is empty and a route sets no listener-port annotation.")
+	flag.StringVar(&allowedRoutesFrom, "allowed-routes-from", string(gatewayv1.NamespacesFromAll), "Default AllowedRoutes namespaces of created listeners: All, Same or Selector.")
+	flag.StringVar(&allowedRoutesSelector, "allowed-routes-selector-annotation", "gateway-auto-listener/allowed-routes-selector", "Route annotation holding the label selector of listeners scoped to Selector. Without it, such listeners only admit routes from the route's own namespace.")
+	flag.BoolVar(&manageCertificates, "manage-certificates", false, "Create a cert-manager Certificate in the Gateway namespace for every listener of a route with an issuer annotation, and delete it with the listener.")
+	flag.BoolVar(&dryRun, "dry-run", false, "Record the listener changes of every route as DryRunAddListener/DryRunRemoveListener events instead of patching the Gateway, and write no finalizers or annotations.")
+	flag.StringVar(&listenerNameTemplate, "listener-name-template", controller.DefaultListenerNameTemplate, "Go template rendering the listener name of a hostname from .Hostname and .Sanitized (dots as dashes, * as wildcard).")
+	flag.StringVar(&secretNameTemplate, "secret-name-template", controller.DefaultSecretNameTemplate, "Go template rendering the certificate Secret name of a hostname from .Hostname and .Sanitized (dots as dashes, * as wildcard).")
+	flag.BoolVar(&createHTTPRedirect, "create-http-redirect", false, "Also create a plain HTTP listener on port 80 for each hostname, for routes redirecting HTTP to HTTPS.")
+	flag.IntVar(&maxListeners, "max-listeners", 64, "Maximum listeners on a Gateway (the Gateway API allows 64). Additions beyond it are skipped with a ListenerLimitExceeded event. 0 disables the limit.")
+	flag.BoolVar(&manageGRPCRoutes, "manage-grpcroutes", false, "Also provision listeners for GRPCRoutes with an issuer annotation, like for HTTPRoutes.")
+	flag.BoolVar(&skipWildcardCovered, "skip-wildcard-covered", false, "Do not add a listener for a hostname a wildcard listener already on the Gateway serves on the same port.")
+	flag.Func("listener-tls-options", "Comma-separated key=value TLS options set on every listener created, e.g. example.com/min-version=1.2. Repeatable.", func(value string) error {
+		listenerTLSOptions = append(listenerTLSOptions, value)
+		return nil
+	})
+	flag.BoolVar(&enableWebhook, "enable-webhook", false, "Serve a validating admission webhook rejecting HTTPRoutes with hostnames not allowed for their namespace.")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the admission webhook server listens on.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "", "Directory holding the webhook server's tls.crt and tls.key. Empty uses controller-runtime's default, <temp-dir>/k8s-webhook-server/serving-certs.")
+	flag.DurationVar(&eventThrottle, "event-throttle", 0, "Record a route's hostname validation and ListenerNameConflict warning events at most once per this interval per hostname. 0 records them on every reconcile.")
+	flag.StringVar(&defaultHostnameTemplate, "default-hostname-template", controller.DefaultHostnameTemplate, "Go template rendering, from .Namespace and .Suffix, the suffix default subdomains of a namespace end with. A * matches within one label, e.g. .{{.Namespace}}--*.{{.Suffix}}.")
+	flag.BoolVar(&validateIssuer, "validate-issuer", false, "Skip the listeners of a route whose cert-manager ClusterIssuer or Issuer does not exist, with an IssuerNotFound event.")
+	flag.BoolVar(&correctListenerDrift, "correct-listener-drift", true, "Patch the listeners a route owns back to their desired state, undoing manual edits to them and carrying route annotation changes to existing listeners. Set to false to leave existing listeners as they are.")
+	flag.StringVar(&allowedSecretNamespaces, "allowed-secret-namespaces", "", "Comma-separated namespaces, besides the Gateway's, whose Secrets a route's tls-secret-namespace annotation may point listeners at. Other namespaces are refused.")
+	flag.StringVar(&forceIssuerKind, "force-issuer-kind", "", "Override the issuerRef kind, ClusterIssuer or Issuer, of the Certificates created with --manage-certificates. Empty follows the route's cert-manager.io/cluster-issuer or cert-manager.io/issuer annotation.")
+	flag.BoolVar(&manageReferenceGrants, "manage-reference-grants", false, "Create a ReferenceGrant in each namespace whose Secrets listeners reference through a route's tls-secret-namespace annotation, and delete it once no listener references a Secret there.")
+	flag.StringVar(&referenceGrantNamespaces, "referencegrant-allowed-namespaces", "", "Comma-separated namespaces --manage-reference-grants may create ReferenceGrants in. A grant in any other namespace is refused with a ReferenceGrantNotAllowed event. Empty allows every namespace listeners reference Secrets in.")
 	flag.BoolVar(&showVersion, "version", false, "Print version and exit.")
 
 	opts := zap.Options{Development: false}
@@ -60,33 +186,330 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if maxListenerNameLength != 0 && maxListenerNameLength < minListenerNameLength {
+		setupLog.Error(fmt.Errorf("must be 0 or at least %d, got %d", minListenerNameLength, maxListenerNameLength),
+			"invalid --max-listener-name-length")
+		os.Exit(1)
+	}
+
+	if requeueAfterSuccess < 0 {
+		setupLog.Error(fmt.Errorf("must not be negative, got %s", requeueAfterSuccess), "invalid --requeue-after-success")
+		os.Exit(1)
+	}
+
+	if gatewayNotFoundRequeue < 0 {
+		setupLog.Error(fmt.Errorf("must not be negative, got %s", gatewayNotFoundRequeue), "invalid --gateway-notfound-requeue")
+		os.Exit(1)
+	}
+
+	if maxAllowedHostnames < 0 {
+		setupLog.Error(fmt.Errorf("must not be negative, got %d", maxAllowedHostnames), "invalid --max-allowed-hostnames")
+		os.Exit(1)
+	}
+
+	switch onEmptyHostname {
+	case controller.EmptyHostnameSkip, controller.EmptyHostnameError, controller.EmptyHostnameEvent:
+	default:
+		setupLog.Error(fmt.Errorf("must be one of skip, error or event, got %q", onEmptyHostname), "invalid --on-empty-hostname")
+		os.Exit(1)
+	}
+
+	switch patchStrategy {
+	case controller.PatchStrategyMerge, controller.PatchStrategyOptimistic, controller.PatchStrategyApply:
+	default:
+		setupLog.Error(fmt.Errorf("must be one of merge, optimistic or apply, got %q", patchStrategy), "invalid --patch-strategy")
+		os.Exit(1)
+	}
+
+	if maxListeners < 0 {
+		setupLog.Error(fmt.Errorf("must not be negative, got %d", maxListeners), "invalid --max-listeners")
+		os.Exit(1)
+	}
+
+	if eventThrottle < 0 {
+		setupLog.Error(fmt.Errorf("must not be negative, got %s", eventThrottle), "invalid --event-throttle")
+		os.Exit(1)
+	}
+
+	if enableWebhook && (webhookPort < 1 || webhookPort > 65535) {
+		setupLog.Error(fmt.Errorf("must be between 1 and 65535, got %d", webhookPort), "invalid --webhook-port")
+		os.Exit(1)
+	}
+
+	if gatewayMutationRate < 0 {
+		setupLog.Error(fmt.Errorf("must not be negative, got %g", gatewayMutationRate), "invalid --gateway-mutation-rate")
+		os.Exit(1)
+	}
+
+	for name, value := range map[string]string{
+		"allowed-routes-from":     allowedRoutesFrom,
+		"tenant-allowed-routes":   tenantAllowedRoutes,
+		"platform-allowed-routes": platformAllowedRoutes,
+	} {
+		if value == "" && name != "allowed-routes-from" {
+			continue
+		}
+		switch gatewayv1.FromNamespaces(value) {
+		case gatewayv1.NamespacesFromAll, gatewayv1.NamespacesFromSame, gatewayv1.NamespacesFromSelector:
+		default:
+			setupLog.Error(fmt.Errorf("must be %s, %s or %s, got %q", gatewayv1.NamespacesFromAll, gatewayv1.NamespacesFromSame,
+				gatewayv1.NamespacesFromSelector, value), "invalid --"+name)
+			os.Exit(1)
+		}
+	}
+
+	if instanceID != "" {
+		if errs := validation.IsDNS1123Label(instanceID); len(errs) > 0 {
+			setupLog.Error(errors.New(strings.Join(errs, "; ")), "invalid --instance-id")
+			os.Exit(1)
+		}
+	}
+
+	switch eventTarget {
+	case controller.EventTargetRoute, controller.EventTargetGateway, controller.EventTargetBoth:
+	default:
+		setupLog.Error(fmt.Errorf("must be one of route, gateway or both, got %q", eventTarget), "invalid --event-target")
+		os.Exit(1)
+	}
+
+	if defaultListenerPort < 1 || defaultListenerPort > 65535 {
+		setupLog.Error(fmt.Errorf("must be between 1 and 65535, got %d", defaultListenerPort), "invalid --default-listener-port")
+		os.Exit(1)
+	}
+
+	var ports []gatewayv1.PortNumber
+	var err error
+	if listenerPorts != "" {
+		if ports, err = parsePorts(listenerPorts); err != nil {
+			setupLog.Error(err, "invalid --listener-ports")
+			os.Exit(1)
+		}
+	}
+
+	var validatedNSSelector labels.Selector
+	if validatedNSLabelSelector != "" {
+		selector, err := labels.Parse(validatedNSLabelSelector)
+		if err != nil {
+			setupLog.Error(err, "invalid --validated-ns-label-selector")
+			os.Exit(1)
+		}
+		validatedNSSelector = selector
+	}
+
+	var hostnameIncludePattern *regexp.Regexp
+	if hostnameIncludeRegex != "" {
+		pattern, err := regexp.Compile(hostnameIncludeRegex)
+		if err != nil {
+			setupLog.Error(err, "invalid --hostname-include-regex")
+			os.Exit(1)
+		}
+		hostnameIncludePattern = pattern
+	}
+
+	var migrateFrom types.NamespacedName
+	if migrateFromGateway != "" {
+		migrateFrom, err = parseNamespacedName(migrateFromGateway)
+		if err != nil {
+			setupLog.Error(err, "invalid --migrate-from-gateway")
+			os.Exit(1)
+		}
+		if migrateFrom.Namespace == gatewayNamespace && migrateFrom.Name == gatewayName {
+			setupLog.Error(fmt.Errorf("must differ from the managed gateway"), "invalid --migrate-from-gateway")
+			os.Exit(1)
+		}
+	}
+
+	tlsOptions, err := controller.ParseTLSOptions(strings.Join(listenerTLSOptions, ","))
+	if err != nil {
+		setupLog.Error(err, "invalid --listener-tls-options")
+		os.Exit(1)
+	}
+
+	defaultHostnameTmpl, err := controller.ParseHostnameTemplate(defaultHostnameTemplate)
+	if err != nil {
+		setupLog.Error(err, "invalid --default-hostname-template")
+		os.Exit(1)
+	}
+
+	listenerNameTmpl, err := controller.ParseNameTemplate("listener name", listenerNameTemplate)
+	if err != nil {
+		setupLog.Error(err, "invalid --listener-name-template")
+		os.Exit(1)
+	}
+	secretNameTmpl, err := controller.ParseNameTemplate("secret name", secretNameTemplate)
+	if err != nil {
+		setupLog.Error(err, "invalid --secret-name-template")
+		os.Exit(1)
+	}
+
+	additionalGateways, err := parseNamespacedNames(additionalGatewaysFlag)
+	if err != nil {
+		setupLog.Error(err, "invalid --additional-gateways")
+		os.Exit(1)
+	}
+
+	secretNamespaces, err := parseNamespaces(allowedSecretNamespaces)
+	if err != nil {
+		setupLog.Error(err, "invalid --allowed-secret-namespaces")
+		os.Exit(1)
+	}
+
+	grantNamespaces, err := parseNamespaces(referenceGrantNamespaces)
+	if err != nil {
+		setupLog.Error(err, "invalid --referencegrant-allowed-namespaces")
+		os.Exit(1)
+	}
+
+	issuerKind, err := controller.ParseIssuerKind(forceIssuerKind)
+	if err != nil {
+		setupLog.Error(err, "invalid --force-issuer-kind")
+		os.Exit(1)
+	}
+
+	var configMap types.NamespacedName
+	cacheOpts := cache.Options{}
+	if configConfigMap != "" {
+		configMap, err = parseNamespacedName(configConfigMap)
+		if err != nil {
+			setupLog.Error(err, "invalid --config-configmap")
+			os.Exit(1)
+		}
+		// Only cache the one ConfigMap the controller watches
+		cacheOpts.ByObject = map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}: {
+				Namespaces: map[string]cache.Config{configMap.Namespace: {}},
+				Field:      fields.OneTermEqualSelector("metadata.name", configMap.Name),
+			},
+		}
+	}
+
+	if externalSecretCheck {
+		controller.AddExternalSecretToScheme(scheme)
+	}
+	if manageCertificates {
+		controller.AddCertificateToScheme(scheme)
+	}
+	if validateIssuer {
+		controller.AddIssuersToScheme(scheme)
+	}
+	if manageReferenceGrants {
+		utilruntime.Must(controller.AddReferenceGrantsToScheme(scheme))
+	}
+
+	// Each instance elects its own leader
+	leaderElectionID := "gateway-auto-listener.an0nfunc.github.io"
+	if instanceID != "" {
+		leaderElectionID = instanceID + "." + leaderElectionID
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
+		Cache:                  cacheOpts,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         true,
-		LeaderElectionID:       "gateway-auto-listener.an0nfunc.github.io",
+		LeaderElectionID:       leaderElectionID,
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
 		},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    webhookPort,
+			CertDir: webhookCertDir,
+		}),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
-	if err = (&controller.HTTPRouteReconciler{
-		Client:                     mgr.GetClient(),
-		Scheme:                     mgr.GetScheme(),
-		Recorder:                   mgr.GetEventRecorderFor("gateway-auto-listener"),
-		GatewayName:                gatewayName,
-		GatewayNamespace:           gatewayNamespace,
-		AllowedDomainSuffix:        allowedDomainSuffix,
-		ValidatedNSPrefix:          validatedNSPrefix,
-		AllowedHostnamesAnnotation: allowedHostnamesAnnotation,
-	}).SetupWithManager(mgr); err != nil {
+	reconciler := &controller.HTTPRouteReconciler{
+		Client:                       mgr.GetClient(),
+		Scheme:                       mgr.GetScheme(),
+		Recorder:                     mgr.GetEventRecorderFor("gateway-auto-listener"),
+		GatewayName:                  gatewayName,
+		GatewayNamespace:             gatewayNamespace,
+		AllowedDomainSuffix:          allowedDomainSuffix,
+		ValidatedNSPrefix:            validatedNSPrefix,
+		ValidatedNSSelector:          validatedNSSelector,
+		AllowedHostnamesAnnotation:   allowedHostnamesAnnotation,
+		StrictAllowedHostnames:       strictAllowedHostnames,
+		RejectHostnameClaimConflicts: rejectClaimConflicts,
+		MaxListenerNameLength:        maxListenerNameLength,
+		ReportNewListenerPorts:       reportNewListenerPorts,
+		ListenerPorts:                ports,
+		ValidationShadowMode:         validationShadowMode,
+		EnforceOnPolicyChange:        enforceOnPolicyChange,
+		RequeueAfterSuccess:          requeueAfterSuccess,
+		OnEmptyHostname:              onEmptyHostname,
+		ExternalSecretCheck:          externalSecretCheck,
+		MaxAllowedHostnames:          maxAllowedHostnames,
+		HostnameIncludePattern:       hostnameIncludePattern,
+		ConfigMap:                    configMap,
+		PatchStrategy:                patchStrategy,
+		EventTarget:                  eventTarget,
+		GatewayMutationRate:          gatewayMutationRate,
+		ReconcileSummaryLog:          reconcileSummaryLog,
+		AdditionalGateways:           additionalGateways,
+		SkipCertRefIfDefault:         skipCertRefIfDefault,
+		RecreateOnIssuerChange:       recreateOnIssuerChange,
+		DisambiguateSecretNames:      disambiguateSecretNames,
+		GatewayNotFoundRequeue:       gatewayNotFoundRequeue,
+		TenantAllowedRoutes:          gatewayv1.FromNamespaces(tenantAllowedRoutes),
+		PlatformAllowedRoutes:        gatewayv1.FromNamespaces(platformAllowedRoutes),
+		ObserveOnly:                  observeOnly,
+		AllowApex:                    allowApex,
+		InstanceID:                   instanceID,
+		ValidateListeners:            validateListeners,
+		DefaultListenerPort:          gatewayv1.PortNumber(defaultListenerPort),
+		AllowedRoutesFrom:            gatewayv1.FromNamespaces(allowedRoutesFrom),
+		SelectorAnnotation:           allowedRoutesSelector,
+		ManageCertificates:           manageCertificates,
+		DryRun:                       dryRun,
+		ListenerNameTemplate:         listenerNameTmpl,
+		SecretNameTemplate:           secretNameTmpl,
+		CreateHTTPRedirect:           createHTTPRedirect,
+		MaxListeners:                 maxListeners,
+		ManageGRPCRoutes:             manageGRPCRoutes,
+		SkipWildcardCovered:          skipWildcardCovered,
+		ListenerTLSOptions:           tlsOptions,
+		EventThrottle:                eventThrottle,
+		DefaultHostnameTemplate:      defaultHostnameTmpl,
+		ValidateIssuer:               validateIssuer,
+		CorrectListenerDrift:         correctListenerDrift,
+		AllowedSecretNamespaces:      secretNamespaces,
+		ForceIssuerKind:              issuerKind,
+		ManageReferenceGrants:        manageReferenceGrants,
+		ReferenceGrantNamespaces:     grantNamespaces,
+	}
+	if err = reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "HTTPRoute")
 		os.Exit(1)
 	}
+	if manageGRPCRoutes {
+		if err = (&controller.GRPCRouteReconciler{HTTPRouteReconciler: reconciler}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "GRPCRoute")
+			os.Exit(1)
+		}
+	}
+	if enableWebhook {
+		if err = (&controller.HostnameValidator{HTTPRouteReconciler: reconciler}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "HTTPRoute")
+			os.Exit(1)
+		}
+	}
+
+	if migrateFromGateway != "" && (observeOnly || dryRun) {
+		setupLog.Info("skipping gateway migration in observe-only or dry-run mode", "from", migrateFrom)
+	} else if migrateFromGateway != "" {
+		if err := mgr.Add(&controller.GatewayMigration{
+			Client:     mgr.GetClient(),
+			From:       migrateFrom,
+			To:         types.NamespacedName{Namespace: gatewayNamespace, Name: gatewayName},
+			InstanceID: instanceID,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up gateway migration")
+			os.Exit(1)
+		}
+	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -96,6 +519,12 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if enableWebhook {
+		if err := mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
+			setupLog.Error(err, "unable to set up webhook ready check")
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager", "version", version)
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -103,3 +532,72 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// parsePorts parses a comma-separated list of distinct port numbers.
+func parsePorts(value string) ([]gatewayv1.PortNumber, error) {
+	var ports []gatewayv1.PortNumber
+	seen := make(map[gatewayv1.PortNumber]bool)
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		port, err := strconv.ParseInt(field, 10, 32)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("%q is not a valid port", field)
+		}
+		if seen[gatewayv1.PortNumber(port)] {
+			return nil, fmt.Errorf("port %d listed more than once", port)
+		}
+		seen[gatewayv1.PortNumber(port)] = true
+		ports = append(ports, gatewayv1.PortNumber(port))
+	}
+	if len(ports) == 0 {
+		return nil, fmt.Errorf("at least one port is required")
+	}
+	return ports, nil
+}
+
+// parseNamespacedName parses a <namespace>/<name> reference.
+func parseNamespacedName(value string) (types.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(value, "/")
+	if !ok || namespace == "" || name == "" {
+		return types.NamespacedName{}, fmt.Errorf("expected <namespace>/<name>, got %q", value)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// parseNamespacedNames parses a comma-separated list of <namespace>/<name>
+// references. An empty value yields no references.
+func parseNamespacedNames(value string) ([]types.NamespacedName, error) {
+	var refs []types.NamespacedName
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		ref, err := parseNamespacedName(field)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// parseNamespaces parses a comma-separated list of namespace names. An empty
+// value yields none.
+func parseNamespaces(value string) ([]string, error) {
+	var namespaces []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if msgs := validation.IsDNS1123Label(field); len(msgs) > 0 {
+			return nil, fmt.Errorf("%q is not a valid namespace: %s", field, strings.Join(msgs, ", "))
+		}
+		namespaces = append(namespaces, field)
+	}
+	return namespaces, nil
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// allowedRoutesAnnotation overrides, per route, the AllowedRoutes scope of
+// its listeners with All, Same or Selector.
+const allowedRoutesAnnotation = "gateway-auto-listener/allowed-routes"
+
+// namespaceNameLabel is the label the API server sets on every namespace to
+// its own name.
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// allowedRoutesBreadth orders the scopes from widest to narrowest; routes in
+// validated namespaces may only narrow their scope.
+var allowedRoutesBreadth = []gatewayv1.FromNamespaces{
+	gatewayv1.NamespacesFromAll,
+	gatewayv1.NamespacesFromSelector,
+	gatewayv1.NamespacesFromSame,
+}
+
+// routeNamespaces returns the AllowedRoutes namespaces of the route's
+// listeners: TenantAllowedRoutes for validated namespaces and
+// PlatformAllowedRoutes otherwise, falling back to AllowedRoutesFrom, and
+// overridden by the route's allowed-routes annotation. Selector scopes read
+// their selector from SelectorAnnotation and are confined to the
+// route's own namespace for validated namespaces or when no selector is set.
+// Invalid overrides and selectors are reported and ignored.
+func (r *HTTPRouteReconciler) routeNamespaces(ctx context.Context, route client.Object) (*gatewayv1.RouteNamespaces, error) {
+	log := log.FromContext(ctx)
+
+	validated, err := r.requiresValidation(ctx, route.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	from := r.PlatformAllowedRoutes
+	if validated {
+		from = r.TenantAllowedRoutes
+	}
+	if from == "" {
+		from = r.AllowedRoutesFrom
+	}
+	if from == "" {
+		from = gatewayv1.NamespacesFromAll
+	}
+
+	if value, ok := route.GetAnnotations()[allowedRoutesAnnotation]; ok {
+		override := gatewayv1.FromNamespaces(value)
+		switch {
+		case !slices.Contains(allowedRoutesBreadth, override):
+			log.Info("ignoring invalid allowed-routes annotation", "allowedRoutes", value)
+			r.Recorder.Eventf(route, corev1.EventTypeWarning, "InvalidAllowedRoutes",
+				"annotation %s must be %s, %s or %s, got %q; using %s", allowedRoutesAnnotation,
+				gatewayv1.NamespacesFromAll, gatewayv1.NamespacesFromSame, gatewayv1.NamespacesFromSelector, value, from)
+		case validated && slices.Index(allowedRoutesBreadth, override) < slices.Index(allowedRoutesBreadth, from):
+			log.Info("ignoring allowed-routes annotation widening the tenant scope", "allowedRoutes", value, "scope", from)
+			r.Recorder.Eventf(route, corev1.EventTypeWarning, "InvalidAllowedRoutes",
+				"annotation %s may only narrow the allowed routes of namespace %s, got %s; using %s",
+				allowedRoutesAnnotation, route.GetNamespace(), value, from)
+		default:
+			from = override
+		}
+	}
+
+	namespaces := &gatewayv1.RouteNamespaces{From: &from}
+	if from != gatewayv1.NamespacesFromSelector {
+		return namespaces, nil
+	}
+
+	var selector *metav1.LabelSelector
+	if value, ok := route.GetAnnotations()[r.SelectorAnnotation]; ok && r.SelectorAnnotation != "" {
+		if selector, err = metav1.ParseToLabelSelector(value); err != nil {
+			log.Info("ignoring invalid allowed-routes selector", "selector", value, "reason", err.Error())
+			r.Recorder.Eventf(route, corev1.EventTypeWarning, "InvalidAllowedRoutes",
+				"annotation %s is not a label selector: %v; admitting routes from namespace %s only",
+				r.SelectorAnnotation, err, route.GetNamespace())
+			selector = nil
+		}
+	}
+	if selector == nil {
+		selector = &metav1.LabelSelector{}
+	}
+	if validated || len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0 {
+		if selector.MatchLabels == nil {
+			selector.MatchLabels = make(map[string]string)
+		}
+		selector.MatchLabels[namespaceNameLabel] = route.GetNamespace()
+	}
+	namespaces.Selector = selector
+	return namespaces, nil
+}
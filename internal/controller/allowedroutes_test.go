@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestRouteNamespaces(t *testing.T) {
+	const selectorAnnotation = "gateway-auto-listener/allowed-routes-selector"
+	ownNamespace := func(namespace string) *metav1.LabelSelector {
+		return &metav1.LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: namespace}}
+	}
+
+	tests := []struct {
+		name         string
+		namespace    string
+		defaultFrom  gatewayv1.FromNamespaces
+		tenantFrom   gatewayv1.FromNamespaces
+		annotations  map[string]string
+		wantFrom     gatewayv1.FromNamespaces
+		wantSelector *metav1.LabelSelector
+		wantEvent    bool
+	}{
+		{name: "default", namespace: "platform", wantFrom: gatewayv1.NamespacesFromAll},
+		{name: "same", namespace: "platform", defaultFrom: gatewayv1.NamespacesFromSame, wantFrom: gatewayv1.NamespacesFromSame},
+		{
+			name: "selector defaults to own namespace", namespace: "platform", defaultFrom: gatewayv1.NamespacesFromSelector,
+			wantFrom: gatewayv1.NamespacesFromSelector, wantSelector: ownNamespace("platform"),
+		},
+		{
+			name: "platform selector annotation", namespace: "platform", defaultFrom: gatewayv1.NamespacesFromSelector,
+			annotations: map[string]string{selectorAnnotation: "team=shop"},
+			wantFrom:    gatewayv1.NamespacesFromSelector, wantSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "shop"}},
+		},
+		{
+			name: "tenant selector confined to own namespace", namespace: "tenant-a", defaultFrom: gatewayv1.NamespacesFromSelector,
+			annotations: map[string]string{selectorAnnotation: "team=shop"},
+			wantFrom:    gatewayv1.NamespacesFromSelector,
+			wantSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+				"team": "shop", namespaceNameLabel: "tenant-a",
+			}},
+		},
+		{
+			name: "invalid selector", namespace: "platform", defaultFrom: gatewayv1.NamespacesFromSelector,
+			annotations: map[string]string{selectorAnnotation: "team in shop"},
+			wantFrom:    gatewayv1.NamespacesFromSelector, wantSelector: ownNamespace("platform"), wantEvent: true,
+		},
+		{
+			name: "override", namespace: "platform", defaultFrom: gatewayv1.NamespacesFromSame,
+			annotations: map[string]string{allowedRoutesAnnotation: "All"},
+			wantFrom:    gatewayv1.NamespacesFromAll,
+		},
+		{
+			name: "tenant override narrows", namespace: "tenant-a", tenantFrom: gatewayv1.NamespacesFromAll,
+			annotations: map[string]string{allowedRoutesAnnotation: "Selector"},
+			wantFrom:    gatewayv1.NamespacesFromSelector, wantSelector: ownNamespace("tenant-a"),
+		},
+		{
+			name: "tenant override cannot widen", namespace: "tenant-a", tenantFrom: gatewayv1.NamespacesFromSame,
+			annotations: map[string]string{allowedRoutesAnnotation: "All"},
+			wantFrom:    gatewayv1.NamespacesFromSame, wantEvent: true,
+		},
+		{
+			name: "invalid override", namespace: "platform",
+			annotations: map[string]string{allowedRoutesAnnotation: "Everywhere"},
+			wantFrom:    gatewayv1.NamespacesFromAll, wantEvent: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tt.namespace}}
+			r := newReconciler(ns)
+			r.AllowedRoutesFrom = tt.defaultFrom
+			r.TenantAllowedRoutes = tt.tenantFrom
+			r.SelectorAnnotation = selectorAnnotation
+			fakeRecorder := record.NewFakeRecorder(10)
+			r.Recorder = fakeRecorder
+			httpRoute := &gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{
+				Name: "app", Namespace: tt.namespace, Annotations: tt.annotations,
+			}}
+
+			namespaces, err := r.routeNamespaces(context.Background(), httpRoute)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if namespaces.From == nil || *namespaces.From != tt.wantFrom {
+				t.Errorf("expected from %s, got %v", tt.wantFrom, namespaces.From)
+			}
+			if (namespaces.Selector == nil) != (tt.wantSelector == nil) ||
+				metav1.FormatLabelSelector(namespaces.Selector) != metav1.FormatLabelSelector(tt.wantSelector) {
+				t.Errorf("expected selector %s, got %s", metav1.FormatLabelSelector(tt.wantSelector), metav1.FormatLabelSelector(namespaces.Selector))
+			}
+			events := eventsWithReason(drainEvents(fakeRecorder), "InvalidAllowedRoutes")
+			if tt.wantEvent != (len(events) == 1) {
+				t.Errorf("expected InvalidAllowedRoutes event: %v, got %v", tt.wantEvent, events)
+			}
+		})
+	}
+}
+
+func TestReconcile_AllowedRoutesSelector(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a"}}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "tenant-a",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				clusterIssuerAnnotation: "letsencrypt",
+				allowedRoutesAnnotation: "Selector",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.tenant-a.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, ns, httpRoute)
+	ctx := context.Background()
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "tenant-a"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 {
+		t.Fatalf("expected 1 listener, got %v", listenerNames(&gw))
+	}
+	namespaces := gw.Spec.Listeners[0].AllowedRoutes.Namespaces
+	if namespaces.From == nil || *namespaces.From != gatewayv1.NamespacesFromSelector {
+		t.Errorf("expected AllowedRoutes from Selector, got %v", namespaces.From)
+	}
+	if namespaces.Selector == nil || namespaces.Selector.MatchLabels[namespaceNameLabel] != "tenant-a" {
+		t.Errorf("expected selector on the route's namespace, got %v", namespaces.Selector)
+	}
+}
@@ -0,0 +1,425 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// certificateGVK is the cert-manager resource issuing a listener's
+// certificate Secret. It is handled as unstructured so cert-manager's API
+// module is not a dependency.
+var certificateGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// AddCertificateToScheme registers Certificate as an unstructured type with
+// the scheme.
+func AddCertificateToScheme(s *runtime.Scheme) {
+	s.AddKnownTypeWithName(certificateGVK, &unstructured.Unstructured{})
+	s.AddKnownTypeWithName(certificateGVK.GroupVersion().WithKind(certificateGVK.Kind+"List"), &unstructured.UnstructuredList{})
+}
+
+// certificateSpec returns the spec of the Certificate issuing the named
+// Secret for the hostnames, or nil when the route names no issuer. An Issuer
+// is looked up by cert-manager in the Certificate's, i.e. the Gateway's,
+// namespace. An invalid issuerRef is returned as an error.
+func (r *HTTPRouteReconciler) certificateSpec(httpRoute *gatewayv1.HTTPRoute, hostnames []string, secretName string) (map[string]any, error) {
+	kind, name, ok := r.issuerRef(httpRoute)
+	if !ok {
+		return nil, nil
+	}
+	if err := validateIssuerRef(kind, name); err != nil {
+		return nil, err
+	}
+	dnsNames := make([]any, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		dnsNames = append(dnsNames, hostname)
+	}
+	return map[string]any{
+		"secretName": secretName,
+		"dnsNames":   dnsNames,
+		"issuerRef": map[string]any{
+			"group": certificateGVK.Group,
+			"kind":  kind,
+			"name":  name,
+		},
+	}, nil
+}
+
+// ensureCertificates creates or updates the Certificates of the named
+// listeners on the Gateway when ManageCertificates is set, one per Secret,
+// covering the hostnames of every listener referencing it. Certificates not
+// labelled as managed were created by someone else and are left alone. The
+// Certificates of shared listeners, which the route tracks but another route
+// created, only get their owner reference dropped, see certificateOwner.
+func (r *HTTPRouteReconciler) ensureCertificates(ctx context.Context, httpRoute *gatewayv1.HTTPRoute,
+	gateway *gatewayv1.Gateway, names, shared map[string]bool) error {
+	if !r.ManageCertificates {
+		return nil
+	}
+	log := log.FromContext(ctx)
+	// A Secret named by the route is provisioned by whoever owns it. A
+	// refused namespace falls back to the Gateway's
+	if namespace, name, _ := routeSecretOverride(httpRoute); name != "" ||
+		namespace != gateway.Namespace && namespace != "" && r.secretNamespaceAllowed(gateway.Namespace, namespace) {
+		log.V(1).Info("not creating certificates: route overrides its TLS secret")
+		return nil
+	}
+
+	var secretNames, sharedSecrets []string
+	secretHostnames := make(map[string][]string)
+	for _, l := range gateway.Spec.Listeners {
+		if l.Hostname == nil || l.TLS == nil || len(l.TLS.CertificateRefs) == 0 {
+			continue
+		}
+		if !names[string(l.Name)] {
+			if secretName := string(l.TLS.CertificateRefs[0].Name); shared[string(l.Name)] && !slices.Contains(sharedSecrets, secretName) {
+				sharedSecrets = append(sharedSecrets, secretName)
+			}
+			continue
+		}
+		secretName := string(l.TLS.CertificateRefs[0].Name)
+		if _, ok := secretHostnames[secretName]; !ok {
+			secretNames = append(secretNames, secretName)
+		}
+		if hostname := string(*l.Hostname); !slices.Contains(secretHostnames[secretName], hostname) {
+			secretHostnames[secretName] = append(secretHostnames[secretName], hostname)
+		}
+	}
+
+	for _, secretName := range secretNames {
+		hostnames := secretHostnames[secretName]
+		sort.Strings(hostnames)
+		spec, err := r.certificateSpec(httpRoute, hostnames, secretName)
+		if err != nil {
+			log.Info("not creating certificate: invalid issuer", "secret", secretName, "reason", err.Error())
+			r.Recorder.Eventf(httpRoute, corev1.EventTypeWarning, "InvalidIssuerRef",
+				"no Certificate created for Secret %s: %v", secretName, err)
+			continue
+		}
+		if spec == nil {
+			log.V(1).Info("not creating certificate: route names no issuer", "secret", secretName)
+			continue
+		}
+
+		cert := &unstructured.Unstructured{}
+		cert.SetGroupVersionKind(certificateGVK)
+		err = r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: gateway.Namespace}, cert)
+		switch {
+		case apierrors.IsNotFound(err):
+			cert = &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+			cert.SetGroupVersionKind(certificateGVK)
+			cert.SetName(secretName)
+			cert.SetNamespace(gateway.Namespace)
+			cert.SetLabels(map[string]string{managedByLabel: managedByValue})
+			if httpRoute.Namespace == gateway.Namespace {
+				if err := controllerutil.SetControllerReference(httpRoute, cert, r.Scheme); err != nil {
+					return fmt.Errorf("failed to set owner of certificate %s: %w", secretName, err)
+				}
+			}
+			log.Info("creating certificate", "certificate", secretName, "hostnames", hostnames)
+			if err := r.Create(ctx, cert); err != nil {
+				return fmt.Errorf("failed to create certificate %s: %w", secretName, err)
+			}
+		case err != nil:
+			return fmt.Errorf("failed to get certificate %s: %w", secretName, err)
+		case cert.GetLabels()[managedByLabel] != managedByValue:
+			log.V(1).Info("certificate exists and is not managed", "certificate", secretName)
+		default:
+			disowned := r.disownSharedCertificate(httpRoute, cert)
+			if !disowned && reflect.DeepEqual(cert.Object["spec"], spec) {
+				continue
+			}
+			cert.Object["spec"] = spec
+			log.Info("updating certificate", "certificate", secretName, "hostnames", hostnames)
+			if err := r.Update(ctx, cert); err != nil {
+				return fmt.Errorf("failed to update certificate %s: %w", secretName, err)
+			}
+		}
+	}
+
+	for _, secretName := range sharedSecrets {
+		cert := &unstructured.Unstructured{}
+		cert.SetGroupVersionKind(certificateGVK)
+		if err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: gateway.Namespace}, cert); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get certificate %s: %w", secretName, err)
+		}
+		if cert.GetLabels()[managedByLabel] != managedByValue || !r.disownSharedCertificate(httpRoute, cert) {
+			continue
+		}
+		log.Info("certificate shared by several routes, leaving its deletion to their finalizers", "certificate", secretName)
+		if err := r.Update(ctx, cert); err != nil {
+			return fmt.Errorf("failed to update certificate %s: %w", secretName, err)
+		}
+	}
+	return nil
+}
+
+// disownSharedCertificate drops the owner reference of a Certificate the
+// route uses but does not own, reporting whether it did. A Certificate is
+// created with its route as controller owner when both share the Gateway
+// namespace, so it is garbage collected with the route. Owner references
+// cannot cross namespaces, nor count the other routes using a shared
+// listener, so once a second route uses the Certificate it is left to
+// pruneCertificates, which the routes' finalizers run.
+func (r *HTTPRouteReconciler) disownSharedCertificate(httpRoute *gatewayv1.HTTPRoute, cert *unstructured.Unstructured) bool {
+	refs := cert.GetOwnerReferences()
+	if len(refs) == 0 {
+		return false
+	}
+	if len(refs) == 1 && refs[0].Kind == "HTTPRoute" && refs[0].Name == httpRoute.Name &&
+		refs[0].UID == httpRoute.UID && cert.GetNamespace() == httpRoute.Namespace {
+		return false
+	}
+	cert.SetOwnerReferences(nil)
+	return true
+}
+
+// pruneCertificates deletes the managed Certificates of Secrets the removed
+// listeners referenced and no remaining listener still does, on this or any
+// other managed Gateway in the same namespace. Only Secrets in the Gateway's
+// namespace, where the Certificates live, are candidates.
+func (r *HTTPRouteReconciler) pruneCertificates(ctx context.Context, original, gateway *gatewayv1.Gateway) error {
+	if !r.ManageCertificates {
+		return nil
+	}
+
+	var candidates []types.NamespacedName
+	for _, l := range original.Spec.Listeners {
+		if l.TLS == nil {
+			continue
+		}
+		for _, ref := range l.TLS.CertificateRefs {
+			key := certificateRefKey(ref, original.Namespace)
+			if key.Namespace == gateway.Namespace && !slices.Contains(candidates, key) {
+				candidates = append(candidates, key)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	inUse := make(map[types.NamespacedName]bool)
+	markInUse := func(gw *gatewayv1.Gateway) {
+		for _, l := range gw.Spec.Listeners {
+			if l.TLS != nil {
+				for _, ref := range l.TLS.CertificateRefs {
+					inUse[certificateRefKey(ref, gw.Namespace)] = true
+				}
+			}
+		}
+	}
+	markInUse(gateway)
+	// The Certificate of a Secret lives in the Gateway's namespace, so
+	// another managed Gateway there may share it
+	for _, key := range r.managedGateways() {
+		if key.Namespace != gateway.Namespace || key.Name == gateway.Name {
+			continue
+		}
+		var other gatewayv1.Gateway
+		if err := r.Get(ctx, key, &other); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get gateway %s: %w", key, err)
+		}
+		markInUse(&other)
+	}
+
+	for _, key := range candidates {
+		if inUse[key] {
+			continue
+		}
+		if err := r.deleteCertificate(ctx, key.Namespace, key.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// certificateRefKey returns the namespace/name of the Secret a certificate
+// ref of a Gateway in gatewayNamespace points at.
+func certificateRefKey(ref gatewayv1.SecretObjectReference, gatewayNamespace string) types.NamespacedName {
+	key := types.NamespacedName{Name: string(ref.Name), Namespace: gatewayNamespace}
+	if ref.Namespace != nil {
+		key.Namespace = string(*ref.Namespace)
+	}
+	return key
+}
+
+// deleteCertificate deletes the named Certificate if it is managed.
+func (r *HTTPRouteReconciler) deleteCertificate(ctx context.Context, namespace, name string) error {
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(certificateGVK)
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, cert); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get certificate %s: %w", name, err)
+	}
+	if cert.GetLabels()[managedByLabel] != managedByValue {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("deleting certificate", "certificate", name)
+	if err := r.Delete(ctx, cert); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete certificate %s: %w", name, err)
+	}
+	return nil
+}
+
+// setCertificateReadyCondition mirrors the Ready condition of the managed
+// Certificates of the route's listeners into its CertificateReady condition,
+// so tenants see issuance progress on the route. Routes with no managed
+// Certificate, and dry-run routes, are left alone.
+func (r *HTTPRouteReconciler) setCertificateReadyCondition(ctx context.Context, httpRoute *gatewayv1.HTTPRoute) error {
+	if !r.ManageCertificates || r.isDryRun(httpRoute) {
+		return nil
+	}
+
+	var ready, pending []string
+	seen := make(map[types.NamespacedName]bool)
+	for _, key := range r.managedGateways() {
+		value := httpRoute.Annotations[r.managedHostnamesKey(key)]
+		if value == "" {
+			continue
+		}
+		names := strings.Split(value, ",")
+		var gateway gatewayv1.Gateway
+		if err := r.Get(ctx, key, &gateway); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get gateway %s: %w", key, err)
+		}
+		for _, l := range gateway.Spec.Listeners {
+			if !slices.Contains(names, string(l.Name)) || l.TLS == nil || len(l.TLS.CertificateRefs) == 0 {
+				continue
+			}
+			certKey := types.NamespacedName{Name: string(l.TLS.CertificateRefs[0].Name), Namespace: gateway.Namespace}
+			if seen[certKey] {
+				continue
+			}
+			seen[certKey] = true
+
+			cert := &unstructured.Unstructured{}
+			cert.SetGroupVersionKind(certificateGVK)
+			if err := r.Get(ctx, certKey, cert); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return fmt.Errorf("failed to get certificate %s: %w", certKey.Name, err)
+			}
+			if cert.GetLabels()[managedByLabel] != managedByValue {
+				continue
+			}
+			if status, message := certificateReady(cert); status {
+				ready = append(ready, certKey.Name)
+			} else {
+				pending = append(pending, fmt.Sprintf("%s (%s)", certKey.Name, message))
+			}
+		}
+	}
+	if len(ready) == 0 && len(pending) == 0 {
+		return nil
+	}
+	sort.Strings(ready)
+	sort.Strings(pending)
+
+	condition := metav1.Condition{
+		Type:    conditionCertificateReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonCertificateReady,
+		Message: "certificates ready: " + strings.Join(ready, ", "),
+	}
+	if len(pending) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonCertificateWait
+		condition.Message = "certificates not ready: " + strings.Join(pending, ", ")
+	}
+	return r.setRouteCondition(ctx, httpRoute, condition)
+}
+
+// certificateReady reports whether the Certificate's Ready condition is True,
+// with the condition's message otherwise.
+func certificateReady(cert *unstructured.Unstructured) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(cert.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		if condition["status"] == string(metav1.ConditionTrue) {
+			return true, ""
+		}
+		if message, _ := condition["message"].(string); message != "" {
+			return false, message
+		}
+		return false, "not ready"
+	}
+	return false, "not issued yet"
+}
+
+// certificateToHTTPRoutes maps a managed Certificate event to the routes using
+// it: the route owning it, and, as owner references cannot cross namespaces,
+// the routes tracking a listener on a managed Gateway that references its
+// Secret.
+func (r *HTTPRouteReconciler) certificateToHTTPRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	owners := make(map[types.NamespacedName]bool)
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind == "HTTPRoute" {
+			owners[types.NamespacedName{Name: ref.Name, Namespace: obj.GetNamespace()}] = true
+		}
+	}
+	listeners := make(map[types.NamespacedName][]string)
+	for _, key := range r.managedGateways() {
+		if key.Namespace != obj.GetNamespace() {
+			continue
+		}
+		var gateway gatewayv1.Gateway
+		if err := r.Get(ctx, key, &gateway); err != nil {
+			continue
+		}
+		for _, l := range gateway.Spec.Listeners {
+			if l.TLS != nil && len(l.TLS.CertificateRefs) > 0 && string(l.TLS.CertificateRefs[0].Name) == obj.GetName() {
+				listeners[key] = append(listeners[key], string(l.Name))
+			}
+		}
+	}
+
+	return r.managedRouteRequests(ctx, func(route *gatewayv1.HTTPRoute) bool {
+		if owners[client.ObjectKeyFromObject(route)] {
+			return true
+		}
+		for key, names := range listeners {
+			if value := route.Annotations[r.managedHostnamesKey(key)]; value != "" &&
+				slices.ContainsFunc(strings.Split(value, ","), func(name string) bool { return slices.Contains(names, name) }) {
+				return true
+			}
+		}
+		return false
+	})
+}
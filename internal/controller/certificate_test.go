@@ -0,0 +1,527 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func init() {
+	AddCertificateToScheme(scheme.Scheme)
+}
+
+func certificateRoute(annotations map[string]string) *gatewayv1.HTTPRoute {
+	return &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Finalizers:  []string{finalizerName},
+			Annotations: annotations,
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+}
+
+func emptyGateway() *gatewayv1.Gateway {
+	return &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+}
+
+func getCertificate(t *testing.T, r *HTTPRouteReconciler, name string) (*unstructured.Unstructured, bool) {
+	t.Helper()
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(certificateGVK)
+	err := r.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "nginx-gateway"}, cert)
+	if apierrors.IsNotFound(err) {
+		return nil, false
+	}
+	if err != nil {
+		t.Fatalf("failed to get certificate: %v", err)
+	}
+	return cert, true
+}
+
+func TestReconcile_ManageCertificates(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		forceKind   string
+		wantKind    string
+		wantIssuer  string
+	}{
+		{"cluster issuer", map[string]string{clusterIssuerAnnotation: "letsencrypt"}, "", "ClusterIssuer", "letsencrypt"},
+		{"issuer", map[string]string{issuerAnnotation: "local-ca"}, "", "Issuer", "local-ca"},
+		{"cluster issuer wins", map[string]string{clusterIssuerAnnotation: "letsencrypt", issuerAnnotation: "local-ca"}, "", "ClusterIssuer", "letsencrypt"},
+		{"forced issuer kind", map[string]string{clusterIssuerAnnotation: "local-ca"}, "Issuer", "Issuer", "local-ca"},
+		{"forced cluster issuer kind", map[string]string{issuerAnnotation: "letsencrypt"}, "ClusterIssuer", "ClusterIssuer", "letsencrypt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReconciler(emptyGateway(), certificateRoute(tt.annotations))
+			r.ManageCertificates = true
+			r.ForceIssuerKind = tt.forceKind
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+			if _, err := r.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			cert, ok := getCertificate(t, r, "app-example-com-tls")
+			if !ok {
+				t.Fatal("expected certificate to be created")
+			}
+			if cert.GetLabels()[managedByLabel] != managedByValue {
+				t.Errorf("expected managed-by label, got %v", cert.GetLabels())
+			}
+			secretName, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName")
+			if secretName != "app-example-com-tls" {
+				t.Errorf("expected secretName app-example-com-tls, got %q", secretName)
+			}
+			dnsNames, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+			if len(dnsNames) != 1 || dnsNames[0] != "app.example.com" {
+				t.Errorf("expected dnsNames [app.example.com], got %v", dnsNames)
+			}
+			kind, _, _ := unstructured.NestedString(cert.Object, "spec", "issuerRef", "kind")
+			name, _, _ := unstructured.NestedString(cert.Object, "spec", "issuerRef", "name")
+			group, _, _ := unstructured.NestedString(cert.Object, "spec", "issuerRef", "group")
+			if kind != tt.wantKind || name != tt.wantIssuer || group != "cert-manager.io" {
+				t.Errorf("expected issuerRef cert-manager.io %s/%s, got %s %s/%s", tt.wantKind, tt.wantIssuer, group, kind, name)
+			}
+		})
+	}
+}
+
+func TestReconcile_ManageCertificatesInvalidIssuer(t *testing.T) {
+	r := newReconciler(emptyGateway(), certificateRoute(map[string]string{clusterIssuerAnnotation: "Not_An_Issuer"}))
+	r.ManageCertificates = true
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := getCertificate(t, r, "app-example-com-tls"); ok {
+		t.Error("expected no certificate with an invalid issuerRef")
+	}
+	if events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "InvalidIssuerRef"); len(events) != 1 {
+		t.Errorf("expected one InvalidIssuerRef event, got %v", events)
+	}
+}
+
+func TestReconcile_ManageCertificatesDisabled(t *testing.T) {
+	r := newReconciler(emptyGateway(), certificateRoute(map[string]string{clusterIssuerAnnotation: "letsencrypt"}))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := getCertificate(t, r, "app-example-com-tls"); ok {
+		t.Error("expected no certificate without ManageCertificates")
+	}
+}
+
+func TestReconcile_ManageCertificatesIssuerChange(t *testing.T) {
+	route := certificateRoute(map[string]string{clusterIssuerAnnotation: "letsencrypt"})
+	r := newReconciler(emptyGateway(), route)
+	r.ManageCertificates = true
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var current gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &current)
+	current.Annotations[clusterIssuerAnnotation] = "letsencrypt-staging"
+	if err := r.Update(ctx, &current); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cert, _ := getCertificate(t, r, "app-example-com-tls")
+	name, _, _ := unstructured.NestedString(cert.Object, "spec", "issuerRef", "name")
+	if name != "letsencrypt-staging" {
+		t.Errorf("expected issuerRef to follow the annotation, got %q", name)
+	}
+}
+
+func TestReconcile_ManageCertificatesUnmanagedLeftAlone(t *testing.T) {
+	existing := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{"secretName": "app-example-com-tls", "dnsNames": []any{"custom.example.com"}},
+	}}
+	existing.SetGroupVersionKind(certificateGVK)
+	existing.SetName("app-example-com-tls")
+	existing.SetNamespace("nginx-gateway")
+
+	r := newReconciler(emptyGateway(), certificateRoute(map[string]string{clusterIssuerAnnotation: "letsencrypt"}), existing)
+	r.ManageCertificates = true
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cert, _ := getCertificate(t, r, "app-example-com-tls")
+	dnsNames, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+	if len(dnsNames) != 1 || dnsNames[0] != "custom.example.com" {
+		t.Errorf("expected unmanaged certificate to be left alone, got dnsNames %v", dnsNames)
+	}
+
+	// Deleting the route must not delete a certificate it did not create
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if err := r.Delete(ctx, &route); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := getCertificate(t, r, "app-example-com-tls"); !ok {
+		t.Error("expected unmanaged certificate to survive route deletion")
+	}
+}
+
+func TestReconcile_ManageCertificatesCleanup(t *testing.T) {
+	r := newReconciler(emptyGateway(), certificateRoute(map[string]string{clusterIssuerAnnotation: "letsencrypt"}))
+	r.ManageCertificates = true
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := getCertificate(t, r, "app-example-com-tls"); !ok {
+		t.Fatal("expected certificate to be created")
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if err := r.Delete(ctx, &route); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := getCertificate(t, r, "app-example-com-tls"); ok {
+		t.Error("expected certificate to be deleted with the route")
+	}
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected listener to be removed, got %v", listenerNames(&gw))
+	}
+}
+
+func TestReconcile_ManageCertificatesHostnameRemoved(t *testing.T) {
+	route := certificateRoute(map[string]string{clusterIssuerAnnotation: "letsencrypt"})
+	route.Spec.Hostnames = []gatewayv1.Hostname{"app.example.com", "api.example.com"}
+	r := newReconciler(emptyGateway(), route)
+	r.ManageCertificates = true
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var current gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &current)
+	current.Spec.Hostnames = []gatewayv1.Hostname{"app.example.com"}
+	if err := r.Update(ctx, &current); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := getCertificate(t, r, "api-example-com-tls"); ok {
+		t.Error("expected certificate of the dropped hostname to be deleted")
+	}
+	if _, ok := getCertificate(t, r, "app-example-com-tls"); !ok {
+		t.Error("expected certificate of the remaining hostname to be kept")
+	}
+}
+
+func TestReconcile_AggregateCert(t *testing.T) {
+	route := certificateRoute(map[string]string{clusterIssuerAnnotation: "letsencrypt", aggregateCertAnnotation: "true"})
+	route.Spec.Hostnames = []gatewayv1.Hostname{"www.example.com", "app.example.com", "api.example.com"}
+	r := newReconciler(emptyGateway(), route)
+	r.ManageCertificates = true
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	shared := r.aggregateSecretName([]string{"api.example.com", "app.example.com", "www.example.com"})
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 3 {
+		t.Fatalf("expected a listener per hostname, got %v", listenerNames(&gw))
+	}
+	for _, l := range gw.Spec.Listeners {
+		if ref := l.TLS.CertificateRefs[0].Name; string(ref) != shared {
+			t.Errorf("expected listener %s to reference %s, got %s", l.Name, shared, ref)
+		}
+	}
+	cert, ok := getCertificate(t, r, shared)
+	if !ok {
+		t.Fatalf("expected certificate %s to be created", shared)
+	}
+	dnsNames, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+	if !slices.Equal(dnsNames, []string{"api.example.com", "app.example.com", "www.example.com"}) {
+		t.Errorf("expected every hostname as a SAN, got %v", dnsNames)
+	}
+
+	// Dropping a hostname moves the listeners to the Secret of the new set
+	var current gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &current)
+	current.Spec.Hostnames = []gatewayv1.Hostname{"app.example.com", "www.example.com"}
+	if err := r.Update(ctx, &current); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	narrowed := r.aggregateSecretName([]string{"app.example.com", "www.example.com"})
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if names := listenerNames(&gw); !slices.Equal(names, []string{"https-app-example-com", "https-www-example-com"}) {
+		t.Fatalf("expected the dropped hostname's listener to be removed, got %v", names)
+	}
+	for _, l := range gw.Spec.Listeners {
+		if ref := l.TLS.CertificateRefs[0].Name; string(ref) != narrowed {
+			t.Errorf("expected listener %s to reference %s, got %s", l.Name, narrowed, ref)
+		}
+	}
+	if _, ok := getCertificate(t, r, shared); ok {
+		t.Error("expected the certificate of the previous hostname set to be deleted")
+	}
+	if _, ok := getCertificate(t, r, narrowed); !ok {
+		t.Errorf("expected certificate %s to be created", narrowed)
+	}
+
+	// Deleting the route removes the listeners and their certificate
+	_ = r.Get(ctx, req.NamespacedName, &current)
+	if err := r.Delete(ctx, &current); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected the listeners to be removed, got %v", listenerNames(&gw))
+	}
+	if _, ok := getCertificate(t, r, narrowed); ok {
+		t.Error("expected the shared certificate to be deleted with the route")
+	}
+}
+
+func TestReconcile_ManageCertificatesSharedAcrossGateways(t *testing.T) {
+	internal := emptyGateway()
+	internal.Name = "internal"
+	gatewayNamespace := gatewayv1.Namespace("nginx-gateway")
+	route := certificateRoute(map[string]string{clusterIssuerAnnotation: "letsencrypt"})
+	route.Spec.ParentRefs = []gatewayv1.ParentReference{
+		{Name: "default", Namespace: &gatewayNamespace},
+		{Name: "internal", Namespace: &gatewayNamespace},
+	}
+	r := newReconciler(emptyGateway(), internal, route)
+	r.AdditionalGateways = []types.NamespacedName{{Name: "internal", Namespace: "nginx-gateway"}}
+	r.ManageCertificates = true
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := getCertificate(t, r, "app-example-com-tls"); !ok {
+		t.Fatal("expected certificate to be created")
+	}
+
+	// Detaching from one Gateway leaves the listener, and the Certificate,
+	// on the other
+	var current gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &current)
+	current.Spec.ParentRefs = current.Spec.ParentRefs[:1]
+	if err := r.Update(ctx, &current); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "internal", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Fatalf("expected the listener to leave the detached gateway, got %v", listenerNames(&gw))
+	}
+	if _, ok := getCertificate(t, r, "app-example-com-tls"); !ok {
+		t.Error("expected the certificate still used by the other gateway to be kept")
+	}
+}
+
+func TestPruneCertificates_OtherNamespaceRef(t *testing.T) {
+	r := newReconciler(emptyGateway(), certificateRoute(map[string]string{clusterIssuerAnnotation: "letsencrypt"}))
+	r.ManageCertificates = true
+	ctx := context.Background()
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := getCertificate(t, r, "app-example-com-tls"); !ok {
+		t.Fatal("expected certificate to be created")
+	}
+
+	// A removed listener referencing a Secret of the same name in another
+	// namespace must not prune the Certificate in the Gateway's namespace
+	certs := gatewayv1.Namespace("certs")
+	original := emptyGateway()
+	original.Spec.Listeners = []gatewayv1.Listener{{
+		Name: "https-other",
+		TLS: &gatewayv1.ListenerTLSConfig{CertificateRefs: []gatewayv1.SecretObjectReference{
+			{Name: "app-example-com-tls", Namespace: &certs},
+		}},
+	}}
+	if err := r.pruneCertificates(ctx, original, emptyGateway()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := getCertificate(t, r, "app-example-com-tls"); !ok {
+		t.Error("expected the certificate in the gateway namespace to be kept")
+	}
+}
+
+func TestReconcile_ManageCertificatesOwnerReference(t *testing.T) {
+	issuer := map[string]string{clusterIssuerAnnotation: "letsencrypt"}
+	local := certificateRoute(issuer)
+	local.Namespace = "nginx-gateway"
+	shared := certificateRoute(issuer)
+	shared.Name = "shared"
+	shared.Namespace = "nginx-gateway"
+	remote := certificateRoute(issuer)
+	remote.Spec.Hostnames = []gatewayv1.Hostname{"remote.example.com"}
+	r := newReconciler(emptyGateway(), local, shared, remote)
+	r.ManageCertificates = true
+	ctx := context.Background()
+	reconcile := func(name, namespace string) {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}); err != nil {
+			t.Fatalf("unexpected error reconciling %s/%s: %v", namespace, name, err)
+		}
+	}
+
+	// A route in the Gateway namespace owns its Certificate, so it is
+	// garbage collected along with the route
+	reconcile("app", "nginx-gateway")
+	cert, ok := getCertificate(t, r, "app-example-com-tls")
+	if !ok {
+		t.Fatal("expected certificate to be created")
+	}
+	refs := cert.GetOwnerReferences()
+	if len(refs) != 1 || refs[0].Kind != "HTTPRoute" || refs[0].Name != "app" || refs[0].Controller == nil || !*refs[0].Controller {
+		t.Fatalf("expected the route as controller owner, got %v", refs)
+	}
+
+	// Once another route shares it, its deletion is left to the finalizers
+	reconcile("shared", "nginx-gateway")
+	cert, _ = getCertificate(t, r, "app-example-com-tls")
+	if refs := cert.GetOwnerReferences(); len(refs) != 0 {
+		t.Errorf("expected the owner reference of a shared certificate to be dropped, got %v", refs)
+	}
+
+	// A route in another namespace cannot own it; its finalizer deletes it
+	reconcile("app", "default")
+	cert, ok = getCertificate(t, r, "remote-example-com-tls")
+	if !ok {
+		t.Fatal("expected certificate to be created")
+	}
+	if refs := cert.GetOwnerReferences(); len(refs) != 0 {
+		t.Errorf("expected no owner reference across namespaces, got %v", refs)
+	}
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, types.NamespacedName{Name: "app", Namespace: "default"}, &route)
+	if err := r.Delete(ctx, &route); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	reconcile("app", "default")
+	if _, ok := getCertificate(t, r, "remote-example-com-tls"); ok {
+		t.Error("expected the finalizer to delete the certificate of the cross-namespace route")
+	}
+}
+
+func certificateReadyCondition(t *testing.T, r *HTTPRouteReconciler, key types.NamespacedName) *metav1.Condition {
+	t.Helper()
+	var route gatewayv1.HTTPRoute
+	if err := r.Get(context.Background(), key, &route); err != nil {
+		t.Fatalf("failed to get route: %v", err)
+	}
+	for _, parent := range route.Status.Parents {
+		if parent.ControllerName == controllerName {
+			return meta.FindStatusCondition(parent.Conditions, conditionCertificateReady)
+		}
+	}
+	return nil
+}
+
+func TestReconcile_CertificateReadyCondition(t *testing.T) {
+	r := newReconciler(emptyGateway(), certificateRoute(map[string]string{clusterIssuerAnnotation: "letsencrypt"}))
+	r.ManageCertificates = true
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	condition := certificateReadyCondition(t, r, req.NamespacedName)
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != reasonCertificateWait {
+		t.Fatalf("expected a CertificateNotReady condition before issuance, got %v", condition)
+	}
+
+	// cert-manager issues the certificate
+	cert, _ := getCertificate(t, r, "app-example-com-tls")
+	if err := unstructured.SetNestedSlice(cert.Object, []any{
+		map[string]any{"type": "Ready", "status": "True", "reason": "Ready"},
+	}, "status", "conditions"); err != nil {
+		t.Fatalf("failed to set certificate status: %v", err)
+	}
+	if err := r.Update(ctx, cert); err != nil {
+		t.Fatalf("failed to update certificate: %v", err)
+	}
+	requests := r.certificateToHTTPRoutes(ctx, cert)
+	if len(requests) != 1 || requests[0].NamespacedName != req.NamespacedName {
+		t.Fatalf("expected the certificate to map to its route, got %v", requests)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	condition = certificateReadyCondition(t, r, req.NamespacedName)
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != reasonCertificateReady {
+		t.Errorf("expected a CertificatesReady condition once issued, got %v", condition)
+	}
+	if condition != nil && condition.Message != "certificates ready: app-example-com-tls" {
+		t.Errorf("unexpected condition message %q", condition.Message)
+	}
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Keys read from the watched ConfigMap. Absent keys keep their current value.
+const (
+	configKeyAllowedDomainSuffix = "allowed-domain-suffix"
+	configKeyValidatedNSPrefix   = "validated-ns-prefix"
+)
+
+// validationPolicy returns the domain suffix and namespace prefix currently
+// in effect.
+func (r *HTTPRouteReconciler) validationPolicy() (suffix, prefix string) {
+	r.configMu.RLock()
+	defer r.configMu.RUnlock()
+	return r.AllowedDomainSuffix, r.ValidatedNSPrefix
+}
+
+// applyConfigMap updates the validation policy from the ConfigMap and reports
+// whether anything changed.
+func (r *HTTPRouteReconciler) applyConfigMap(cm *corev1.ConfigMap) bool {
+	r.configMu.Lock()
+	defer r.configMu.Unlock()
+
+	var changed bool
+	if suffix, ok := cm.Data[configKeyAllowedDomainSuffix]; ok && suffix != r.AllowedDomainSuffix {
+		r.AllowedDomainSuffix = suffix
+		changed = true
+	}
+	if prefix, ok := cm.Data[configKeyValidatedNSPrefix]; ok && prefix != r.ValidatedNSPrefix {
+		r.ValidatedNSPrefix = prefix
+		changed = true
+	}
+	return changed
+}
+
+// configMapToHTTPRoutes applies changes of the watched ConfigMap and, when the
+// policy changed, re-enqueues every managed route so it takes effect.
+func (r *HTTPRouteReconciler) configMapToHTTPRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	cm, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		return nil
+	}
+	if !r.applyConfigMap(cm) {
+		return nil
+	}
+
+	suffix, prefix := r.validationPolicy()
+	log.FromContext(ctx).Info("configuration changed, resyncing routes",
+		"configmap", client.ObjectKeyFromObject(cm), "allowedDomainSuffix", suffix, "validatedNSPrefix", prefix)
+	return r.managedRouteRequests(ctx, nil)
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestConfigMapToHTTPRoutes(t *testing.T) {
+	managed := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "tenant-acme",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+	}
+	unmanaged := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "tenant-acme"},
+	}
+
+	r := newReconciler(managed, unmanaged)
+	r.ConfigMap = types.NamespacedName{Namespace: "nginx-gateway", Name: "gateway-auto-listener"}
+	ctx := context.Background()
+
+	if err := r.validateHostname(ctx, "app.tenant-acme.example.com", "tenant-acme"); err != nil {
+		t.Fatalf("expected hostname to be allowed before the change, got: %v", err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "gateway-auto-listener", Namespace: "nginx-gateway"},
+		Data: map[string]string{
+			configKeyAllowedDomainSuffix: "example.org",
+		},
+	}
+	requests := r.configMapToHTTPRoutes(ctx, cm)
+	if len(requests) != 1 || requests[0].NamespacedName != (types.NamespacedName{Name: "app", Namespace: "tenant-acme"}) {
+		t.Errorf("expected the managed route to be re-enqueued, got %v", requests)
+	}
+
+	if err := r.validateHostname(ctx, "app.tenant-acme.example.com", "tenant-acme"); err == nil {
+		t.Error("expected the old suffix to be rejected after the change")
+	}
+	if err := r.validateHostname(ctx, "app.tenant-acme.example.org", "tenant-acme"); err != nil {
+		t.Errorf("expected the new suffix to be allowed, got: %v", err)
+	}
+	if suffix, prefix := r.validationPolicy(); suffix != "example.org" || prefix != "tenant-" {
+		t.Errorf("expected absent keys to keep their value, got suffix %q prefix %q", suffix, prefix)
+	}
+
+	// An unchanged ConfigMap does not trigger a resync
+	if requests := r.configMapToHTTPRoutes(ctx, cm); len(requests) != 0 {
+		t.Errorf("expected no resync without a change, got %v", requests)
+	}
+
+	cm.Data[configKeyValidatedNSPrefix] = "team-"
+	if requests := r.configMapToHTTPRoutes(ctx, cm); len(requests) != 1 {
+		t.Errorf("expected a resync after the prefix changed, got %v", requests)
+	}
+	if err := r.validateHostname(ctx, "anything.example.net", "tenant-acme"); err != nil {
+		t.Errorf("expected tenant-acme to no longer be validated, got: %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"maps"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Kinds of listener drift, used as the drift metric label.
+const (
+	driftHostname      = "hostname"
+	driftPort          = "port"
+	driftProtocol      = "protocol"
+	driftTLS           = "tls"
+	driftCertRef       = "cert-ref"
+	driftAllowedRoutes = "allowed-routes"
+)
+
+// correctListenerDrift patches the hostname, port, protocol, TLS mode and
+// options, certificate refs and allowed route namespaces of a managed
+// listener back to the desired listener and returns the kinds of drift it
+// corrected. This also carries changes of the route's annotations, such as
+// its TLS mode, protocol or allowed routes, to listeners that already exist.
+// The allowed route kinds are left as they are.
+func correctListenerDrift(listener *gatewayv1.Listener, desired gatewayv1.Listener) []string {
+	var kinds []string
+	if listener.Hostname == nil || *listener.Hostname != *desired.Hostname {
+		listener.Hostname = desired.Hostname
+		kinds = append(kinds, driftHostname)
+	}
+	if listener.Port != desired.Port {
+		listener.Port = desired.Port
+		kinds = append(kinds, driftPort)
+	}
+	if listener.Protocol != desired.Protocol {
+		listener.Protocol = desired.Protocol
+		kinds = append(kinds, driftProtocol)
+	}
+	if desired.AllowedRoutes != nil && desired.AllowedRoutes.Namespaces != nil {
+		if listener.AllowedRoutes == nil {
+			listener.AllowedRoutes = &gatewayv1.AllowedRoutes{}
+		}
+		if !equality.Semantic.DeepEqual(listener.AllowedRoutes.Namespaces, desired.AllowedRoutes.Namespaces) {
+			listener.AllowedRoutes.Namespaces = desired.AllowedRoutes.Namespaces
+			kinds = append(kinds, driftAllowedRoutes)
+		}
+	}
+
+	if listener.TLS == nil {
+		listener.TLS = &gatewayv1.ListenerTLSConfig{}
+	}
+	if listener.TLS.Mode == nil || *listener.TLS.Mode != *desired.TLS.Mode ||
+		!maps.Equal(listener.TLS.Options, desired.TLS.Options) {
+		listener.TLS.Mode = desired.TLS.Mode
+		listener.TLS.Options = desired.TLS.Options
+		kinds = append(kinds, driftTLS)
+	}
+	if !slices.EqualFunc(listener.TLS.CertificateRefs, desired.TLS.CertificateRefs, sameSecretRef) {
+		listener.TLS.CertificateRefs = desired.TLS.CertificateRefs
+		kinds = append(kinds, driftCertRef)
+	}
+	return kinds
+}
+
+// sameSecretRef compares certificate refs the way the API server defaults
+// them: an empty group, the Secret kind and the namespace of the desired
+// ref, which is the Gateway's.
+func sameSecretRef(ref, desired gatewayv1.SecretObjectReference) bool {
+	group := func(r gatewayv1.SecretObjectReference) gatewayv1.Group {
+		if r.Group == nil {
+			return ""
+		}
+		return *r.Group
+	}
+	kind := func(r gatewayv1.SecretObjectReference) gatewayv1.Kind {
+		if r.Kind == nil {
+			return "Secret"
+		}
+		return *r.Kind
+	}
+	namespace := func(r gatewayv1.SecretObjectReference) gatewayv1.Namespace {
+		if r.Namespace == nil {
+			return *desired.Namespace
+		}
+		return *r.Namespace
+	}
+	return ref.Name == desired.Name && group(ref) == group(desired) &&
+		kind(ref) == kind(desired) && namespace(ref) == namespace(desired)
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestCorrectListenerDrift_DefaultedRefs(t *testing.T) {
+	r := newReconciler()
+	desired := r.buildListener("https-app-example-com", "app.example.com", 443, "nginx-gateway", "app-example-com-tls", nil)
+	listener := *desired.DeepCopy()
+	// As read back from the API server
+	group := gatewayv1.Group("")
+	kind := gatewayv1.Kind("Secret")
+	listener.TLS.CertificateRefs[0].Group = &group
+	listener.TLS.CertificateRefs[0].Kind = &kind
+
+	if kinds := correctListenerDrift(&listener, desired); len(kinds) != 0 {
+		t.Errorf("expected no drift for API defaults, got %v", kinds)
+	}
+}
+
+func TestCorrectListenerDrift_ProtocolAndAllowedRoutes(t *testing.T) {
+	r := newReconciler()
+	listener := r.buildListener("https-app-example-com", "app.example.com", 443, "nginx-gateway", "app-example-com-tls", nil)
+	desired := *listener.DeepCopy()
+	desired.Protocol = gatewayv1.TLSProtocolType
+	same := gatewayv1.NamespacesFromSame
+	desired.AllowedRoutes.Namespaces = &gatewayv1.RouteNamespaces{From: &same}
+
+	kinds := correctListenerDrift(&listener, desired)
+	if len(kinds) != 2 || kinds[0] != driftProtocol || kinds[1] != driftAllowedRoutes {
+		t.Errorf("expected protocol and allowed-routes drift, got %v", kinds)
+	}
+	if listener.Protocol != gatewayv1.TLSProtocolType || *listener.AllowedRoutes.Namespaces.From != same {
+		t.Errorf("expected the listener updated, got protocol %s and allowed routes %+v",
+			listener.Protocol, listener.AllowedRoutes.Namespaces)
+	}
+}
+
+func TestReconcile_DriftCorrection(t *testing.T) {
+	tests := []struct {
+		kind  string
+		drift func(l *gatewayv1.Listener)
+	}{
+		{driftPort, func(l *gatewayv1.Listener) { l.Port = 8443 }},
+		{driftCertRef, func(l *gatewayv1.Listener) { l.TLS.CertificateRefs[0].Name = "hand-made-tls" }},
+		{driftTLS, func(l *gatewayv1.Listener) {
+			l.TLS.Options = map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{"example.com/ciphers": "legacy"}
+		}},
+		{driftHostname, func(l *gatewayv1.Listener) {
+			hostname := gatewayv1.Hostname("other.example.com")
+			l.Hostname = &hostname
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			r := newReconciler()
+			desired := r.buildListener("https-app-example-com", "app.example.com", 443, "nginx-gateway", "app-example-com-tls", nil)
+			drifted := *desired.DeepCopy()
+			tt.drift(&drifted)
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{drifted},
+				},
+			}
+			httpRoute := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "app",
+					Namespace:  "default",
+					Finalizers: []string{finalizerName},
+					Annotations: map[string]string{
+						"cert-manager.io/cluster-issuer": "letsencrypt",
+						managedHostnamesAnnotation:       "https-app-example-com",
+					},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"app.example.com"},
+				},
+			}
+
+			r = newReconciler(gateway, httpRoute)
+			counter := driftCorrectionsTotal.WithLabelValues(tt.kind)
+			before := counterValue(t, counter)
+			ctx := context.Background()
+			if _, err := r.Reconcile(ctx, ctrl.Request{
+				NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"},
+			}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := counterValue(t, counter) - before; got != 1 {
+				t.Errorf("expected %s drift counted once, got %v", tt.kind, got)
+			}
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			if len(gw.Spec.Listeners) != 1 {
+				t.Fatalf("expected 1 listener, got %v", listenerNames(&gw))
+			}
+			if kinds := correctListenerDrift(&gw.Spec.Listeners[0], desired); len(kinds) != 0 {
+				t.Errorf("expected listener restored, still drifted in %v", kinds)
+			}
+			if events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "ListenerDriftCorrected"); len(events) != 1 {
+				t.Errorf("expected one ListenerDriftCorrected event, got %v", events)
+			}
+		})
+	}
+}
+
+func TestReconcile_DriftCorrectionDisabled(t *testing.T) {
+	r := newReconciler()
+	drifted := r.buildListener("https-app-example-com", "app.example.com", 443, "nginx-gateway", "hand-made-tls", nil)
+	gateway := emptyGateway()
+	gateway.Spec.Listeners = []gatewayv1.Listener{drifted}
+	httpRoute := certificateRoute(map[string]string{
+		clusterIssuerAnnotation:    "letsencrypt",
+		managedHostnamesAnnotation: "https-app-example-com",
+	})
+
+	r = newReconciler(gateway, httpRoute)
+	r.CorrectListenerDrift = false
+	ctx := context.Background()
+	if _, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 || gw.Spec.Listeners[0].TLS.CertificateRefs[0].Name != "hand-made-tls" {
+		t.Errorf("expected the manual edit kept, got %+v", gw.Spec.Listeners)
+	}
+	if events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "ListenerDriftCorrected"); len(events) != 0 {
+		t.Errorf("expected no ListenerDriftCorrected events, got %v", events)
+	}
+}
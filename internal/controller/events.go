@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Objects listener lifecycle events are recorded on.
+const (
+	EventTargetRoute   = "route"
+	EventTargetGateway = "gateway"
+	EventTargetBoth    = "both"
+)
+
+// recordListenerEvent records a listener lifecycle event on the route, the
+// Gateway or both, according to EventTarget. Events on the Gateway name the
+// route they concern.
+func (r *HTTPRouteReconciler) recordListenerEvent(route client.Object, gateway *gatewayv1.Gateway, eventtype, reason, messageFmt string, args ...any) {
+	message := fmt.Sprintf(messageFmt, args...)
+	if r.EventTarget != EventTargetGateway {
+		r.Recorder.Event(route, eventtype, reason, message)
+	}
+	if r.EventTarget == EventTargetGateway || r.EventTarget == EventTargetBoth {
+		r.Recorder.Eventf(gateway, eventtype, reason, "%s (route %s/%s)", message, route.GetNamespace(), route.GetName())
+	}
+}
+
+// eventThrottleKey identifies a hostname event of a route. Listener events
+// also carry the Gateway and reason, so each conflict is throttled on its own.
+type eventThrottleKey struct {
+	route    types.NamespacedName
+	hostname string
+	gateway  types.NamespacedName
+	reason   string
+}
+
+// eventThrottle remembers when a hostname event of a route was last
+// recorded, so a rejection repeated on every reconcile is recorded at most
+// once per interval.
+type eventThrottle struct {
+	mu   sync.Mutex
+	last map[eventThrottleKey]time.Time
+}
+
+// allow reports whether the event may be recorded at now, noting it if so. A
+// non-positive interval always allows.
+func (t *eventThrottle) allow(key eventThrottleKey, interval time.Duration, now time.Time) bool {
+	if interval <= 0 {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if last, ok := t.last[key]; ok && now.Sub(last) < interval {
+		return false
+	}
+	if t.last == nil {
+		t.last = make(map[eventThrottleKey]time.Time)
+	}
+	t.last[key] = now
+	return true
+}
+
+// forget drops the entries of a deleted or released route.
+func (t *eventThrottle) forget(route types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key := range t.last {
+		if key.route == route {
+			delete(t.last, key)
+		}
+	}
+}
+
+// allowHostnameEvent reports whether a validation event for the route's
+// hostname is due, according to EventThrottle.
+func (r *HTTPRouteReconciler) allowHostnameEvent(route client.Object, hostname string) bool {
+	key := eventThrottleKey{route: client.ObjectKeyFromObject(route), hostname: hostname}
+	return r.eventThrottle.allow(key, r.EventThrottle, time.Now())
+}
+
+// allowListenerEvent reports whether a listener event with reason for the
+// route's hostname on gateway is due, according to EventThrottle. It throttles
+// warnings a reconcile would otherwise repeat every time, such as a listener
+// name held by another hostname.
+func (r *HTTPRouteReconciler) allowListenerEvent(route client.Object, gateway *gatewayv1.Gateway, hostname, reason string) bool {
+	key := eventThrottleKey{
+		route:    client.ObjectKeyFromObject(route),
+		hostname: hostname,
+		gateway:  client.ObjectKeyFromObject(gateway),
+		reason:   reason,
+	}
+	return r.eventThrottle.allow(key, r.EventThrottle, time.Now())
+}
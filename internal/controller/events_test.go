@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestReconcile_EventTarget(t *testing.T) {
+	// Events on the Gateway are told apart by the route they name
+	const (
+		onRoute   = "Normal ListenerCreated created listener https-app-example-com for hostname app.example.com"
+		onGateway = "Normal ListenerCreated created listener https-app-example-com for hostname app.example.com (route default/app)"
+	)
+
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{"", []string{onRoute}},
+		{EventTargetRoute, []string{onRoute}},
+		{EventTargetGateway, []string{onGateway}},
+		{EventTargetBoth, []string{onRoute, onGateway}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{},
+				},
+			}
+			httpRoute := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "app",
+					Namespace:  "default",
+					Finalizers: []string{finalizerName},
+					Annotations: map[string]string{
+						"cert-manager.io/cluster-issuer": "letsencrypt",
+					},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"app.example.com"},
+				},
+			}
+
+			r := newReconciler(gateway, httpRoute)
+			r.EventTarget = tt.target
+			fakeRecorder := record.NewFakeRecorder(10)
+			r.Recorder = fakeRecorder
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := eventsWithReason(drainEvents(fakeRecorder), "ListenerCreated"); !slices.Equal(got, tt.want) {
+				t.Errorf("expected events %v, got %v", tt.want, got)
+			}
+
+			// Removal is mirrored the same way
+			var route gatewayv1.HTTPRoute
+			_ = r.Get(ctx, req.NamespacedName, &route)
+			if err := r.Delete(ctx, &route); err != nil {
+				t.Fatalf("failed to delete route: %v", err)
+			}
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			removed := eventsWithReason(drainEvents(fakeRecorder), "ListenerRemoved")
+			if len(removed) != len(tt.want) {
+				t.Errorf("expected %d ListenerRemoved events, got %v", len(tt.want), removed)
+			}
+		})
+	}
+}
+
+func TestEventThrottle(t *testing.T) {
+	var throttle eventThrottle
+	key := eventThrottleKey{route: types.NamespacedName{Namespace: "tenant-acme", Name: "app"}, hostname: "evil.com"}
+	other := eventThrottleKey{route: key.route, hostname: "other.com"}
+	now := time.Now()
+
+	if !throttle.allow(key, time.Minute, now) {
+		t.Error("expected the first event to be allowed")
+	}
+	if throttle.allow(key, time.Minute, now.Add(30*time.Second)) {
+		t.Error("expected a repeat within the interval to be throttled")
+	}
+	if !throttle.allow(other, time.Minute, now.Add(30*time.Second)) {
+		t.Error("expected another hostname to be throttled separately")
+	}
+	if !throttle.allow(key, time.Minute, now.Add(time.Minute)) {
+		t.Error("expected the event to be allowed once the interval passed")
+	}
+	if !throttle.allow(key, 0, now.Add(time.Minute)) {
+		t.Error("expected a zero interval to never throttle")
+	}
+
+	throttle.forget(key.route)
+	if !throttle.allow(key, time.Minute, now.Add(time.Minute)) {
+		t.Error("expected a forgotten route to be allowed again")
+	}
+}
+
+func TestReconcile_EventThrottle(t *testing.T) {
+	for _, tt := range []struct {
+		throttle time.Duration
+		want     int
+	}{
+		{0, 3},
+		{time.Hour, 1},
+	} {
+		t.Run(tt.throttle.String(), func(t *testing.T) {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-acme"}}
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{},
+				},
+			}
+			httpRoute := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "app",
+					Namespace:   "tenant-acme",
+					Finalizers:  []string{finalizerName},
+					Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"evil.com"},
+				},
+			}
+
+			r := newReconciler(ns, gateway, httpRoute)
+			r.EventThrottle = tt.throttle
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "tenant-acme"}}
+
+			for i := range 3 {
+				// Unrelated patches of the shared Gateway trigger a full reconcile
+				var gw gatewayv1.Gateway
+				_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+				gw.Annotations = map[string]string{"example.com/revision": string(rune('a' + i))}
+				if err := r.Update(ctx, &gw); err != nil {
+					t.Fatalf("failed to update gateway: %v", err)
+				}
+				if _, err := r.Reconcile(ctx, req); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			failed := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "HostnameValidationFailed")
+			if len(failed) != tt.want {
+				t.Errorf("expected %d HostnameValidationFailed events, got %v", tt.want, failed)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// secretSyncRequeueInterval is how long to wait before checking pending
+// ExternalSecrets again.
+const secretSyncRequeueInterval = 30 * time.Second
+
+// externalSecretGVK is the External Secrets Operator resource syncing a
+// listener's certificate Secret. It is handled as unstructured so the
+// operator's API module is not a dependency.
+var externalSecretGVK = schema.GroupVersionKind{
+	Group:   "external-secrets.io",
+	Version: "v1",
+	Kind:    "ExternalSecret",
+}
+
+// AddExternalSecretToScheme registers ExternalSecret as an unstructured type
+// with the scheme.
+func AddExternalSecretToScheme(s *runtime.Scheme) {
+	s.AddKnownTypeWithName(externalSecretGVK, &unstructured.Unstructured{})
+	s.AddKnownTypeWithName(externalSecretGVK.GroupVersion().WithKind(externalSecretGVK.Kind+"List"), &unstructured.UnstructuredList{})
+}
+
+// externalSecretSynced reports whether the ExternalSecret producing the named
+// Secret in the Gateway namespace exists and is Ready.
+func (r *HTTPRouteReconciler) externalSecretSynced(ctx context.Context, namespace, secretName string) (bool, error) {
+	es := &unstructured.Unstructured{}
+	es.SetGroupVersionKind(externalSecretGVK)
+	if err := r.Get(ctx, types.NamespacedName{Name: secretName, Namespace: namespace}, es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get externalsecret %s: %w", secretName, err)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(es.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		if condition["type"] == "Ready" {
+			return condition["status"] == "True", nil
+		}
+	}
+	return false, nil
+}
+
+// updateSecretSyncCondition reports on the route whether listeners are held
+// back by ExternalSecrets that have not synced yet.
+func (r *HTTPRouteReconciler) updateSecretSyncCondition(ctx context.Context, httpRoute *gatewayv1.HTTPRoute, pendingSecrets []string) error {
+	condition := metav1.Condition{
+		Type:    conditionSecretSyncPending,
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonSecretsSynced,
+		Message: "all certificate ExternalSecrets are synced",
+	}
+	if len(pendingSecrets) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonSecretNotSynced
+		condition.Message = fmt.Sprintf("waiting for ExternalSecrets to sync: %s", strings.Join(pendingSecrets, ", "))
+	}
+	return r.setRouteCondition(ctx, httpRoute, condition)
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func init() {
+	AddExternalSecretToScheme(scheme.Scheme)
+}
+
+func externalSecret(name, ready string) *unstructured.Unstructured {
+	es := &unstructured.Unstructured{}
+	es.SetGroupVersionKind(externalSecretGVK)
+	es.SetName(name)
+	es.SetNamespace("nginx-gateway")
+	if ready != "" {
+		_ = unstructured.SetNestedSlice(es.Object, []any{
+			map[string]any{"type": "Ready", "status": ready},
+		}, "status", "conditions")
+	}
+	return es
+}
+
+func externalSecretRoute() *gatewayv1.HTTPRoute {
+	return &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+}
+
+func TestReconcile_ExternalSecretCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		externalSecret client.Object
+		wantListeners  int
+		wantPending    metav1.ConditionStatus
+	}{
+		{"missing", nil, 0, metav1.ConditionTrue},
+		{"not ready", externalSecret("app-example-com-tls", "False"), 0, metav1.ConditionTrue},
+		{"no status", externalSecret("app-example-com-tls", ""), 0, metav1.ConditionTrue},
+		{"synced", externalSecret("app-example-com-tls", "True"), 1, metav1.ConditionFalse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{},
+				},
+			}
+			objs := []client.Object{gateway, externalSecretRoute()}
+			if tt.externalSecret != nil {
+				objs = append(objs, tt.externalSecret)
+			}
+
+			r := newReconciler(objs...)
+			r.ExternalSecretCheck = true
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+			result, err := r.Reconcile(ctx, req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			wantRequeue := tt.wantPending == metav1.ConditionTrue
+			if (result.RequeueAfter == secretSyncRequeueInterval) != wantRequeue {
+				t.Errorf("expected requeue %v, got RequeueAfter %s", wantRequeue, result.RequeueAfter)
+			}
+
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			if len(gw.Spec.Listeners) != tt.wantListeners {
+				t.Errorf("expected %d listeners, got %d", tt.wantListeners, len(gw.Spec.Listeners))
+			}
+
+			var route gatewayv1.HTTPRoute
+			_ = r.Get(ctx, req.NamespacedName, &route)
+			if len(route.Status.Parents) != 1 || route.Status.Parents[0].ControllerName != controllerName {
+				t.Fatalf("expected one parent status owned by the controller, got %v", route.Status.Parents)
+			}
+			condition := meta.FindStatusCondition(route.Status.Parents[0].Conditions, conditionSecretSyncPending)
+			if condition == nil || condition.Status != tt.wantPending {
+				t.Errorf("expected %s condition %s, got %v", conditionSecretSyncPending, tt.wantPending, condition)
+			}
+		})
+	}
+}
+
+func TestReconcile_ExternalSecretSyncsLater(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	es := externalSecret("app-example-com-tls", "False")
+
+	r := newReconciler(gateway, externalSecretRoute(), es)
+	r.ExternalSecretCheck = true
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_ = unstructured.SetNestedSlice(es.Object, []any{
+		map[string]any{"type": "Ready", "status": "True"},
+	}, "status", "conditions")
+	if err := r.Status().Update(ctx, es); err != nil {
+		t.Fatalf("failed to update externalsecret: %v", err)
+	}
+
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.RequeueAfter != 0 {
+		t.Errorf("expected no requeue once synced, got %s", result.RequeueAfter)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 {
+		t.Errorf("expected listener once the externalsecret synced, got %d", len(gw.Spec.Listeners))
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if !meta.IsStatusConditionFalse(route.Status.Parents[0].Conditions, conditionSecretSyncPending) {
+		t.Errorf("expected %s to be cleared, got %v", conditionSecretSyncPending, route.Status.Parents[0].Conditions)
+	}
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// fingerprintCache remembers, per route, the fingerprint of the inputs of its
+// last clean reconcile. The fingerprint includes the route UID, so a route
+// recreated under the same name never matches.
+type fingerprintCache struct {
+	mu           sync.Mutex
+	fingerprints map[types.NamespacedName]string
+}
+
+// unchanged reports whether the route was last reconciled cleanly with the
+// same fingerprint.
+func (c *fingerprintCache) unchanged(key types.NamespacedName, fingerprint string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fp, ok := c.fingerprints[key]
+	return ok && fp == fingerprint
+}
+
+func (c *fingerprintCache) store(key types.NamespacedName, fingerprint string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fingerprints == nil {
+		c.fingerprints = make(map[types.NamespacedName]string)
+	}
+	c.fingerprints[key] = fingerprint
+}
+
+func (c *fingerprintCache) forget(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.fingerprints, key)
+}
+
+// reconcileFingerprint hashes everything a reconcile of the route depends on:
+// the route and the managed Gateways (by resourceVersion), the route's namespace,
+// which carries the hostname policy, the namespaces owning the claims on its
+// hostnames, and the runtime-configurable policy. Any change to these yields a
+// different fingerprint.
+func (r *HTTPRouteReconciler) reconcileFingerprint(ctx context.Context, httpRoute *gatewayv1.HTTPRoute) (string, error) {
+	var gatewayVersions []string
+	for _, key := range r.managedGateways() {
+		var gateway gatewayv1.Gateway
+		if err := r.getGateway(ctx, key, &gateway); err != nil {
+			if key != r.primaryGateway() && errors.Is(err, errGatewayNotFound) {
+				continue
+			}
+			return "", err
+		}
+		gatewayVersions = append(gatewayVersions, gateway.ResourceVersion)
+	}
+	var ns corev1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: httpRoute.Namespace}, &ns); err != nil && !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get namespace: %w", err)
+	}
+	// Another namespace claiming or releasing a hostname changes the verdict
+	// on a validated namespace's routes without touching the namespace
+	var owners []string
+	validated, err := r.requiresValidation(ctx, httpRoute.Namespace)
+	if err != nil {
+		return "", err
+	}
+	if validated && r.AllowedHostnamesAnnotation != "" && r.RejectHostnameClaimConflicts {
+		for _, hostname := range httpRoute.Spec.Hostnames {
+			normalized, err := normalizeHostname(string(hostname))
+			if hostname == "" || err != nil {
+				continue
+			}
+			owner, err := r.hostnameClaimOwner(ctx, normalized)
+			if err != nil {
+				return "", err
+			}
+			owners = append(owners, normalized+"="+owner)
+		}
+	}
+	suffix, prefix := r.validationPolicy()
+
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s\x00%s\x00%s\x00%s\x00%s\x00%s\x00%s",
+		httpRoute.UID, httpRoute.ResourceVersion, strings.Join(gatewayVersions, ","), ns.ResourceVersion,
+		strings.Join(owners, ","), suffix, prefix))
+	return hex.EncodeToString(sum[:]), nil
+}
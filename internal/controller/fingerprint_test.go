@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestReconcile_UnchangedRouteSkipsWork(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "default",
+			UID:        "app-uid",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+
+	var gatewayWrites, routeWrites int
+	r := newReconciler()
+	r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(gateway, httpRoute).WithStatusSubresource(httpRoute).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if _, ok := obj.(*gatewayv1.Gateway); ok {
+					gatewayWrites++
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				if _, ok := obj.(*gatewayv1.HTTPRoute); ok {
+					routeWrites++
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}).Build()
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	for range 5 {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if gatewayWrites != 1 {
+		t.Errorf("expected exactly one gateway write, got %d", gatewayWrites)
+	}
+	if routeWrites != 1 {
+		t.Errorf("expected exactly one route write, got %d", routeWrites)
+	}
+
+	// A changed gateway is reconciled again and repairs the listener
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	gw.Spec.Listeners = nil
+	if err := r.Client.Update(ctx, &gw); err != nil {
+		t.Fatalf("failed to update gateway: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 {
+		t.Errorf("expected the deleted listener to be restored, got %d listeners", len(gw.Spec.Listeners))
+	}
+	if gatewayWrites != 2 {
+		t.Errorf("expected a second gateway write after the change, got %d", gatewayWrites)
+	}
+}
+
+func TestReconcileFingerprint_ClaimOwner(t *testing.T) {
+	now := time.Now()
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "tenant-b", UID: "app-uid"},
+		Spec:       gatewayv1.HTTPRouteSpec{Hostnames: []gatewayv1.Hostname{"app.shared.org"}},
+	}
+	r := newReconciler(emptyGateway(), httpRoute,
+		claimingNamespace("tenant-b", now, "shared.org"),
+		claimingNamespace("tenant-a", now.Add(-time.Hour), "other.org"),
+	)
+	ctx := context.Background()
+
+	before, err := r.reconcileFingerprint(ctx, httpRoute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// An older namespace claiming the hostname takes it over
+	var older corev1.Namespace
+	_ = r.Get(ctx, types.NamespacedName{Name: "tenant-a"}, &older)
+	older.Annotations["gateway-auto-listener/allowed-hostnames"] = "app.shared.org"
+	if err := r.Update(ctx, &older); err != nil {
+		t.Fatalf("failed to update namespace: %v", err)
+	}
+	after, err := r.reconcileFingerprint(ctx, httpRoute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if before == after {
+		t.Error("expected the fingerprint to change with the hostname's claim owner")
+	}
+}
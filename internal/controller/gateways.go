@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// maxAnnotationNameLength is the limit on the name part of an annotation key.
+const maxAnnotationNameLength = 63
+
+// gatewayAnnotation selects, as <namespace>/<name>, the managed Gateway a
+// route's listeners are provisioned on, overriding its parentRefs.
+const gatewayAnnotation = "gateway-auto-listener/gateway"
+
+// errGatewayNotFound marks a managed Gateway that does not exist.
+var errGatewayNotFound = errors.New("gateway not found")
+
+// primaryGateway returns the Gateway named by GatewayName and
+// GatewayNamespace, which routes without parentRefs are provisioned on.
+func (r *HTTPRouteReconciler) primaryGateway() types.NamespacedName {
+	return types.NamespacedName{Name: r.GatewayName, Namespace: r.GatewayNamespace}
+}
+
+// managedGateways returns every Gateway the controller manages, the primary
+// Gateway first.
+func (r *HTTPRouteReconciler) managedGateways() []types.NamespacedName {
+	gateways := []types.NamespacedName{r.primaryGateway()}
+	for _, gw := range r.AdditionalGateways {
+		if !slices.Contains(gateways, gw) {
+			gateways = append(gateways, gw)
+		}
+	}
+	return gateways
+}
+
+// routeGateways returns the managed Gateways the route attaches to, in
+// managedGateways order. A route with the gateway annotation attaches to the
+// Gateway it names, or to none when that is malformed or not managed. Routes
+// without parentRefs attach to the primary Gateway; otherwise only
+// Gateway-kind parentRefs count, so mesh parentRefs such as Services are
+// ignored.
+func (r *HTTPRouteReconciler) routeGateways(httpRoute *gatewayv1.HTTPRoute) []types.NamespacedName {
+	return r.parentGateways(httpRoute, httpRoute.Spec.ParentRefs)
+}
+
+// parentGateways returns the managed Gateways a route of any kind attaches
+// to, given its parentRefs; see routeGateways.
+func (r *HTTPRouteReconciler) parentGateways(route client.Object, parentRefs []gatewayv1.ParentReference) []types.NamespacedName {
+	if key, ok, err := routeGatewayAnnotation(route); ok || err != nil {
+		if err != nil || !slices.Contains(r.managedGateways(), key) {
+			return nil
+		}
+		return []types.NamespacedName{key}
+	}
+	if len(parentRefs) == 0 {
+		return []types.NamespacedName{r.primaryGateway()}
+	}
+
+	referenced := make(map[types.NamespacedName]bool)
+	for _, ref := range parentRefs {
+		if ref.Group != nil && *ref.Group != gatewayv1.GroupName {
+			continue
+		}
+		if ref.Kind != nil && *ref.Kind != "Gateway" {
+			continue
+		}
+		namespace := route.GetNamespace()
+		if ref.Namespace != nil {
+			namespace = string(*ref.Namespace)
+		}
+		referenced[types.NamespacedName{Name: string(ref.Name), Namespace: namespace}] = true
+	}
+
+	var gateways []types.NamespacedName
+	for _, gw := range r.managedGateways() {
+		if referenced[gw] {
+			gateways = append(gateways, gw)
+		}
+	}
+	return gateways
+}
+
+// routeGatewayAnnotation parses the route's gateway annotation, reporting
+// whether it is set.
+func routeGatewayAnnotation(route client.Object) (types.NamespacedName, bool, error) {
+	value, ok := route.GetAnnotations()[gatewayAnnotation]
+	if !ok {
+		return types.NamespacedName{}, false, nil
+	}
+	namespace, name, found := strings.Cut(value, "/")
+	if !found || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return types.NamespacedName{}, false, fmt.Errorf("annotation %s must be <namespace>/<name>, got %q", gatewayAnnotation, value)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, true, nil
+}
+
+// checkGatewayAnnotation records a warning event when the route's gateway
+// annotation is malformed or names a Gateway the controller does not manage.
+func (r *HTTPRouteReconciler) checkGatewayAnnotation(ctx context.Context, httpRoute *gatewayv1.HTTPRoute) {
+	key, ok, err := routeGatewayAnnotation(httpRoute)
+	if err == nil && (!ok || slices.Contains(r.managedGateways(), key)) {
+		return
+	}
+	if err == nil {
+		err = fmt.Errorf("annotation %s names gateway %s, which is not managed", gatewayAnnotation, key)
+	}
+	log.FromContext(ctx).Info("route gateway annotation ignored", "reason", err.Error())
+	r.Recorder.Eventf(httpRoute, corev1.EventTypeWarning, "InvalidGatewayAnnotation", "%v", err)
+}
+
+// managedHostnamesKey returns the route annotation tracking the listeners
+// the route owns on the Gateway. The primary Gateway keeps the plain
+// managed-hostnames annotation; other Gateways get one qualified with their
+// namespace and name. Either is further scoped to the InstanceID.
+func (r *HTTPRouteReconciler) managedHostnamesKey(gateway types.NamespacedName) string {
+	if gateway == r.primaryGateway() {
+		return instanceKey(managedHostnamesAnnotation, r.InstanceID)
+	}
+	prefix, name, _ := strings.Cut(managedHostnamesAnnotation, "/")
+	return instanceKey(prefix+"/"+truncateName(name+"."+gateway.Namespace+"."+gateway.Name, maxAnnotationNameLength), r.InstanceID)
+}
+
+// getGateway fetches a managed Gateway, reporting a missing one as
+// errGatewayNotFound.
+func (r *HTTPRouteReconciler) getGateway(ctx context.Context, key types.NamespacedName, gateway *gatewayv1.Gateway) error {
+	if err := r.Get(ctx, key, gateway); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%w: %s", errGatewayNotFound, key)
+		}
+		return fmt.Errorf("failed to get gateway: %w", err)
+	}
+	return nil
+}
+
+// gatewayNotFoundResult turns a missing Gateway into a fixed requeue after
+// GatewayNotFoundRequeue, reporting whether it did.
+func (r *HTTPRouteReconciler) gatewayNotFoundResult(ctx context.Context, err error) (ctrl.Result, bool) {
+	if r.GatewayNotFoundRequeue <= 0 || !errors.Is(err, errGatewayNotFound) {
+		return ctrl.Result{}, false
+	}
+	log.FromContext(ctx).Info("gateway not found, requeueing", "reason", err.Error(), "after", r.GatewayNotFoundRequeue)
+	return ctrl.Result{RequeueAfter: r.GatewayNotFoundRequeue}, true
+}
@@ -0,0 +1,338 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestManagedHostnamesKey(t *testing.T) {
+	r := newReconciler()
+	if key := r.managedHostnamesKey(r.primaryGateway()); key != managedHostnamesAnnotation {
+		t.Errorf("expected primary gateway to use %s, got %s", managedHostnamesAnnotation, key)
+	}
+
+	key := r.managedHostnamesKey(types.NamespacedName{Name: "internal", Namespace: "edge"})
+	if key != "gateway-auto-listener/managed-hostnames.edge.internal" {
+		t.Errorf("unexpected key %s", key)
+	}
+
+	long := r.managedHostnamesKey(types.NamespacedName{Name: strings.Repeat("g", 63), Namespace: strings.Repeat("n", 63)})
+	if _, name, _ := strings.Cut(long, "/"); len(name) > maxAnnotationNameLength {
+		t.Errorf("expected key name within %d characters, got %s", maxAnnotationNameLength, long)
+	}
+}
+
+func TestReconcile_MultipleGateways(t *testing.T) {
+	primary := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	internal := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "internal", Namespace: "edge"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	primaryNamespace := gatewayv1.Namespace("nginx-gateway")
+	internalNamespace := gatewayv1.Namespace("edge")
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{
+					{Name: "default", Namespace: &primaryNamespace},
+					{Name: "internal", Namespace: &internalNamespace},
+				},
+			},
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+
+	r := newReconciler(primary, internal, httpRoute)
+	internalKey := types.NamespacedName{Name: "internal", Namespace: "edge"}
+	r.AdditionalGateways = []types.NamespacedName{internalKey}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, key := range []types.NamespacedName{r.primaryGateway(), internalKey} {
+		var gw gatewayv1.Gateway
+		_ = r.Get(ctx, key, &gw)
+		if len(gw.Spec.Listeners) != 1 || gw.Spec.Listeners[0].Name != "https-app-example-com" {
+			t.Fatalf("expected listener on gateway %s, got %v", key, listenerNames(&gw))
+		}
+		ref := gw.Spec.Listeners[0].TLS.CertificateRefs[0]
+		if ref.Namespace == nil || string(*ref.Namespace) != key.Namespace {
+			t.Errorf("expected certificate ref in namespace %s on gateway %s, got %v", key.Namespace, key, ref.Namespace)
+		}
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	for _, key := range []string{managedHostnamesAnnotation, r.managedHostnamesKey(internalKey)} {
+		if route.Annotations[key] != "https-app-example-com" {
+			t.Errorf("expected annotation %s to track the listener, got %q", key, route.Annotations[key])
+		}
+	}
+
+	// Detaching from the additional gateway prunes its listener only
+	route.Spec.ParentRefs = route.Spec.ParentRefs[:1]
+	if err := r.Update(ctx, &route); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, internalKey, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected listener removed from additional gateway, got %v", listenerNames(&gw))
+	}
+	_ = r.Get(ctx, r.primaryGateway(), &gw)
+	if len(gw.Spec.Listeners) != 1 {
+		t.Errorf("expected listener kept on primary gateway, got %v", listenerNames(&gw))
+	}
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if _, ok := route.Annotations[r.managedHostnamesKey(internalKey)]; ok {
+		t.Error("expected additional gateway annotation dropped")
+	}
+}
+
+func TestReconcile_MultipleGatewaysDelete(t *testing.T) {
+	primaryNamespace := gatewayv1.Namespace("nginx-gateway")
+	internalNamespace := gatewayv1.Namespace("edge")
+	internalKey := types.NamespacedName{Name: "internal", Namespace: "edge"}
+	listener := func(ns string) gatewayv1.Listener {
+		return newReconciler().buildListener("https-app-example-com", "app.example.com", 443, ns, "app-example-com-tls", nil)
+	}
+	primary := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{listener("nginx-gateway")},
+		},
+	}
+	internal := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "internal", Namespace: "edge"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{listener("edge")},
+		},
+	}
+	now := metav1.Now()
+	r := newReconciler()
+	r.AdditionalGateways = []types.NamespacedName{internalKey}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "app",
+			Namespace:         "default",
+			Finalizers:        []string{finalizerName},
+			DeletionTimestamp: &now,
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer":   "letsencrypt",
+				managedHostnamesAnnotation:         "https-app-example-com",
+				r.managedHostnamesKey(internalKey): "https-app-example-com",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{
+					{Name: "default", Namespace: &primaryNamespace},
+					{Name: "internal", Namespace: &internalNamespace},
+				},
+			},
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+	base := newReconciler(primary, internal, httpRoute)
+	r.Client = base.Client
+	ctx := context.Background()
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, key := range []types.NamespacedName{r.primaryGateway(), internalKey} {
+		var gw gatewayv1.Gateway
+		_ = r.Get(ctx, key, &gw)
+		if len(gw.Spec.Listeners) != 0 {
+			t.Errorf("expected listeners removed from gateway %s, got %v", key, listenerNames(&gw))
+		}
+	}
+}
+
+func TestReconcile_GatewayNotFoundRequeue(t *testing.T) {
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	r := newReconciler(httpRoute)
+	r.GatewayNotFoundRequeue = 30 * time.Second
+	result, err := r.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("expected no error while the gateway is missing, got %v", err)
+	}
+	if result.RequeueAfter != 30*time.Second {
+		t.Errorf("expected fixed requeue after 30s, got %+v", result)
+	}
+	// The route waits quietly: one event, however often it is requeued
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("expected no error while the gateway is missing, got %v", err)
+	}
+	if events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "WaitingForGateway"); len(events) != 1 {
+		t.Errorf("expected a single WaitingForGateway event, got %v", events)
+	}
+
+	// Without a fixed interval the error drives the backoff
+	r = newReconciler(httpRoute)
+	if _, err := r.Reconcile(context.Background(), req); !errors.Is(err, errGatewayNotFound) {
+		t.Errorf("expected gateway not found error, got %v", err)
+	}
+
+	// Creating the gateway requeues the route right away
+	gateway := &gatewayv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"}}
+	if !r.managedGatewayPredicate().Create(event.CreateEvent{Object: gateway}) {
+		t.Fatal("expected gateway creation to pass the predicate")
+	}
+	if requests := r.gatewayToHTTPRoutes(context.Background(), gateway); len(requests) != 1 || requests[0] != req {
+		t.Errorf("expected the route requeued on gateway creation, got %v", requests)
+	}
+}
+
+func TestRouteGateways_Annotation(t *testing.T) {
+	r := newReconciler()
+	internalKey := types.NamespacedName{Name: "internal", Namespace: "edge"}
+	r.AdditionalGateways = []types.NamespacedName{internalKey}
+
+	tests := []struct {
+		name       string
+		annotation string
+		want       []types.NamespacedName
+	}{
+		{name: "absent", want: []types.NamespacedName{r.primaryGateway()}},
+		{name: "additional gateway", annotation: "edge/internal", want: []types.NamespacedName{internalKey}},
+		{name: "primary gateway", annotation: "nginx-gateway/default", want: []types.NamespacedName{r.primaryGateway()}},
+		{name: "unmanaged gateway", annotation: "edge/other"},
+		{name: "missing namespace", annotation: "internal"},
+		{name: "empty name", annotation: "edge/"},
+		{name: "extra segment", annotation: "edge/internal/x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpRoute := &gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}}
+			if tt.annotation != "" {
+				httpRoute.Annotations = map[string]string{gatewayAnnotation: tt.annotation}
+			}
+			if got := r.routeGateways(httpRoute); !slices.Equal(got, tt.want) {
+				t.Errorf("routeGateways() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcile_GatewayAnnotation(t *testing.T) {
+	primary := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: "nginx", Listeners: []gatewayv1.Listener{}},
+	}
+	internal := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "internal", Namespace: "edge"},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: "nginx", Listeners: []gatewayv1.Listener{}},
+	}
+	route := func(name string, annotations map[string]string) *gatewayv1.HTTPRoute {
+		annotations["cert-manager.io/cluster-issuer"] = "letsencrypt"
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Finalizers:  []string{finalizerName},
+				Annotations: annotations,
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(name + ".example.com")},
+			},
+		}
+	}
+	internalRoute := route("intranet", map[string]string{gatewayAnnotation: "edge/internal"})
+	publicRoute := route("www", map[string]string{})
+	malformedRoute := route("broken", map[string]string{gatewayAnnotation: "internal"})
+
+	r := newReconciler(primary, internal, internalRoute, publicRoute, malformedRoute)
+	internalKey := types.NamespacedName{Name: "internal", Namespace: "edge"}
+	r.AdditionalGateways = []types.NamespacedName{internalKey}
+	fakeRecorder := record.NewFakeRecorder(20)
+	r.Recorder = fakeRecorder
+	ctx := context.Background()
+
+	for _, name := range []string{"intranet", "www", "broken"} {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}}); err != nil {
+			t.Fatalf("unexpected error reconciling %s: %v", name, err)
+		}
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, internalKey, &gw)
+	if names := listenerNames(&gw); !slices.Equal(names, []string{"https-intranet-example-com"}) {
+		t.Errorf("expected only the annotated route's listener on the internal gateway, got %v", names)
+	}
+	_ = r.Get(ctx, r.primaryGateway(), &gw)
+	if names := listenerNames(&gw); !slices.Equal(names, []string{"https-www-example-com"}) {
+		t.Errorf("expected only the default route's listener on the primary gateway, got %v", names)
+	}
+
+	events := eventsWithReason(drainEvents(fakeRecorder), "InvalidGatewayAnnotation")
+	if len(events) != 1 || !strings.Contains(events[0], `got "internal"`) {
+		t.Errorf("expected one InvalidGatewayAnnotation event for the malformed route, got %v", events)
+	}
+
+	// Gateway events fan out only to the routes on that gateway
+	requests := func(gateway *gatewayv1.Gateway) []string {
+		var names []string
+		for _, req := range r.gatewayToHTTPRoutes(ctx, gateway) {
+			names = append(names, req.Name)
+		}
+		return names
+	}
+	if names := requests(internal); !slices.Equal(names, []string{"intranet"}) {
+		t.Errorf("expected internal gateway events to map to the annotated route, got %v", names)
+	}
+	if names := requests(primary); !slices.Equal(names, []string{"www"}) {
+		t.Errorf("expected primary gateway events to map to the default route, got %v", names)
+	}
+}
@@ -0,0 +1,500 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// GRPCRouteReconciler provisions listeners for GRPCRoutes, sharing the
+// configuration, naming and hostname validation of the HTTPRouteReconciler
+// it wraps. It covers the route lifecycle: the finalizer, hostname
+// validation, and adding and removing the listeners of the route's hostnames
+// on the managed Gateways it attaches to. The tls-secret-name and
+// tls-secret-namespace annotations apply as for HTTPRoutes; other annotations
+// tuning listeners, such as protocol, listener-port, tls-mode or tls-options,
+// only apply to HTTPRoutes. ListenerTLSOptions applies to both.
+type GRPCRouteReconciler struct {
+	*HTTPRouteReconciler
+}
+
+func (r *GRPCRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	var grpcRoute gatewayv1.GRPCRoute
+	if err := r.Get(ctx, req.NamespacedName, &grpcRoute); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	log = log.WithValues("uid", grpcRoute.UID)
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	if !r.shouldManage(&grpcRoute) {
+		return ctrl.Result{}, nil
+	}
+
+	// Deleted and ignored routes give up their listeners and finalizer
+	if !grpcRoute.DeletionTimestamp.IsZero() || isIgnored(&grpcRoute) {
+		r.eventThrottle.forget(req.NamespacedName)
+		if !controllerutil.ContainsFinalizer(&grpcRoute, r.finalizer()) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.releaseGRPCRoute(ctx, &grpcRoute); err != nil {
+			if delay, ok := throttleDelay(err); ok {
+				log.V(1).Info("gateway mutation throttled, requeueing", "after", delay)
+				return ctrl.Result{RequeueAfter: delay}, nil
+			}
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	targeted := r.parentGateways(&grpcRoute, grpcRoute.Spec.ParentRefs)
+	if len(targeted) == 0 {
+		if !controllerutil.ContainsFinalizer(&grpcRoute, r.finalizer()) {
+			log.V(1).Info("route does not target the managed gateway, skipping")
+			return ctrl.Result{}, nil
+		}
+		// The route moved away from every managed Gateway: give up the
+		// listeners it still tracks and its finalizer
+		if err := r.releaseGRPCRoute(ctx, &grpcRoute); err != nil {
+			if delay, ok := throttleDelay(err); ok {
+				log.V(1).Info("gateway mutation throttled, requeueing", "after", delay)
+				return ctrl.Result{RequeueAfter: delay}, nil
+			}
+			return ctrl.Result{}, err
+		}
+		if !r.isDryRun(&grpcRoute) {
+			log.Info("route no longer targets a managed gateway, released its listeners")
+			r.Recorder.Event(&grpcRoute, corev1.EventTypeNormal, "RouteDetached",
+				"route no longer targets a managed Gateway, released its listeners")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Add finalizer if not present. The update triggers another reconcile,
+	// which provisions the listeners.
+	if !controllerutil.ContainsFinalizer(&grpcRoute, r.finalizer()) && !r.DryRun {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			var current gatewayv1.GRPCRoute
+			if err := r.Get(ctx, req.NamespacedName, &current); err != nil {
+				return err
+			}
+			if !controllerutil.AddFinalizer(&current, r.finalizer()) {
+				return nil
+			}
+			return r.Update(ctx, &current)
+		})
+		if err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		if !r.ObserveOnly {
+			return ctrl.Result{}, nil
+		}
+	}
+
+	for _, key := range r.managedGateways() {
+		attached := slices.Contains(targeted, key)
+		if _, tracked := grpcRoute.Annotations[r.managedHostnamesKey(key)]; !attached && !tracked {
+			continue
+		}
+		err := r.reconcileGRPCGatewayListeners(ctx, &grpcRoute, key, attached)
+		if delay, ok := throttleDelay(err); ok {
+			log.V(1).Info("gateway mutation throttled, requeueing", "after", delay)
+			return ctrl.Result{RequeueAfter: delay}, nil
+		}
+		if result, ok := r.gatewayNotFoundResult(ctx, err); ok {
+			return result, nil
+		}
+		if err != nil {
+			log.Error(err, "failed to reconcile listeners", "gateway", key)
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: r.RequeueAfterSuccess}, nil
+}
+
+// releaseGRPCRoute removes the listeners of a deleted or ignored route, or
+// of one no longer attached to any managed Gateway, then drops its
+// bookkeeping annotations and finalizer.
+func (r *GRPCRouteReconciler) releaseGRPCRoute(ctx context.Context, grpcRoute *gatewayv1.GRPCRoute) error {
+	for _, key := range r.managedGateways() {
+		if _, tracked := grpcRoute.Annotations[r.managedHostnamesKey(key)]; !tracked {
+			continue
+		}
+		// A missing Gateway has no listeners left to remove
+		if err := r.reconcileGRPCGatewayListeners(ctx, grpcRoute, key, false); err != nil && !errors.Is(err, errGatewayNotFound) {
+			return err
+		}
+	}
+	// Routes stay terminating until dry-run is turned off
+	if r.isDryRun(grpcRoute) {
+		return nil
+	}
+	for _, key := range r.managedGateways() {
+		delete(grpcRoute.Annotations, r.managedHostnamesKey(key))
+	}
+	controllerutil.RemoveFinalizer(grpcRoute, r.finalizer())
+	if err := r.Update(ctx, grpcRoute); err != nil {
+		return fmt.Errorf("failed to update grpcroute: %w", err)
+	}
+	return nil
+}
+
+// grpcRouteHostnames returns the route's hostnames that get listeners: valid,
+// within the include pattern, rendering valid names and passing hostname
+// validation for the route's namespace. Skipped hostnames are reported;
+// errors looking the hostname policy up are returned.
+func (r *GRPCRouteReconciler) grpcRouteHostnames(ctx context.Context, grpcRoute *gatewayv1.GRPCRoute) ([]string, error) {
+	log := log.FromContext(ctx)
+
+	var hostnames []string
+	for _, hostname := range grpcRoute.Spec.Hostnames {
+		if hostname == "" {
+			continue
+		}
+		normalized, err := normalizeHostname(string(hostname))
+		if err != nil {
+			r.Recorder.Eventf(grpcRoute, corev1.EventTypeWarning, "InvalidHostname",
+				"ignoring hostname %s: %v", hostname, err)
+			continue
+		}
+		if slices.Contains(hostnames, normalized) {
+			continue
+		}
+		if r.HostnameIncludePattern != nil && !r.HostnameIncludePattern.MatchString(normalized) {
+			log.Info("hostname excluded by include pattern", "hostname", normalized)
+			continue
+		}
+		if err := r.checkHostnameNames(normalized); err != nil {
+			r.Recorder.Eventf(grpcRoute, corev1.EventTypeWarning, "InvalidGeneratedName",
+				"skipping hostname %s: %v", normalized, err)
+			continue
+		}
+		if err := r.validateHostname(ctx, normalized, grpcRoute.Namespace); err != nil {
+			if !isPolicyRejection(err) {
+				return nil, fmt.Errorf("failed to validate hostname %s: %w", normalized, err)
+			}
+			if r.ValidationShadowMode {
+				log.Info("shadow mode: hostname would be rejected", "hostname", normalized, "reason", err.Error())
+				r.Recorder.Eventf(grpcRoute, corev1.EventTypeWarning, "WouldRejectHostname",
+					"hostname %s would be rejected for namespace %s: %v", normalized, grpcRoute.Namespace, err)
+			} else {
+				log.Error(err, "hostname validation failed", "hostname", normalized)
+				r.Recorder.Eventf(grpcRoute, corev1.EventTypeWarning, "HostnameValidationFailed",
+					"hostname %s not allowed for namespace %s", normalized, grpcRoute.Namespace)
+				continue
+			}
+		}
+		hostnames = append(hostnames, normalized)
+	}
+	return hostnames, nil
+}
+
+// reconcileGRPCGatewayListeners brings the route's listeners on one managed
+// Gateway in line with its hostnames, or removes them all when the route is
+// not attached. Listeners another route still references are kept.
+func (r *GRPCRouteReconciler) reconcileGRPCGatewayListeners(ctx context.Context, grpcRoute *gatewayv1.GRPCRoute,
+	key types.NamespacedName, attached bool) error {
+	log := log.FromContext(ctx).WithValues("gateway", key)
+
+	var gateway gatewayv1.Gateway
+	if err := r.getGateway(ctx, key, &gateway); err != nil {
+		return err
+	}
+
+	// Desired listener names, in hostname order, with their hostname and port
+	type listenerKey struct {
+		hostname string
+		port     gatewayv1.PortNumber
+	}
+	desired := make(map[string]listenerKey)
+	var desiredNames []string
+	var routeNamespaces *gatewayv1.RouteNamespaces
+	secretNamespace, secretOverride := gateway.Namespace, ""
+	if attached && grpcRoute.DeletionTimestamp.IsZero() && !isIgnored(grpcRoute) {
+		hostnames, err := r.grpcRouteHostnames(ctx, grpcRoute)
+		if err != nil {
+			return err
+		}
+		secretNamespace, secretOverride = r.listenerSecretRef(ctx, &gateway, grpcRoute)
+		for _, hostname := range hostnames {
+			for _, port := range r.listenerPorts() {
+				name := r.listenerName(hostname, port)
+				desired[name] = listenerKey{hostname, port}
+				desiredNames = append(desiredNames, name)
+			}
+		}
+		if routeNamespaces, err = r.routeNamespaces(ctx, grpcRoute); err != nil {
+			return err
+		}
+	}
+
+	annotationKey := r.managedHostnamesKey(key)
+	previous := make(map[string]bool)
+	if prev := grpcRoute.Annotations[annotationKey]; prev != "" {
+		for _, name := range strings.Split(prev, ",") {
+			previous[name] = true
+		}
+	}
+
+	// Only listeners the controller created are removed
+	created, recorded := createdListeners(&gateway, r.managedListenersKey())
+	stale := func(l gatewayv1.Listener) bool {
+		_, ok := desired[string(l.Name)]
+		return previous[string(l.Name)] && !ok && (!recorded || created[string(l.Name)])
+	}
+	var references map[string]bool
+	if slices.ContainsFunc(gateway.Spec.Listeners, stale) {
+		var err error
+		if references, err = r.grpcListenerReferences(ctx, grpcRoute, key); err != nil {
+			return err
+		}
+	}
+
+	original := gateway.DeepCopy()
+	dryRun := r.isDryRun(grpcRoute)
+	existing := make(map[string]string)
+	var removedNames []string
+	// Never nil, so the patch carries an empty array rather than null
+	newListeners := make([]gatewayv1.Listener, 0, len(gateway.Spec.Listeners))
+	for _, l := range gateway.Spec.Listeners {
+		name := string(l.Name)
+		if l.Hostname != nil {
+			existing[name] = string(*l.Hostname)
+		} else {
+			existing[name] = ""
+		}
+		if stale(l) && !references[name] {
+			if dryRun {
+				r.Recorder.Eventf(grpcRoute, corev1.EventTypeNormal, "DryRunRemoveListener",
+					"would remove listener %s", name)
+			} else {
+				log.Info("removing listener", "listener", name)
+				removedNames = append(removedNames, name)
+			}
+			continue
+		}
+		newListeners = append(newListeners, l)
+	}
+
+	tracked := make(map[string]bool)
+	owned := make(map[string]bool)
+	var addedNames []string
+	for _, name := range desiredNames {
+		hostname, port := desired[name].hostname, desired[name].port
+		if other, ok := existing[name]; ok {
+			if other != hostname {
+				if r.allowListenerEvent(grpcRoute, &gateway, hostname, "ListenerNameConflict") {
+					r.recordListenerEvent(grpcRoute, &gateway, corev1.EventTypeWarning, "ListenerNameConflict",
+						"listener %s for hostname %s already exists on Gateway %s/%s for hostname %q",
+						name, hostname, gateway.Namespace, gateway.Name, other)
+				}
+				continue
+			}
+			// Shared with another route or created by hand
+			tracked[name] = true
+			if previous[name] {
+				owned[name] = true
+			}
+			continue
+		}
+		if r.MaxListeners > 0 && len(newListeners) >= r.MaxListeners {
+			r.Recorder.Eventf(grpcRoute, corev1.EventTypeWarning, "ListenerLimitExceeded",
+				"Gateway %s/%s is at its limit of %d listeners; no listener added for hostname %s",
+				gateway.Namespace, gateway.Name, r.MaxListeners, hostname)
+			continue
+		}
+		secretName := secretOverride
+		if secretName == "" {
+			secretName = r.hostnameToSecretName(hostname)
+		}
+		listener := r.buildListener(name, hostname, port, secretNamespace, secretName, r.ListenerTLSOptions)
+		listener.AllowedRoutes.Namespaces = routeNamespaces.DeepCopy()
+		newListeners = append(newListeners, listener)
+		existing[name] = hostname
+		tracked[name] = true
+		owned[name] = true
+		if dryRun {
+			r.Recorder.Eventf(grpcRoute, corev1.EventTypeNormal, "DryRunAddListener",
+				"would add listener %s for hostname %s", name, hostname)
+		} else {
+			log.Info("adding listener", "listener", name, "hostname", hostname)
+			addedNames = append(addedNames, name)
+		}
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if len(addedNames) > 0 || len(removedNames) > 0 {
+		gateway.Spec.Listeners = newListeners
+		if gateway.Labels == nil {
+			gateway.Labels = make(map[string]string)
+		}
+		gateway.Labels[managedByLabel] = managedByValue
+
+		managed, err := r.grpcListenerOwnership(ctx, grpcRoute, annotationKey, owned)
+		if err != nil {
+			return err
+		}
+		orderManagedListeners(gateway.Spec.Listeners, managed)
+		r.recordCreatedListeners(&gateway, original, managed, addedNames, removedNames)
+		if err := r.patchGateway(ctx, &gateway, original, managed); err != nil {
+			return err
+		}
+		if err := r.syncReferenceGrants(ctx, grpcRoute, original, &gateway); err != nil {
+			return err
+		}
+		for _, name := range removedNames {
+			r.recordListenerEvent(grpcRoute, &gateway, corev1.EventTypeNormal, "ListenerRemoved",
+				"removed listener %s", name)
+		}
+		for _, name := range addedNames {
+			r.recordListenerEvent(grpcRoute, &gateway, corev1.EventTypeNormal, "ListenerCreated",
+				"created listener %s for hostname %s", name, desired[name].hostname)
+		}
+	}
+
+	// Deleted and ignored routes drop their bookkeeping with the finalizer
+	if !grpcRoute.DeletionTimestamp.IsZero() || isIgnored(grpcRoute) {
+		return nil
+	}
+	names := make([]string, 0, len(tracked))
+	for name := range tracked {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	value := strings.Join(names, ",")
+	prev, ok := grpcRoute.Annotations[annotationKey]
+	switch {
+	case !attached && value == "" && ok:
+		delete(grpcRoute.Annotations, annotationKey)
+	case prev != value:
+		if grpcRoute.Annotations == nil {
+			grpcRoute.Annotations = make(map[string]string)
+		}
+		grpcRoute.Annotations[annotationKey] = value
+	default:
+		return nil
+	}
+	if err := r.Update(ctx, grpcRoute); err != nil {
+		return fmt.Errorf("failed to update grpcroute annotation: %w", err)
+	}
+	return nil
+}
+
+// grpcListenerReferences returns the listener names on the Gateway that
+// HTTPRoutes or other live GRPCRoutes still rely on.
+func (r *GRPCRouteReconciler) grpcListenerReferences(ctx context.Context, grpcRoute *gatewayv1.GRPCRoute, key types.NamespacedName) (map[string]bool, error) {
+	referenced, err := r.httpRouteReferences(ctx, client.ObjectKey{}, key)
+	if err != nil {
+		return nil, err
+	}
+	grpcReferenced, err := r.grpcRouteListenerNames(ctx, r.managedHostnamesKey(key), client.ObjectKeyFromObject(grpcRoute), true)
+	if err != nil {
+		return nil, err
+	}
+	for name := range grpcReferenced {
+		referenced[name] = true
+	}
+	return referenced, nil
+}
+
+// grpcListenerOwnership reports which listeners of a Gateway the controller
+// manages: those tracked by any HTTPRoute or another GRPCRoute, plus the
+// given ones of this route.
+func (r *GRPCRouteReconciler) grpcListenerOwnership(ctx context.Context, grpcRoute *gatewayv1.GRPCRoute, annotationKey string, owned map[string]bool) (func(name string) bool, error) {
+	managed, err := managedListenerNames(ctx, r, annotationKey, client.ObjectKey{})
+	if err != nil {
+		return nil, err
+	}
+	grpcManaged, err := r.grpcRouteListenerNames(ctx, annotationKey, client.ObjectKeyFromObject(grpcRoute), false)
+	if err != nil {
+		return nil, err
+	}
+	return func(name string) bool {
+		return managed[name] || grpcManaged[name] || owned[name]
+	}, nil
+}
+
+// grpcRouteListenerNames collects the listener names tracked under
+// annotationKey by every GRPCRoute except the excluded one. With live set,
+// routes being deleted or ignored are skipped, as their listeners are on
+// their way out.
+func (r *HTTPRouteReconciler) grpcRouteListenerNames(ctx context.Context, annotationKey string, exclude client.ObjectKey, live bool) (map[string]bool, error) {
+	var routes gatewayv1.GRPCRouteList
+	if err := r.List(ctx, &routes); err != nil {
+		return nil, fmt.Errorf("failed to list grpcroutes: %w", err)
+	}
+	names := make(map[string]bool)
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if client.ObjectKeyFromObject(route) == exclude {
+			continue
+		}
+		if live && (!route.DeletionTimestamp.IsZero() || isIgnored(route) ||
+			!controllerutil.ContainsFinalizer(route, r.finalizer())) {
+			continue
+		}
+		if value := route.Annotations[annotationKey]; value != "" {
+			for _, name := range strings.Split(value, ",") {
+				names[name] = true
+			}
+		}
+	}
+	return names, nil
+}
+
+// SetupWithManager registers the GRPCRoute controller. It expects the
+// wrapped HTTPRouteReconciler to be set up first.
+func (r *GRPCRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1.GRPCRoute{}).
+		Watches(&gatewayv1.Gateway{}, handler.EnqueueRequestsFromMapFunc(r.gatewayToGRPCRoutes),
+			builder.WithPredicates(r.managedGatewayPredicate())).
+		Complete(r)
+}
+
+// gatewayToGRPCRoutes maps a Gateway event back to the GRPCRoutes that
+// attach to it or still track listeners on it.
+func (r *GRPCRouteReconciler) gatewayToGRPCRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
+	gateway, ok := obj.(*gatewayv1.Gateway)
+	if !ok || !r.isManagedGateway(gateway) {
+		return nil
+	}
+
+	var routes gatewayv1.GRPCRouteList
+	if err := r.List(ctx, &routes); err != nil {
+		return nil
+	}
+	key := client.ObjectKeyFromObject(gateway)
+	var requests []reconcile.Request
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if !r.shouldManage(route) || isIgnored(route) || !controllerutil.ContainsFinalizer(route, r.finalizer()) {
+			continue
+		}
+		_, tracked := route.Annotations[r.managedHostnamesKey(key)]
+		if !tracked && !slices.Contains(r.parentGateways(route, route.Spec.ParentRefs), key) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(route)})
+	}
+	return requests
+}
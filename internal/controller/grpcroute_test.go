@@ -0,0 +1,420 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func newGRPCReconciler(objs ...client.Object) *GRPCRouteReconciler {
+	r := newReconciler(objs...)
+	r.ManageGRPCRoutes = true
+	return &GRPCRouteReconciler{HTTPRouteReconciler: r}
+}
+
+func grpcTestGateway() *gatewayv1.Gateway {
+	return &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+}
+
+func TestGRPCReconcile_CreatesListener(t *testing.T) {
+	grpcRoute := &gatewayv1.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "grpc-route",
+			Namespace:   "default",
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.GRPCRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"grpc.example.com"},
+		},
+	}
+
+	r := newGRPCReconciler(grpcTestGateway(), grpcRoute)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "grpc-route", Namespace: "default"}}
+
+	// First reconcile adds the finalizer, the second creates the listener
+	for range 2 {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 {
+		t.Fatalf("expected 1 listener, got %v", listenerNames(&gw))
+	}
+	listener := gw.Spec.Listeners[0]
+	if string(listener.Name) != "https-grpc-example-com" {
+		t.Errorf("expected listener name 'https-grpc-example-com', got %q", listener.Name)
+	}
+	if listener.Port != 443 || listener.Protocol != gatewayv1.HTTPSProtocolType {
+		t.Errorf("expected HTTPS on port 443, got %s on %d", listener.Protocol, listener.Port)
+	}
+	if listener.TLS == nil || len(listener.TLS.CertificateRefs) != 1 ||
+		string(listener.TLS.CertificateRefs[0].Name) != "grpc-example-com-tls" {
+		t.Errorf("expected certificate ref grpc-example-com-tls, got %+v", listener.TLS)
+	}
+
+	var route gatewayv1.GRPCRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if !controllerutil.ContainsFinalizer(&route, finalizerName) {
+		t.Error("expected finalizer to be present")
+	}
+	if got := route.Annotations[managedHostnamesAnnotation]; got != "https-grpc-example-com" {
+		t.Errorf("expected managed-hostnames annotation https-grpc-example-com, got %q", got)
+	}
+	if events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "ListenerCreated"); len(events) != 1 {
+		t.Errorf("expected one ListenerCreated event, got %v", events)
+	}
+}
+
+func TestGRPCReconcile_SkipWithoutAnnotation(t *testing.T) {
+	grpcRoute := &gatewayv1.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "grpc-route", Namespace: "default"},
+		Spec: gatewayv1.GRPCRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"grpc.example.com"},
+		},
+	}
+
+	r := newGRPCReconciler(grpcTestGateway(), grpcRoute)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "grpc-route", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var route gatewayv1.GRPCRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if controllerutil.ContainsFinalizer(&route, finalizerName) {
+		t.Error("expected no finalizer without an issuer annotation")
+	}
+}
+
+func TestGRPCReconcile_RouteMovedOffManagedGateway(t *testing.T) {
+	grpcRoute := &gatewayv1.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "grpc-route",
+			Namespace:   "default",
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.GRPCRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"grpc.example.com"},
+		},
+	}
+	r := newGRPCReconciler(grpcTestGateway(), grpcRoute)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "grpc-route", Namespace: "default"}}
+	for range 2 {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Point the route at a Gateway the controller does not manage
+	var route gatewayv1.GRPCRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	otherNamespace := gatewayv1.Namespace("other")
+	route.Spec.ParentRefs = []gatewayv1.ParentReference{{Name: "external", Namespace: &otherNamespace}}
+	if err := r.Update(ctx, &route); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected the listener to be removed, got %v", listenerNames(&gw))
+	}
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if controllerutil.ContainsFinalizer(&route, finalizerName) {
+		t.Error("expected the finalizer to be removed")
+	}
+	if _, ok := route.Annotations[managedHostnamesAnnotation]; ok {
+		t.Errorf("expected the managed-hostnames annotation to be removed, got %q", route.Annotations[managedHostnamesAnnotation])
+	}
+	if events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "RouteDetached"); len(events) != 1 {
+		t.Errorf("expected one RouteDetached event, got %v", events)
+	}
+}
+
+func TestGRPCReconcile_TLSAnnotations(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		wantListener  bool
+		wantNamespace string
+		wantSecret    string
+		wantEvents    []string
+	}{
+		{
+			name:        "passthrough alone is not managed",
+			annotations: map[string]string{tlsModeAnnotation: "Passthrough"},
+		},
+		{
+			name:          "secret override",
+			annotations:   map[string]string{tlsSecretNameAnnotation: "vault-tls"},
+			wantListener:  true,
+			wantNamespace: "nginx-gateway",
+			wantSecret:    "vault-tls",
+		},
+		{
+			name:          "allowed secret namespace",
+			annotations:   map[string]string{tlsSecretNameAnnotation: "vault-tls", tlsSecretNamespaceAnnotation: "certs"},
+			wantListener:  true,
+			wantNamespace: "certs",
+			wantSecret:    "vault-tls",
+			wantEvents:    []string{"CrossNamespaceSecret"},
+		},
+		{
+			name:          "refused secret namespace",
+			annotations:   map[string]string{tlsSecretNameAnnotation: "vault-tls", tlsSecretNamespaceAnnotation: "other"},
+			wantListener:  true,
+			wantNamespace: "nginx-gateway",
+			wantSecret:    "vault-tls",
+			wantEvents:    []string{"SecretNamespaceNotAllowed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grpcRoute := &gatewayv1.GRPCRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "grpc-route", Namespace: "default", Annotations: tt.annotations},
+				Spec:       gatewayv1.GRPCRouteSpec{Hostnames: []gatewayv1.Hostname{"grpc.example.com"}},
+			}
+			r := newGRPCReconciler(grpcTestGateway(), grpcRoute)
+			r.AllowedSecretNamespaces = []string{"certs"}
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "grpc-route", Namespace: "default"}}
+			for range 2 {
+				if _, err := r.Reconcile(ctx, req); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			if !tt.wantListener {
+				if len(gw.Spec.Listeners) != 0 {
+					t.Errorf("expected no listeners, got %v", listenerNames(&gw))
+				}
+				return
+			}
+			if len(gw.Spec.Listeners) != 1 {
+				t.Fatalf("expected 1 listener, got %v", listenerNames(&gw))
+			}
+			tls := gw.Spec.Listeners[0].TLS
+			if tls == nil || *tls.Mode != gatewayv1.TLSModeTerminate || len(tls.CertificateRefs) != 1 {
+				t.Fatalf("expected a Terminate listener with one certificate ref, got %+v", tls)
+			}
+			ref := tls.CertificateRefs[0]
+			if string(*ref.Namespace) != tt.wantNamespace || string(ref.Name) != tt.wantSecret {
+				t.Errorf("expected certificate ref %s/%s, got %s/%s", tt.wantNamespace, tt.wantSecret, *ref.Namespace, ref.Name)
+			}
+			events := drainEvents(r.Recorder.(*record.FakeRecorder))
+			for _, reason := range tt.wantEvents {
+				if len(eventsWithReason(events, reason)) == 0 {
+					t.Errorf("expected a %s event, got %v", reason, events)
+				}
+			}
+		})
+	}
+}
+
+func TestGRPCReconcile_DeleteRemovesListener(t *testing.T) {
+	grpcRoute := &gatewayv1.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "grpc-route",
+			Namespace:   "default",
+			Finalizers:  []string{finalizerName},
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.GRPCRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"grpc.example.com", "api.example.com"},
+		},
+	}
+
+	r := newGRPCReconciler(grpcTestGateway(), grpcRoute)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "grpc-route", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 2 {
+		t.Fatalf("expected 2 listeners, got %v", listenerNames(&gw))
+	}
+
+	// Dropping a hostname removes its listener
+	var route gatewayv1.GRPCRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	route.Spec.Hostnames = []gatewayv1.Hostname{"grpc.example.com"}
+	if err := r.Update(ctx, &route); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if names := listenerNames(&gw); !slices.Equal(names, []string{"https-grpc-example-com"}) {
+		t.Fatalf("expected the dropped hostname's listener to be removed, got %v", names)
+	}
+
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if err := r.Delete(ctx, &route); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected 0 listeners after deletion, got %v", listenerNames(&gw))
+	}
+	if err := r.Get(ctx, req.NamespacedName, &route); err == nil {
+		t.Error("expected the route to be gone once its finalizer was removed")
+	}
+}
+
+func TestGRPCReconcile_HostnameValidation(t *testing.T) {
+	grpcRoute := &gatewayv1.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "grpc-route",
+			Namespace:   "tenant-shop",
+			Finalizers:  []string{finalizerName},
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.GRPCRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"grpc.tenant-shop.example.com", "grpc.other.org"},
+		},
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-shop"}}
+
+	r := newGRPCReconciler(grpcTestGateway(), grpcRoute, namespace)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "grpc-route", Namespace: "tenant-shop"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if names := listenerNames(&gw); !slices.Equal(names, []string{"https-grpc-tenant-shop-example-com"}) {
+		t.Errorf("expected only the allowed hostname's listener, got %v", names)
+	}
+	if events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "HostnameValidationFailed"); len(events) != 1 {
+		t.Errorf("expected one HostnameValidationFailed event, got %v", events)
+	}
+}
+
+func TestGRPCReconcile_ListenerSharedWithHTTPRoute(t *testing.T) {
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "web",
+			Namespace:   "default",
+			Finalizers:  []string{finalizerName},
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+	grpcRoute := &gatewayv1.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "grpc",
+			Namespace:   "default",
+			Finalizers:  []string{finalizerName},
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.GRPCRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+
+	r := newGRPCReconciler(grpcTestGateway(), httpRoute, grpcRoute)
+	ctx := context.Background()
+	httpReq := ctrl.Request{NamespacedName: types.NamespacedName{Name: "web", Namespace: "default"}}
+	grpcReq := ctrl.Request{NamespacedName: types.NamespacedName{Name: "grpc", Namespace: "default"}}
+	if _, err := r.HTTPRouteReconciler.Reconcile(ctx, httpReq); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, grpcReq); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if names := listenerNames(&gw); !slices.Equal(names, []string{"https-app-example-com"}) {
+		t.Fatalf("expected one shared listener, got %v", names)
+	}
+
+	// The GRPCRoute keeps the listener once the HTTPRoute is gone
+	var web gatewayv1.HTTPRoute
+	_ = r.Get(ctx, httpReq.NamespacedName, &web)
+	if err := r.Delete(ctx, &web); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.HTTPRouteReconciler.Reconcile(ctx, httpReq); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 {
+		t.Fatalf("expected the listener to outlive the HTTPRoute, got %v", listenerNames(&gw))
+	}
+
+	var grpc gatewayv1.GRPCRoute
+	_ = r.Get(ctx, grpcReq.NamespacedName, &grpc)
+	if err := r.Delete(ctx, &grpc); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, grpcReq); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected the listener to be removed with the last route, got %v", listenerNames(&gw))
+	}
+}
+
+func TestGatewayToGRPCRoutes(t *testing.T) {
+	gateway := grpcTestGateway()
+	route := func(name string, annotations map[string]string) *gatewayv1.GRPCRoute {
+		annotations[clusterIssuerAnnotation] = "letsencrypt"
+		return &gatewayv1.GRPCRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Finalizers:  []string{finalizerName},
+				Annotations: annotations,
+			},
+		}
+	}
+
+	r := newGRPCReconciler(gateway,
+		route("managed", map[string]string{}),
+		route("ignored", map[string]string{ignoreAnnotation: "true"}),
+	)
+	requests := r.gatewayToGRPCRoutes(context.Background(), gateway)
+	if len(requests) != 1 || requests[0].Name != "managed" {
+		t.Errorf("expected a request for the managed route only, got %v", requests)
+	}
+}
@@ -2,19 +2,36 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"text/template"
+	"time"
+	"unicode/utf8"
 
+	"golang.org/x/net/idna"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
@@ -26,63 +43,403 @@ const (
 	managedByLabel             = "gateway-auto-listener/managed-by"
 	managedByValue             = "gateway-auto-listener"
 	managedHostnamesAnnotation = "gateway-auto-listener/managed-hostnames"
+	dryRunAnnotation           = "gateway-auto-listener/dry-run"
+	protocolAnnotation         = "gateway-auto-listener/protocol"
+	listenerPortAnnotation     = "gateway-auto-listener/listener-port"
+	// listenersAnnotation lists the listeners a route owns for users to
+	// inspect; the controller's bookkeeping never reads it.
+	listenersAnnotation = "gateway-auto-listener/listeners"
+
+	defaultListenerPort gatewayv1.PortNumber = 443
+
+	// nameHashLength is the number of hex characters of the hash appended to
+	// truncated names.
+	nameHashLength = 8
+)
+
+// Modes for handling empty hostnames listed on a route.
+const (
+	// EmptyHostnameSkip silently ignores empty hostnames.
+	EmptyHostnameSkip = "skip"
+	// EmptyHostnameError fails the reconcile so the route is retried.
+	EmptyHostnameError = "error"
+	// EmptyHostnameEvent records a warning event and ignores the hostname.
+	EmptyHostnameEvent = "event"
 )
 
+// errHostnameClaimConflict marks a hostname whose custom domain is claimed by
+// another validated namespace.
+var errHostnameClaimConflict = errors.New("hostname claim conflict")
+
+// errHostnameNotAllowed marks a hostname the namespace's policy does not
+// allow.
+var errHostnameNotAllowed = errors.New("not allowed")
+
+// isPolicyRejection reports whether an error of validateHostname rejects the
+// hostname by policy, rather than failing to look the policy up.
+func isPolicyRejection(err error) bool {
+	return errors.Is(err, errHostnameNotAllowed) || errors.Is(err, errHostnameClaimConflict)
+}
+
 type HTTPRouteReconciler struct {
 	client.Client
-	Scheme                     *runtime.Scheme
-	Recorder                   record.EventRecorder
-	GatewayName                string
-	GatewayNamespace           string
-	AllowedDomainSuffix        string
-	ValidatedNSPrefix          string
+	Scheme              *runtime.Scheme
+	Recorder            record.EventRecorder
+	GatewayName         string
+	GatewayNamespace    string
+	AllowedDomainSuffix string
+	ValidatedNSPrefix   string
+	// ValidatedNSSelector additionally subjects namespaces whose labels match
+	// it to hostname validation. Nil disables label-based selection.
+	ValidatedNSSelector        labels.Selector
 	AllowedHostnamesAnnotation string
+	// StrictAllowedHostnames records a MalformedAllowedHostnames event on the
+	// namespace when its allowed-hostnames annotation has malformed entries.
+	StrictAllowedHostnames bool
+	// RejectHostnameClaimConflicts refuses custom domains that another, older
+	// validated namespace also claims in its allowed-hostnames annotation.
+	RejectHostnameClaimConflicts bool
+	// MaxListenerNameLength caps generated listener names, replacing the tail
+	// of longer names with a hash. Zero disables the limit.
+	MaxListenerNameLength int
+	// ReportNewListenerPorts records an informational event when a listener
+	// is added on a port no existing Gateway listener uses.
+	ReportNewListenerPorts bool
+	// ListenerPorts are the ports each hostname gets a listener on. The
+	// first is the primary port; empty means 443 only.
+	ListenerPorts []gatewayv1.PortNumber
+	// ValidationShadowMode admits hostnames that fail validation, only
+	// reporting that they would have been rejected.
+	ValidationShadowMode bool
+	// EnforceOnPolicyChange removes managed listeners whose hostname no
+	// longer passes validation, e.g. after the namespace policy tightened.
+	EnforceOnPolicyChange bool
+	// RequeueAfterSuccess periodically re-verifies a route's listeners after a
+	// successful reconcile. Zero disables the requeue.
+	RequeueAfterSuccess time.Duration
+	// OnEmptyHostname is one of EmptyHostnameSkip, EmptyHostnameError or
+	// EmptyHostnameEvent. Empty behaves like EmptyHostnameSkip.
+	OnEmptyHostname string
+	// ExternalSecretCheck holds back a listener until the ExternalSecret
+	// syncing its certificate Secret is Ready.
+	ExternalSecretCheck bool
+	// MaxAllowedHostnames caps how many allowed-hostnames entries of a
+	// namespace are evaluated. Zero disables the limit.
+	MaxAllowedHostnames int
+	// HostnameIncludePattern limits listeners to matching hostnames. Nil
+	// includes every hostname.
+	HostnameIncludePattern *regexp.Regexp
+	// ConfigMap, when set, is watched for runtime changes to
+	// AllowedDomainSuffix and ValidatedNSPrefix.
+	ConfigMap types.NamespacedName
+	// PatchStrategy is one of PatchStrategyMerge, PatchStrategyOptimistic or
+	// PatchStrategyApply. Empty behaves like PatchStrategyMerge.
+	PatchStrategy string
+	// EventTarget is one of EventTargetRoute, EventTargetGateway or
+	// EventTargetBoth. Empty behaves like EventTargetRoute.
+	EventTarget string
+	// GatewayMutationRate caps listener patches per second and Gateway;
+	// throttled routes are requeued. Zero disables the limit.
+	GatewayMutationRate float64
+	// ReconcileSummaryLog emits one info line per reconcile summarizing the
+	// listener changes, validation failures and whether the Gateway was
+	// patched.
+	ReconcileSummaryLog bool
+	// AdditionalGateways are managed alongside the primary Gateway. Routes
+	// get listeners on every managed Gateway their parentRefs reference.
+	AdditionalGateways []types.NamespacedName
+	// SkipCertRefIfDefault omits the certificate ref of listeners on
+	// Gateways annotated with a default certificate Secret.
+	SkipCertRefIfDefault bool
+	// CorrectListenerDrift patches the listeners a route owns back to their
+	// desired state, undoing manual edits and carrying changes of the route's
+	// annotations to existing listeners, with a ListenerDriftCorrected event.
+	// Without it existing listeners are left as they are.
+	CorrectListenerDrift bool
+	// RecreateOnIssuerChange removes and re-adds a route's listeners when its
+	// issuer annotation changes, so the new issuer re-validates them.
+	RecreateOnIssuerChange bool
+	// DisambiguateSecretNames gives a hostname whose secret name is already
+	// used by another hostname on the Gateway a hash-qualified secret name.
+	DisambiguateSecretNames bool
+	// GatewayNotFoundRequeue requeues routes after a fixed delay while a
+	// managed Gateway is missing, instead of backing off on an error. The
+	// Gateway watch requeues them as soon as it is created. Zero keeps the
+	// error backoff.
+	GatewayNotFoundRequeue time.Duration
+	// TenantAllowedRoutes and PlatformAllowedRoutes scope which namespaces
+	// may attach routes to listeners of routes in validated (tenant) and
+	// other (platform) namespaces. Empty falls back to AllowedRoutesFrom.
+	TenantAllowedRoutes   gatewayv1.FromNamespaces
+	PlatformAllowedRoutes gatewayv1.FromNamespaces
+	// ObserveOnly logs every write the controller would make, including
+	// finalizers, status and events, without performing any of them.
+	ObserveOnly bool
+	// AllowApex lets validated namespaces use the bare allowed domain suffix
+	// itself as a hostname, not only their default subdomain.
+	AllowApex bool
+	// InstanceID scopes the finalizer, the route bookkeeping annotations and
+	// the server-side apply field manager to this controller instance, so
+	// instances managing disjoint hostnames on one Gateway never prune each
+	// other's listeners. Empty keeps the unscoped names.
+	InstanceID string
+	// ValidateListeners checks each listener against the Gateway API schema
+	// before it is added, skipping invalid ones with an InvalidListener event
+	// instead of failing the whole Gateway patch.
+	ValidateListeners bool
+	// DefaultListenerPort is the port listeners are created on when
+	// ListenerPorts is empty. Zero means 443.
+	DefaultListenerPort gatewayv1.PortNumber
+	// AllowedRoutesFrom is the AllowedRoutes scope of listeners whose
+	// tenant or platform scope is unset. Empty behaves like
+	// NamespacesFromAll.
+	AllowedRoutesFrom gatewayv1.FromNamespaces
+	// SelectorAnnotation is the route annotation holding the
+	// label selector of listeners scoped to NamespacesFromSelector. Without
+	// it, such listeners only admit routes from the route's own namespace.
+	SelectorAnnotation string
+	// ManageCertificates creates a cert-manager Certificate in the Gateway
+	// namespace for every listener a route with an issuer annotation owns,
+	// and deletes it once no listener references its Secret.
+	ManageCertificates bool
+	// DryRun reports the listener changes of every route as DryRun events
+	// instead of applying them, and writes neither finalizers nor
+	// bookkeeping annotations.
+	DryRun bool
+	// ListenerNameTemplate and SecretNameTemplate render the listener and
+	// certificate secret names of a hostname from NameTemplateData. Nil
+	// uses DefaultListenerNameTemplate and DefaultSecretNameTemplate.
+	ListenerNameTemplate *template.Template
+	SecretNameTemplate   *template.Template
+	// DefaultHostnameTemplate renders, from HostnameTemplateData, the suffix
+	// a namespace's default subdomains end with, a * matching within one
+	// label. Nil uses DefaultHostnameTemplate.
+	DefaultHostnameTemplate *template.Template
+	// CreateHTTPRedirect adds a plain HTTP listener on port 80 next to the
+	// HTTPS listeners of each hostname, for routes redirecting to HTTPS. The
+	// http-redirect route annotation overrides it.
+	CreateHTTPRedirect bool
+	// MaxListeners is the most listeners a Gateway may hold; additions
+	// beyond it are skipped with a ListenerLimitExceeded event instead of
+	// failing the patch. Zero disables the limit.
+	MaxListeners int
+	// ManageGRPCRoutes makes listener ownership and sharing account for the
+	// listeners GRPCRoutes track; set it when a GRPCRouteReconciler runs
+	// alongside.
+	ManageGRPCRoutes bool
+	// SkipWildcardCovered skips creating a listener for a hostname that a
+	// wildcard listener already on the Gateway, on the same port, matches.
+	SkipWildcardCovered bool
+	// EventThrottle records a route's hostname validation and listener name
+	// conflict warnings at most once per interval per hostname. Validation itself runs on every
+	// reconcile. Zero records them every time.
+	EventThrottle time.Duration
+	// ListenerTLSOptions are set on the TLS config of every listener created,
+	// below the Gateway and route tls-options annotations in precedence.
+	ListenerTLSOptions map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue
+	// ValidateIssuer skips the listeners of a route whose cert-manager
+	// ClusterIssuer or Issuer does not exist, with an IssuerNotFound event,
+	// rather than creating listeners whose Secret is never issued. The
+	// issuer types must be registered with AddIssuersToScheme.
+	ValidateIssuer bool
+	// AllowedSecretNamespaces are the namespaces besides the Gateway's that
+	// a route's tls-secret-namespace annotation may name. Any other namespace
+	// is refused with a SecretNamespaceNotAllowed event, so a route cannot
+	// point listeners at Secrets of arbitrary namespaces.
+	AllowedSecretNamespaces []string
+	// ForceIssuerKind, ClusterIssuer or Issuer, overrides the issuerRef kind
+	// of created Certificates, which otherwise follows the issuer annotation
+	// a route uses.
+	ForceIssuerKind string
+	// ManageReferenceGrants creates, in each namespace whose Secrets
+	// listeners reference across namespaces, a ReferenceGrant allowing the
+	// Gateway's namespace to, and deletes it once no listener of a managed
+	// Gateway references a Secret there. The ReferenceGrant type must be
+	// registered with AddReferenceGrantsToScheme.
+	ManageReferenceGrants bool
+	// ReferenceGrantNamespaces are the namespaces ManageReferenceGrants
+	// may create or update ReferenceGrants in. A grant any other namespace
+	// would need is refused with a ReferenceGrantNotAllowed event. Empty
+	// allows every namespace listeners reference Secrets in.
+	ReferenceGrantNamespaces []string
+
+	// configMu guards AllowedDomainSuffix and ValidatedNSPrefix against
+	// updates from the watched ConfigMap.
+	configMu sync.RWMutex
+
+	fingerprints    fingerprintCache
+	gatewayLimiters gatewayLimiters
+	eventThrottle   eventThrottle
+
+	allowedHostnames allowedHostnamesCache
 }
 
-func (r *HTTPRouteReconciler) hasCertAnnotation(httpRoute *gatewayv1.HTTPRoute) bool {
-	if _, ok := httpRoute.Annotations[clusterIssuerAnnotation]; ok {
+// shouldManage reports whether the route asks for listeners: it names a
+// cert-manager issuer, an existing certificate Secret to reference, or
+// passthrough listeners, which need no certificate.
+func (r *HTTPRouteReconciler) shouldManage(route client.Object) bool {
+	if _, ok := route.GetAnnotations()[clusterIssuerAnnotation]; ok {
 		return true
 	}
-	if _, ok := httpRoute.Annotations[issuerAnnotation]; ok {
+	if _, ok := route.GetAnnotations()[issuerAnnotation]; ok {
 		return true
 	}
+	return hasSecretOverride(route) || isPassthrough(route)
+}
+
+// targetsManagedGateway reports whether the route attaches to at least one
+// managed Gateway, see routeGateways.
+func (r *HTTPRouteReconciler) targetsManagedGateway(httpRoute *gatewayv1.HTTPRoute) bool {
+	return len(r.routeGateways(httpRoute)) > 0
+}
+
+// tracksManagedGateway reports whether the route still tracks listeners on
+// any managed Gateway.
+func (r *HTTPRouteReconciler) tracksManagedGateway(httpRoute *gatewayv1.HTTPRoute) bool {
+	for _, key := range r.managedGateways() {
+		if _, tracked := httpRoute.Annotations[r.managedHostnamesKey(key)]; tracked {
+			return true
+		}
+	}
 	return false
 }
 
-func (r *HTTPRouteReconciler) validateHostname(ctx context.Context, hostname, namespace string) error {
-	if r.ValidatedNSPrefix == "" {
-		return nil
+// isDryRun reports whether the route's listener changes are only to be
+// reported, either because DryRun is set or the route asks for it. A route
+// being deleted no longer gets its own way: its finalizer goes with it, so
+// its listeners are removed rather than orphaned.
+func (r *HTTPRouteReconciler) isDryRun(route client.Object) bool {
+	if r.DryRun {
+		return true
 	}
+	return route.GetDeletionTimestamp() == nil && route.GetAnnotations()[dryRunAnnotation] == "true"
+}
 
-	if !strings.HasPrefix(namespace, r.ValidatedNSPrefix) {
+// listenerProtocol returns the protocol of the route's listeners: HTTPS by
+// default, or TLS when the protocol annotation asks for it. Both terminate
+// TLS with the certificate ref. Invalid values are reported and ignored.
+func (r *HTTPRouteReconciler) listenerProtocol(ctx context.Context, httpRoute *gatewayv1.HTTPRoute) gatewayv1.ProtocolType {
+	value, ok := httpRoute.Annotations[protocolAnnotation]
+	if !ok {
+		return gatewayv1.HTTPSProtocolType
+	}
+	switch protocol := gatewayv1.ProtocolType(value); protocol {
+	case gatewayv1.HTTPSProtocolType, gatewayv1.TLSProtocolType:
+		return protocol
+	}
+	log.FromContext(ctx).Info("ignoring invalid protocol annotation", "protocol", value)
+	r.Recorder.Eventf(httpRoute, corev1.EventTypeWarning, "InvalidProtocol",
+		"annotation %s must be %s or %s, got %q; using %s", protocolAnnotation,
+		gatewayv1.HTTPSProtocolType, gatewayv1.TLSProtocolType, value, gatewayv1.HTTPSProtocolType)
+	return gatewayv1.HTTPSProtocolType
+}
+
+// isValidatedNamespace reports whether hostnames in the namespace are subject
+// to validation, either by name prefix or by label selector.
+func (r *HTTPRouteReconciler) isValidatedNamespace(ns *corev1.Namespace) bool {
+	if _, prefix := r.validationPolicy(); prefix != "" && strings.HasPrefix(ns.Name, prefix) {
+		return true
+	}
+	return r.ValidatedNSSelector != nil && r.ValidatedNSSelector.Matches(labels.Set(ns.Labels))
+}
+
+// requiresValidation reports whether the named namespace is validated. The
+// namespace is only fetched when the prefix alone does not decide it; a
+// namespace already gone carries no labels, so no policy applies to it.
+func (r *HTTPRouteReconciler) requiresValidation(ctx context.Context, namespace string) (bool, error) {
+	if _, prefix := r.validationPolicy(); prefix != "" && strings.HasPrefix(namespace, prefix) {
+		return true, nil
+	}
+	if r.ValidatedNSSelector == nil {
+		return false, nil
+	}
+
+	var ns corev1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get namespace: %w", err)
+	}
+	return r.isValidatedNamespace(&ns), nil
+}
+
+func (r *HTTPRouteReconciler) validateHostname(ctx context.Context, hostname, namespace string) error {
+	hostname = trimTrailingDots(hostname)
+	validated, err := r.requiresValidation(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	if !validated {
 		return nil
 	}
 
-	if r.AllowedDomainSuffix != "" {
-		defaultSuffix := fmt.Sprintf(".%s.%s", namespace, r.AllowedDomainSuffix)
-		if strings.HasSuffix(hostname, defaultSuffix) {
+	if suffix, _ := r.validationPolicy(); suffix != "" {
+		suffix = trimTrailingDots(suffix)
+		if r.isDefaultHostname(hostname, namespace, suffix) {
+			return nil
+		}
+		if r.AllowApex && hostname == suffix {
 			return nil
 		}
 	}
 
+	// A namespace gone mid-deletion has no allowed-hostnames annotation left,
+	// so only the default subdomain applies
 	var ns corev1.Namespace
-	if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
-		return fmt.Errorf("failed to get namespace: %w", err)
+	nsErr := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns)
+	if nsErr != nil && !apierrors.IsNotFound(nsErr) {
+		return fmt.Errorf("failed to get namespace: %w", nsErr)
+	}
+	if nsErr != nil {
+		log.FromContext(ctx).V(1).Info("namespace not found, no custom hostnames allowed", "namespace", namespace)
 	}
 
-	if r.AllowedHostnamesAnnotation != "" {
-		allowedHostnames := ns.Annotations[r.AllowedHostnamesAnnotation]
-		if allowedHostnames != "" {
-			for _, allowed := range strings.Split(allowedHostnames, ",") {
-				allowed = strings.TrimSpace(allowed)
-				if hostname == allowed || strings.HasSuffix(hostname, "."+allowed) {
-					return nil
+	if r.AllowedHostnamesAnnotation != "" && nsErr == nil {
+		entries, malformed := r.allowedHostnames.parse(&ns, r.AllowedHostnamesAnnotation)
+		if r.MaxAllowedHostnames > 0 && len(entries) > r.MaxAllowedHostnames {
+			log.FromContext(ctx).Info("evaluating only the first allowed-hostnames entries",
+				"namespace", namespace, "entries", len(entries), "limit", r.MaxAllowedHostnames)
+			r.Recorder.Eventf(&ns, corev1.EventTypeWarning, "TooManyAllowedHostnames",
+				"annotation %s has %d entries, only the first %d are evaluated",
+				r.AllowedHostnamesAnnotation, len(entries), r.MaxAllowedHostnames)
+			entries = entries[:r.MaxAllowedHostnames]
+		}
+		if malformed {
+			log.FromContext(ctx).V(1).Info("ignoring malformed entries in allowed-hostnames annotation",
+				"namespace", namespace, "annotation", r.AllowedHostnamesAnnotation)
+			if r.StrictAllowedHostnames {
+				r.Recorder.Eventf(&ns, corev1.EventTypeWarning, "MalformedAllowedHostnames",
+					"annotation %s contains empty or malformed entries: %q",
+					r.AllowedHostnamesAnnotation, ns.Annotations[r.AllowedHostnamesAnnotation])
+			}
+		}
+		for _, allowed := range entries {
+			matched, err := r.allowedHostnames.match(ns.Name, hostname, allowed)
+			if err != nil {
+				log.FromContext(ctx).Info("skipping invalid allowed-hostnames entry",
+					"namespace", namespace, "entry", allowed, "reason", err.Error())
+				continue
+			}
+			if !matched {
+				continue
+			}
+			if r.RejectHostnameClaimConflicts {
+				// The claim belongs to the hostname, so an older namespace
+				// claiming it through any kind of entry owns it
+				owner, err := r.hostnameClaimOwner(ctx, hostname)
+				if err != nil {
+					return err
+				}
+				if owner != "" && owner != namespace {
+					return fmt.Errorf("%w: %s is claimed by namespace %s", errHostnameClaimConflict, hostname, owner)
 				}
 			}
+			return nil
 		}
 	}
 
-	return fmt.Errorf("hostname %s not allowed for namespace %s", hostname, namespace)
+	return fmt.Errorf("hostname %s %w for namespace %s", hostname, errHostnameNotAllowed, namespace)
 }
 
 func (r *HTTPRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -93,17 +450,51 @@ func (r *HTTPRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	if !r.hasCertAnnotation(&httpRoute) {
+	// Tag every log line of this reconcile, including in helpers, with the
+	// route's UID so recreations under the same name can be told apart.
+	log = log.WithValues("uid", httpRoute.UID)
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	if !r.shouldManage(&httpRoute) {
+		return ctrl.Result{}, nil
+	}
+
+	summary := &reconcileSummary{}
+	if r.ReconcileSummaryLog {
+		defer summary.log(log)
+	}
+
+	// Ignored routes get no finalizer or listeners, and give up any they had
+	if isIgnored(&httpRoute) {
+		r.fingerprints.forget(req.NamespacedName)
+		r.eventThrottle.forget(req.NamespacedName)
+		if err := r.releaseIgnoredRoute(ctx, &httpRoute, summary); err != nil {
+			if delay, ok := throttleDelay(err); ok {
+				log.V(1).Info("gateway mutation throttled, requeueing", "after", delay)
+				return ctrl.Result{RequeueAfter: delay}, nil
+			}
+			return ctrl.Result{}, err
+		}
 		return ctrl.Result{}, nil
 	}
 
 	// Handle deletion
 	if !httpRoute.DeletionTimestamp.IsZero() {
-		if controllerutil.ContainsFinalizer(&httpRoute, finalizerName) {
-			if err := r.removeListeners(ctx, &httpRoute); err != nil {
+		r.fingerprints.forget(req.NamespacedName)
+		r.eventThrottle.forget(req.NamespacedName)
+		if controllerutil.ContainsFinalizer(&httpRoute, r.finalizer()) {
+			if err := r.removeListeners(ctx, &httpRoute, summary); err != nil {
+				if delay, ok := throttleDelay(err); ok {
+					log.V(1).Info("gateway mutation throttled, requeueing", "after", delay)
+					return ctrl.Result{RequeueAfter: delay}, nil
+				}
 				return ctrl.Result{}, err
 			}
-			controllerutil.RemoveFinalizer(&httpRoute, finalizerName)
+			// Routes stay terminating until dry-run is turned off
+			if r.DryRun {
+				return ctrl.Result{}, nil
+			}
+			controllerutil.RemoveFinalizer(&httpRoute, r.finalizer())
 			if err := r.Update(ctx, &httpRoute); err != nil {
 				return ctrl.Result{}, err
 			}
@@ -111,60 +502,412 @@ func (r *HTTPRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, nil
 	}
 
-	// Add finalizer if not present
-	if !controllerutil.ContainsFinalizer(&httpRoute, finalizerName) {
-		controllerutil.AddFinalizer(&httpRoute, finalizerName)
-		if err := r.Update(ctx, &httpRoute); err != nil {
+	r.checkGatewayAnnotation(ctx, &httpRoute)
+	if !r.targetsManagedGateway(&httpRoute) {
+		if !controllerutil.ContainsFinalizer(&httpRoute, r.finalizer()) && !r.tracksManagedGateway(&httpRoute) {
+			log.V(1).Info("route does not target the managed gateway, skipping")
+			return ctrl.Result{}, nil
+		}
+		// The route moved away from every managed Gateway: give up the
+		// listeners it still tracks and its finalizer
+		r.fingerprints.forget(req.NamespacedName)
+		released, err := r.releaseRoute(ctx, &httpRoute, summary)
+		if delay, ok := throttleDelay(err); ok {
+			log.V(1).Info("gateway mutation throttled, requeueing", "after", delay)
+			return ctrl.Result{RequeueAfter: delay}, nil
+		}
+		if err != nil {
 			return ctrl.Result{}, err
 		}
+		if released {
+			log.Info("route no longer targets a managed gateway, released its listeners")
+			r.Recorder.Event(&httpRoute, corev1.EventTypeNormal, "RouteDetached",
+				"route no longer targets a managed Gateway, released its listeners")
+		}
+		return ctrl.Result{}, nil
 	}
 
-	if err := r.reconcileListeners(ctx, &httpRoute); err != nil {
+	// Add finalizer if not present. The update triggers another reconcile,
+	// which provisions the listeners.
+	if !controllerutil.ContainsFinalizer(&httpRoute, r.finalizer()) && !r.DryRun {
+		if err := r.addFinalizer(ctx, req.NamespacedName); err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		// Observe-only writes nothing, so no update event would follow
+		if !r.ObserveOnly {
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// Skip the work when nothing the outcome depends on changed since the
+	// last clean reconcile
+	fingerprint, err := r.reconcileFingerprint(ctx, &httpRoute)
+	r.setGatewayNotFoundCondition(ctx, &httpRoute, err)
+	if result, ok := r.gatewayNotFoundResult(ctx, err); ok {
+		return result, nil
+	}
+	if err != nil {
+		log.Error(err, "failed to fingerprint route")
+		return ctrl.Result{}, err
+	}
+	if r.fingerprints.unchanged(req.NamespacedName, fingerprint) {
+		log.V(1).Info("route and gateway unchanged, skipping")
+		// Certificate status changes are not part of the fingerprint
+		if err := r.setCertificateReadyCondition(ctx, &httpRoute); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: r.RequeueAfterSuccess}, nil
+	}
+
+	result, err := r.reconcileListeners(ctx, &httpRoute, summary)
+	if delay, ok := throttleDelay(err); ok {
+		log.V(1).Info("gateway mutation throttled, requeueing", "after", delay)
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+	r.setGatewayNotFoundCondition(ctx, &httpRoute, err)
+	if result, ok := r.gatewayNotFoundResult(ctx, err); ok {
+		return result, nil
+	}
+	if err != nil {
 		log.Error(err, "failed to reconcile listeners")
 		return ctrl.Result{}, err
 	}
+	if err := r.setListenersReadyCondition(ctx, &httpRoute, summary); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.setCertificateReadyCondition(ctx, &httpRoute); err != nil {
+		return ctrl.Result{}, err
+	}
+	if result.RequeueAfter > 0 {
+		return result, nil
+	}
+	r.fingerprints.store(req.NamespacedName, fingerprint)
 
-	return ctrl.Result{}, nil
+	// A zero RequeueAfterSuccess leaves the route to the next watch event.
+	return ctrl.Result{RequeueAfter: r.RequeueAfterSuccess}, nil
 }
 
-func (r *HTTPRouteReconciler) reconcileListeners(ctx context.Context, httpRoute *gatewayv1.HTTPRoute) error {
+// includeHostname reports whether the hostname matches the global include
+// pattern, recording an event for excluded hostnames.
+func (r *HTTPRouteReconciler) includeHostname(ctx context.Context, httpRoute *gatewayv1.HTTPRoute, hostname string) bool {
+	if r.HostnameIncludePattern == nil || r.HostnameIncludePattern.MatchString(hostname) {
+		return true
+	}
+	log.FromContext(ctx).Info("hostname excluded by include pattern", "hostname", hostname)
+	r.Recorder.Eventf(httpRoute, corev1.EventTypeNormal, "HostnameExcludedByFilter",
+		"hostname %s does not match the include pattern %s", hostname, r.HostnameIncludePattern)
+	return false
+}
+
+// admitNames reports whether the hostname's listener and secret names render
+// to valid names, recording an event when they do not.
+func (r *HTTPRouteReconciler) admitNames(ctx context.Context, httpRoute *gatewayv1.HTTPRoute, hostname string) bool {
+	err := r.checkHostnameNames(hostname)
+	if err == nil {
+		return true
+	}
+	log.FromContext(ctx).Error(err, "invalid generated name", "hostname", hostname)
+	r.Recorder.Eventf(httpRoute, corev1.EventTypeWarning, "InvalidGeneratedName",
+		"skipping hostname %s: %v", hostname, err)
+	return false
+}
+
+// admitHostname runs hostname validation for a route and reports rejections.
+// In shadow mode rejections are only logged, recorded as WouldRejectHostname
+// events and counted, and the hostname is admitted. Errors looking the policy
+// up are returned, so a transient failure never counts as a rejection.
+func (r *HTTPRouteReconciler) admitHostname(ctx context.Context, httpRoute *gatewayv1.HTTPRoute, gateway *gatewayv1.Gateway, hostname string) (bool, error) {
 	log := log.FromContext(ctx)
 
+	err := r.validateHostname(ctx, hostname, httpRoute.Namespace)
+	if err == nil {
+		return true, nil
+	}
+	if !isPolicyRejection(err) {
+		return false, err
+	}
+
+	if r.ValidationShadowMode {
+		log.Info("shadow mode: hostname would be rejected", "hostname", hostname, "reason", err.Error())
+		if r.allowHostnameEvent(httpRoute, hostname) {
+			r.Recorder.Eventf(httpRoute, corev1.EventTypeWarning, "WouldRejectHostname",
+				"hostname %s would be rejected for namespace %s: %v", hostname, httpRoute.Namespace, err)
+		}
+		hostnamesWouldRejectTotal.WithLabelValues(r.namespaceClass(httpRoute.Namespace)).Inc()
+		return true, nil
+	}
+
+	log.Error(err, "hostname validation failed", "hostname", hostname)
+	if !r.allowHostnameEvent(httpRoute, hostname) {
+		log.V(1).Info("hostname validation event throttled", "hostname", hostname)
+	} else if errors.Is(err, errHostnameClaimConflict) {
+		r.recordListenerEvent(httpRoute, gateway, corev1.EventTypeWarning, "HostnameClaimConflict",
+			"hostname %s not allowed for namespace %s: %v", hostname, httpRoute.Namespace, err)
+	} else {
+		r.Recorder.Eventf(httpRoute, corev1.EventTypeWarning, "HostnameValidationFailed",
+			"hostname %s not allowed for namespace %s", hostname, httpRoute.Namespace)
+	}
+	return false, nil
+}
+
+// addFinalizer adds the finalizer to a freshly fetched copy of the route,
+// retrying on conflicts so a concurrent change to the route doesn't leave it
+// without a finalizer until the next event.
+func (r *HTTPRouteReconciler) addFinalizer(ctx context.Context, key types.NamespacedName) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var httpRoute gatewayv1.HTTPRoute
+		if err := r.Get(ctx, key, &httpRoute); err != nil {
+			return err
+		}
+		if !controllerutil.AddFinalizer(&httpRoute, r.finalizer()) {
+			return nil
+		}
+		return r.Update(ctx, &httpRoute)
+	})
+}
+
+// reconcileListeners provisions the route's listeners on every managed
+// Gateway it attaches to, and prunes them from managed Gateways it no longer
+// attaches to.
+func (r *HTTPRouteReconciler) reconcileListeners(ctx context.Context, httpRoute *gatewayv1.HTTPRoute, summary *reconcileSummary) (ctrl.Result, error) {
+	targeted := r.routeGateways(httpRoute)
+	// On an issuer switch this pass only removes the listeners; the route
+	// update recording the new issuer triggers the pass re-adding them.
+	recreate := r.issuerChanged(httpRoute)
+
+	var pendingSecrets []string
+	for _, key := range r.managedGateways() {
+		attached := slices.Contains(targeted, key)
+		if _, tracked := httpRoute.Annotations[r.managedHostnamesKey(key)]; !attached && !tracked {
+			continue
+		}
+		var pending []string
+		err := retryGatewayConflicts(summary, func() error {
+			var err error
+			pending, err = r.reconcileGatewayListeners(ctx, httpRoute, key, attached && !recreate, summary)
+			return err
+		})
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		pendingSecrets = append(pendingSecrets, pending...)
+	}
+
+	// Catch up with listener annotations edited by hand or written by older
+	// versions; regular changes are written along with the bookkeeping
+	if !r.isDryRun(httpRoute) && r.syncListenersAnnotation(httpRoute) {
+		if err := r.Update(ctx, httpRoute); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update httproute annotation: %w", err)
+		}
+	}
+
+	if r.RecreateOnIssuerChange && !r.isDryRun(httpRoute) {
+		if err := r.recordIssuer(ctx, httpRoute); err != nil {
+			return ctrl.Result{}, err
+		}
+		if recreate {
+			return ctrl.Result{}, nil
+		}
+	}
+
+	if r.ExternalSecretCheck && !r.isDryRun(httpRoute) {
+		if err := r.updateSecretSyncCondition(ctx, httpRoute, pendingSecrets); err != nil {
+			return ctrl.Result{}, err
+		}
+		if len(pendingSecrets) > 0 {
+			return ctrl.Result{RequeueAfter: secretSyncRequeueInterval}, nil
+		}
+	}
+
+	if summary.issuerMissing {
+		return ctrl.Result{RequeueAfter: issuerRequeueInterval}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileGatewayListeners reconciles the route's listeners on one managed
+// Gateway. Listeners of a route no longer attached to the Gateway are all
+// stale. It returns the certificate secrets whose ExternalSecret is not
+// synced yet.
+func (r *HTTPRouteReconciler) reconcileGatewayListeners(ctx context.Context, httpRoute *gatewayv1.HTTPRoute,
+	key types.NamespacedName, attached bool, summary *reconcileSummary) ([]string, error) {
+	log := log.FromContext(ctx).WithValues("gateway", key)
+
 	var gateway gatewayv1.Gateway
-	if err := r.Get(ctx, types.NamespacedName{
-		Name:      r.GatewayName,
-		Namespace: r.GatewayNamespace,
-	}, &gateway); err != nil {
-		return fmt.Errorf("failed to get gateway: %w", err)
+	if err := r.getGateway(ctx, key, &gateway); err != nil {
+		return nil, err
 	}
 
 	existingListeners := make(map[string]bool)
+	existingHostnames := make(map[string]string)
+	usedPorts := make(map[gatewayv1.PortNumber]bool)
+	secretHostnames := make(map[string]string)
 	for _, l := range gateway.Spec.Listeners {
 		existingListeners[string(l.Name)] = true
+		if l.Hostname != nil {
+			existingHostnames[string(l.Name)] = string(*l.Hostname)
+			if l.TLS != nil {
+				for _, ref := range l.TLS.CertificateRefs {
+					secretHostnames[string(ref.Name)] = string(*l.Hostname)
+				}
+			}
+		}
+		usedPorts[l.Port] = true
 	}
 
-	// Build set of current desired listener names
-	currentListeners := make(map[string]bool)
-	for _, hostname := range httpRoute.Spec.Hostnames {
-		currentListeners[hostnameToListenerName(string(hostname))] = true
+	var hostnames []string
+	ports, portErr := r.routeListenerPorts(httpRoute)
+	if attached {
+		var err error
+		if hostnames, err = r.routeHostnames(httpRoute); err != nil {
+			return nil, err
+		}
+		if portErr != nil {
+			log.Info("ignoring invalid listener port annotation", "reason", portErr.Error())
+			r.Recorder.Eventf(httpRoute, corev1.EventTypeWarning, "InvalidListenerPort",
+				"%v; using port %d", portErr, ports[0])
+		}
+	}
+	annotationKey := r.managedHostnamesKey(key)
+
+	// Validate hostnames up front so both removal and addition see the verdict
+	admitted := make(map[string]bool)
+	excluded := make(map[string]bool)
+	for _, hostname := range hostnames {
+		if _, ok := admitted[hostname]; !ok {
+			excluded[hostname] = !r.includeHostname(ctx, httpRoute, hostname)
+			admitted[hostname] = !excluded[hostname] && r.admitNames(ctx, httpRoute, hostname)
+			if admitted[hostname] {
+				ok, err := r.admitHostname(ctx, httpRoute, &gateway, hostname)
+				if err != nil {
+					return nil, fmt.Errorf("failed to validate hostname %s: %w", hostname, err)
+				}
+				admitted[hostname] = ok
+			}
+			if !admitted[hostname] {
+				summary.validationFailures++
+			}
+			if !admitted[hostname] && !excluded[hostname] {
+				summary.rejectedHostnames = insertSorted(summary.rejectedHostnames, hostname)
+			}
+		}
 	}
 
 	// Determine previously managed listeners from annotation
 	previousListeners := make(map[string]bool)
-	if prev := httpRoute.Annotations[managedHostnamesAnnotation]; prev != "" {
+	if prev := httpRoute.Annotations[annotationKey]; prev != "" {
 		for _, name := range strings.Split(prev, ",") {
 			previousListeners[name] = true
 		}
 	}
 
-	// Remove stale listeners (previously managed but no longer desired)
-	gwPatch := client.MergeFrom(gateway.DeepCopy())
+	// Build set of current desired listener names. When enforcing policy
+	// changes, listeners of hostnames that are no longer allowed are not
+	// desired and get pruned below; otherwise the route keeps tracking them,
+	// but never starts tracking a hostname it was not admitted for, as the
+	// tracked listeners keep shared listeners alive. Hostnames outside the
+	// include pattern are never tracked, as another instance may own their
+	// listeners.
+	currentListeners := make(map[string]bool)
+	disallowedListeners := make(map[string]string)
+	redirect := r.httpRedirect(httpRoute)
+	for _, hostname := range hostnames {
+		if excluded[hostname] {
+			continue
+		}
+		for _, name := range r.hostnameListenerNames(hostname, ports, redirect) {
+			if r.EnforceOnPolicyChange && !admitted[hostname] {
+				disallowedListeners[name] = hostname
+				continue
+			}
+			if !admitted[hostname] && !previousListeners[name] {
+				continue
+			}
+			currentListeners[name] = true
+		}
+	}
+
+	dryRun := r.isDryRun(httpRoute)
+	tlsOptions := r.listenerTLSOptions(ctx, &gateway, httpRoute)
+	passthrough := attached && r.listenerTLSMode(ctx, httpRoute) == gatewayv1.TLSModePassthrough
+	omitCertRefs := attached && !passthrough && r.omitCertificateRefs(ctx, &gateway, httpRoute, tlsOptions)
+	var protocol gatewayv1.ProtocolType
+	var routeNamespaces *gatewayv1.RouteNamespaces
+	secretNamespace, secretOverride := gateway.Namespace, ""
+	if attached {
+		protocol = r.listenerProtocol(ctx, httpRoute)
+		if !passthrough {
+			secretNamespace, secretOverride = r.listenerSecretRef(ctx, &gateway, httpRoute)
+		}
+		if !passthrough && secretOverride == "" && isAggregateCert(httpRoute) {
+			var shared []string
+			for hostname, ok := range admitted {
+				if ok {
+					shared = append(shared, hostname)
+				}
+			}
+			if len(shared) > 0 {
+				sort.Strings(shared)
+				secretOverride = r.aggregateSecretName(shared)
+			}
+		}
+		var err error
+		if routeNamespaces, err = r.routeNamespaces(ctx, httpRoute); err != nil {
+			return nil, err
+		}
+	}
+
+	var issuerMissing bool
+	if attached && r.ValidateIssuer && !passthrough && !hasSecretOverride(httpRoute) {
+		exists, err := r.issuerExists(ctx, httpRoute, gateway.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		issuerMissing = !exists
+	}
+
+	// Remove stale listeners (previously managed but no longer desired),
+	// keeping those another route still references. Only listeners the
+	// controller created are removed; the route merely stops tracking manual
+	// ones.
+	created, recorded := createdListeners(&gateway, r.managedListenersKey())
+	owns := func(name string) bool {
+		return previousListeners[name] && (!recorded || created[name])
+	}
+	stale := func(l gatewayv1.Listener) bool {
+		return owns(string(l.Name)) && !currentListeners[string(l.Name)]
+	}
+	var references map[string]bool
+	if slices.ContainsFunc(gateway.Spec.Listeners, stale) {
+		var err error
+		if references, err = r.listenerReferences(ctx, httpRoute, key); err != nil {
+			return nil, err
+		}
+	}
+	original := gateway.DeepCopy()
 	var removed int
-	var newGWListeners []gatewayv1.Listener
+	var removedNames []string
+	// Never nil, so the patch carries an empty array rather than null
+	newGWListeners := make([]gatewayv1.Listener, 0, len(gateway.Spec.Listeners))
 	for _, l := range gateway.Spec.Listeners {
 		name := string(l.Name)
-		if previousListeners[name] && !currentListeners[name] {
-			log.Info("removing stale listener", "listener", name)
+		if stale(l) && references[name] {
+			log.Info("keeping stale listener referenced by another route", "listener", name)
+		} else if stale(l) {
+			if dryRun {
+				log.Info("dry-run: would remove stale listener", "listener", name)
+				r.Recorder.Eventf(httpRoute, corev1.EventTypeNormal, "DryRunRemoveListener",
+					"would remove listener %s", name)
+			} else {
+				log.Info("removing stale listener", "listener", name)
+				removedNames = append(removedNames, name)
+				if hostname, ok := disallowedListeners[name]; ok {
+					r.Recorder.Eventf(httpRoute, corev1.EventTypeWarning, "HostnameNoLongerAllowed",
+						"removed listener %s: hostname %s is no longer allowed for namespace %s",
+						name, hostname, httpRoute.Namespace)
+				}
+			}
 			removed++
 			continue
 		}
@@ -173,65 +916,326 @@ func (r *HTTPRouteReconciler) reconcileListeners(ctx context.Context, httpRoute
 
 	// Add new listeners
 	var added int
-	for _, hostname := range httpRoute.Spec.Hostnames {
-		if err := r.validateHostname(ctx, string(hostname), httpRoute.Namespace); err != nil {
-			log.Error(err, "hostname validation failed", "hostname", hostname)
-			r.Recorder.Eventf(httpRoute, corev1.EventTypeWarning, "HostnameValidationFailed",
-				"hostname %s not allowed for namespace %s", string(hostname), httpRoute.Namespace)
+	var addedNames []string
+	var driftKinds []string
+	var correctedNames []string
+	correctedKinds := make(map[string][]string)
+	addedListeners := make(map[string]string)
+	var pendingSecrets []string
+	secretSynced := make(map[string]bool)
+	// nameTaken reports whether another listener, e.g. of a different
+	// hostname whose name truncates the same way, already holds the name.
+	// Such a listener is never adopted: it is left untracked and the
+	// conflict is reported.
+	nameTaken := func(listenerName, hostname string) bool {
+		if !existingListeners[listenerName] || previousListeners[listenerName] || existingHostnames[listenerName] == hostname {
+			return false
+		}
+		log.Info("listener name taken by a different hostname", "listener", listenerName,
+			"hostname", hostname, "existingHostname", existingHostnames[listenerName])
+		if r.allowListenerEvent(httpRoute, &gateway, hostname, "ListenerNameConflict") {
+			r.recordListenerEvent(httpRoute, &gateway, corev1.EventTypeWarning, "ListenerNameConflict",
+				"listener %s for hostname %s already exists on Gateway %s/%s for hostname %q",
+				listenerName, hostname, gateway.Namespace, gateway.Name, existingHostnames[listenerName])
+		}
+		delete(currentListeners, listenerName)
+		return true
+	}
+	// atLimit reports whether the Gateway has no room for another listener,
+	// remembering the hostname so the skipped additions are reported
+	// together. The skipped listener is left untracked and retried once the
+	// Gateway changes.
+	var overLimit []string
+	atLimit := func(listenerName, hostname string) bool {
+		if r.MaxListeners <= 0 || len(newGWListeners) < r.MaxListeners {
+			return false
+		}
+		log.Info("gateway listener limit reached, skipping listener", "listener", listenerName,
+			"hostname", hostname, "maxListeners", r.MaxListeners)
+		overLimit = insertSorted(overLimit, hostname)
+		delete(currentListeners, listenerName)
+		return true
+	}
+	// covered reports whether a wildcard listener already on the Gateway
+	// serves the hostname on the port, when SkipWildcardCovered is set. The
+	// skipped listener is left untracked, so it is created should the
+	// wildcard go away.
+	reportedCovered := make(map[string]bool)
+	covered := func(listenerName, hostname string, port gatewayv1.PortNumber) bool {
+		if !r.SkipWildcardCovered {
+			return false
+		}
+		wildcard := coveringWildcard(gateway.Spec.Listeners, hostname, port)
+		if wildcard == "" {
+			return false
+		}
+		log.V(1).Info("hostname covered by wildcard listener, skipping listener", "listener", listenerName,
+			"hostname", hostname, "wildcard", wildcard)
+		if !reportedCovered[hostname] {
+			reportedCovered[hostname] = true
+			r.Recorder.Eventf(httpRoute, corev1.EventTypeNormal, "HostnameCoveredByWildcard",
+				"hostname %s is served by wildcard listener %s on Gateway %s/%s; no listener added",
+				hostname, wildcard, gateway.Namespace, gateway.Name)
+		}
+		delete(currentListeners, listenerName)
+		return true
+	}
+	// noIssuer reports whether the route's issuer is missing, when
+	// ValidateIssuer is set. The skipped listener is left untracked and the
+	// route requeued, so it is created once the issuer exists.
+	reportedIssuer := make(map[string]bool)
+	noIssuer := func(listenerName, hostname string) bool {
+		if !issuerMissing {
+			return false
+		}
+		log.Info("issuer not found, skipping listener", "listener", listenerName,
+			"hostname", hostname, "issuer", routeIssuer(httpRoute))
+		if !reportedIssuer[hostname] {
+			reportedIssuer[hostname] = true
+			r.Recorder.Eventf(httpRoute, corev1.EventTypeWarning, "IssuerNotFound",
+				"%s not found; no listener added for hostname %s", r.describeIssuer(httpRoute, gateway.Namespace), hostname)
+		}
+		delete(currentListeners, listenerName)
+		summary.issuerMissing = true
+		return true
+	}
+	for _, hostname := range hostnames {
+		if !admitted[hostname] {
 			continue
 		}
 
-		listenerName := hostnameToListenerName(string(hostname))
-		if existingListeners[listenerName] && !previousListeners[listenerName] {
-			log.V(1).Info("listener already exists", "listener", listenerName)
-			continue
+		for _, port := range ports {
+			listenerName := r.listenerName(hostname, port)
+			if nameTaken(listenerName, hostname) {
+				continue
+			}
+			if existingListeners[listenerName] && !owns(listenerName) {
+				log.V(1).Info("listener already exists", "listener", listenerName)
+				continue
+			}
+			if existingListeners[listenerName] && !r.CorrectListenerDrift {
+				continue
+			}
+			if existingListeners[listenerName] {
+				// The route owns the listener: patch manual edits back
+				i := slices.IndexFunc(newGWListeners, func(l gatewayv1.Listener) bool { return string(l.Name) == listenerName })
+				if i < 0 {
+					continue
+				}
+				existing := &newGWListeners[i]
+				secretName := r.hostnameToSecretName(hostname)
+				if secretOverride != "" {
+					secretName = secretOverride
+				} else if existing.TLS != nil && len(existing.TLS.CertificateRefs) > 0 &&
+					string(existing.TLS.CertificateRefs[0].Name) == r.disambiguatedSecretName(hostname) {
+					secretName = r.disambiguatedSecretName(hostname)
+				}
+				desired := r.buildListener(listenerName, hostname, port, secretNamespace, secretName, tlsOptions)
+				desired.Protocol = protocol
+				desired.AllowedRoutes.Namespaces = routeNamespaces.DeepCopy()
+				if omitCertRefs {
+					desired.TLS.CertificateRefs = nil
+				}
+				if passthrough {
+					makePassthrough(&desired)
+				}
+				kinds := correctListenerDrift(existing, desired)
+				if len(kinds) == 0 {
+					continue
+				}
+				if dryRun {
+					log.Info("dry-run: would correct listener drift", "listener", listenerName, "drift", kinds)
+					continue
+				}
+				log.Info("correcting listener drift", "listener", listenerName, "drift", kinds)
+				driftKinds = append(driftKinds, kinds...)
+				correctedNames = append(correctedNames, listenerName)
+				correctedKinds[listenerName] = kinds
+				continue
+			}
+			if covered(listenerName, hostname, port) || noIssuer(listenerName, hostname) {
+				continue
+			}
+
+			secretName := r.hostnameToSecretName(hostname)
+			if secretOverride != "" {
+				secretName = secretOverride
+			} else if other, ok := secretHostnames[secretName]; ok && other != hostname && !passthrough {
+				// Sanitizing is lossy, e.g. a.b.com and a-b.com share a-b-com-tls
+				log.Info("secret name already used by another hostname", "secret", secretName,
+					"hostname", hostname, "otherHostname", other)
+				r.recordListenerEvent(httpRoute, &gateway, corev1.EventTypeWarning, "SecretNameCollision",
+					"secret %s of hostname %s is already used by hostname %s on Gateway %s/%s",
+					secretName, hostname, other, gateway.Namespace, gateway.Name)
+				if r.DisambiguateSecretNames {
+					secretName = r.disambiguatedSecretName(hostname)
+				}
+			}
+			secretHostnames[secretName] = hostname
+			if r.ExternalSecretCheck && !omitCertRefs && !passthrough {
+				synced, checked := secretSynced[secretName]
+				if !checked {
+					var err error
+					if synced, err = r.externalSecretSynced(ctx, secretNamespace, secretName); err != nil {
+						return nil, err
+					}
+					secretSynced[secretName] = synced
+					if !synced {
+						log.Info("waiting for externalsecret to sync", "secret", secretName, "hostname", hostname)
+						pendingSecrets = append(pendingSecrets, secretName)
+					}
+				}
+				if !synced {
+					continue
+				}
+			}
+			listener := r.buildListener(listenerName, hostname, port, secretNamespace, secretName, tlsOptions)
+			listener.Protocol = protocol
+			listener.AllowedRoutes.Namespaces = routeNamespaces.DeepCopy()
+			if omitCertRefs {
+				listener.TLS.CertificateRefs = nil
+			}
+			if passthrough {
+				makePassthrough(&listener)
+			}
+			if r.ValidateListeners {
+				if err := validateListener(listener); err != nil {
+					log.Info("skipping invalid listener", "listener", listenerName, "hostname", hostname, "reason", err.Error())
+					r.recordListenerEvent(httpRoute, &gateway, corev1.EventTypeWarning, "InvalidListener",
+						"skipping listener %s for hostname %s: %s", listenerName, hostname, strings.ReplaceAll(err.Error(), "\n", "; "))
+					delete(currentListeners, listenerName)
+					continue
+				}
+			}
+			if atLimit(listenerName, hostname) {
+				continue
+			}
+			newGWListeners = append(newGWListeners, listener)
+			addedListeners[listenerName] = hostname
+			added++
+			if r.ReportNewListenerPorts && !usedPorts[listener.Port] {
+				usedPorts[listener.Port] = true
+				r.Recorder.Eventf(httpRoute, corev1.EventTypeNormal, "NewListenerPort",
+					"listener %s uses port %d, which no other listener on Gateway %s/%s uses; make sure the Gateway implementation exposes it",
+					listenerName, listener.Port, gateway.Namespace, gateway.Name)
+			}
+			if dryRun {
+				log.Info("dry-run: would add listener", "listener", listenerName, "hostname", hostname, "secret", secretName)
+				r.Recorder.Eventf(httpRoute, corev1.EventTypeNormal, "DryRunAddListener",
+					"would add listener %s for hostname %s", listenerName, hostname)
+			} else {
+				log.Info("adding listener", "listener", listenerName, "hostname", hostname, "secret", secretName)
+				addedNames = append(addedNames, listenerName)
+			}
 		}
-		if existingListeners[listenerName] && previousListeners[listenerName] {
+
+		if !redirect {
 			continue
 		}
-
-		secretName := hostnameToSecretName(string(hostname))
-		ns := gatewayv1.Namespace(r.GatewayNamespace)
-		hostnameVal := gatewayv1.Hostname(hostname)
-		tlsMode := gatewayv1.TLSModeTerminate
-		allowAll := gatewayv1.NamespacesFromAll
-
-		listener := gatewayv1.Listener{
-			Name:     gatewayv1.SectionName(listenerName),
-			Hostname: &hostnameVal,
-			Port:     443,
-			Protocol: gatewayv1.HTTPSProtocolType,
-			AllowedRoutes: &gatewayv1.AllowedRoutes{
-				Namespaces: &gatewayv1.RouteNamespaces{
-					From: &allowAll,
-				},
-			},
-			TLS: &gatewayv1.ListenerTLSConfig{
-				Mode: &tlsMode,
-				CertificateRefs: []gatewayv1.SecretObjectReference{
-					{
-						Name:      gatewayv1.ObjectName(secretName),
-						Namespace: &ns,
-					},
-				},
-			},
+		listenerName := r.httpListenerName(hostname)
+		if nameTaken(listenerName, hostname) || existingListeners[listenerName] ||
+			covered(listenerName, hostname, httpRedirectPort) || noIssuer(listenerName, hostname) ||
+			atLimit(listenerName, hostname) {
+			continue
 		}
-		newGWListeners = append(newGWListeners, listener)
+		newGWListeners = append(newGWListeners, buildHTTPListener(listenerName, hostname, routeNamespaces.DeepCopy()))
+		addedListeners[listenerName] = hostname
 		added++
-		log.Info("adding listener", "listener", listenerName, "hostname", hostname, "secret", secretName)
+		if dryRun {
+			log.Info("dry-run: would add HTTP listener", "listener", listenerName, "hostname", hostname)
+			r.Recorder.Eventf(httpRoute, corev1.EventTypeNormal, "DryRunAddListener",
+				"would add listener %s for hostname %s", listenerName, hostname)
+		} else {
+			log.Info("adding HTTP listener", "listener", listenerName, "hostname", hostname)
+			addedNames = append(addedNames, listenerName)
+		}
+	}
+
+	if len(overLimit) > 0 {
+		r.Recorder.Eventf(httpRoute, corev1.EventTypeWarning, "ListenerLimitExceeded",
+			"Gateway %s/%s is at its limit of %d listeners; no listeners added for hostnames %s",
+			gateway.Namespace, gateway.Name, r.MaxListeners, strings.Join(overLimit, ", "))
 	}
 
-	if added > 0 || removed > 0 {
+	// Dry-run routes only report the diff; neither the Gateway nor the
+	// managed-hostnames bookkeeping is touched.
+	if dryRun {
+		return nil, nil
+	}
+
+	// A Gateway predating the managed-listeners annotation gets it on the
+	// first reconcile of a route with listeners on it
+	seed := !recorded && len(currentListeners) > 0
+	if added > 0 || removed > 0 || len(driftKinds) > 0 || seed {
 		gateway.Spec.Listeners = newGWListeners
 		if gateway.Labels == nil {
 			gateway.Labels = make(map[string]string)
 		}
 		gateway.Labels[managedByLabel] = managedByValue
-		if err := r.Patch(ctx, &gateway, gwPatch); err != nil {
-			return fmt.Errorf("failed to patch gateway: %w", err)
+
+		// The route owns its current listeners it created, not manual ones
+		// that happened to exist under the same name.
+		owned := make(map[string]bool)
+		for name := range currentListeners {
+			if _, added := addedListeners[name]; added || owns(name) {
+				owned[name] = true
+			}
+		}
+		managed, err := r.listenerOwnership(ctx, httpRoute, annotationKey, owned)
+		if err != nil {
+			return nil, err
+		}
+		orderManagedListeners(gateway.Spec.Listeners, managed)
+		r.recordCreatedListeners(&gateway, original, managed, addedNames, removedNames)
+		if err := r.patchGateway(ctx, &gateway, original, managed); err != nil {
+			return nil, err
+		}
+		if err := r.pruneCertificates(ctx, original, &gateway); err != nil {
+			return nil, err
+		}
+		if err := r.syncReferenceGrants(ctx, httpRoute, original, &gateway); err != nil {
+			return nil, err
+		}
+		summary.added += added
+		summary.removed += removed
+		summary.gatewayPatched = true
+		for _, name := range removedNames {
+			r.recordListenerEvent(httpRoute, &gateway, corev1.EventTypeNormal, "ListenerRemoved",
+				"removed listener %s", name)
+		}
+		for _, name := range addedNames {
+			r.recordListenerEvent(httpRoute, &gateway, corev1.EventTypeNormal, "ListenerCreated",
+				"created listener %s for hostname %s", name, addedListeners[name])
+		}
+		for _, name := range correctedNames {
+			r.recordListenerEvent(httpRoute, &gateway, corev1.EventTypeNormal, "ListenerDriftCorrected",
+				"corrected %s of listener %s", strings.Join(correctedKinds[name], ", "), name)
+		}
+		listenersCreatedTotal.WithLabelValues(r.namespaceClass(httpRoute.Namespace)).Add(float64(added))
+		for _, kind := range driftKinds {
+			driftCorrectionsTotal.WithLabelValues(kind).Inc()
 		}
 	}
 
+	for _, l := range gateway.Spec.Listeners {
+		if currentListeners[string(l.Name)] {
+			summary.listeners = insertSorted(summary.listeners, string(l.Name))
+		}
+	}
+
+	certificateListeners := make(map[string]bool)
+	sharedListeners := make(map[string]bool)
+	for name := range currentListeners {
+		if _, added := addedListeners[name]; added || owns(name) {
+			certificateListeners[name] = true
+		} else {
+			sharedListeners[name] = true
+		}
+	}
+	if err := r.ensureCertificates(ctx, httpRoute, &gateway, certificateListeners, sharedListeners); err != nil {
+		return nil, err
+	}
+
 	// Update the managed-hostnames annotation on the HTTPRoute
 	var managedNames []string
 	for name := range currentListeners {
@@ -240,107 +1244,474 @@ func (r *HTTPRouteReconciler) reconcileListeners(ctx context.Context, httpRoute
 	sort.Strings(managedNames)
 	newAnnotation := strings.Join(managedNames, ",")
 
-	if httpRoute.Annotations[managedHostnamesAnnotation] != newAnnotation {
+	prev, tracked := httpRoute.Annotations[annotationKey]
+	switch {
+	case !attached && newAnnotation == "" && tracked:
+		// The route left the Gateway; drop its bookkeeping
+		delete(httpRoute.Annotations, annotationKey)
+		r.syncListenersAnnotation(httpRoute)
+		if err := r.Update(ctx, httpRoute); err != nil {
+			return nil, fmt.Errorf("failed to update httproute annotation: %w", err)
+		}
+	case prev != newAnnotation:
 		if httpRoute.Annotations == nil {
 			httpRoute.Annotations = make(map[string]string)
 		}
-		httpRoute.Annotations[managedHostnamesAnnotation] = newAnnotation
+		httpRoute.Annotations[annotationKey] = newAnnotation
+		r.syncListenersAnnotation(httpRoute)
 		if err := r.Update(ctx, httpRoute); err != nil {
-			return fmt.Errorf("failed to update httproute annotation: %w", err)
+			return nil, fmt.Errorf("failed to update httproute annotation: %w", err)
+		}
+	}
+
+	return pendingSecrets, nil
+}
+
+// syncListenersAnnotation sets listenersAnnotation to the sorted listener
+// names the route owns on any managed Gateway, reporting whether it changed.
+// The caller writes the route.
+func (r *HTTPRouteReconciler) syncListenersAnnotation(httpRoute *gatewayv1.HTTPRoute) bool {
+	var names []string
+	for _, key := range r.managedGateways() {
+		if value := httpRoute.Annotations[r.managedHostnamesKey(key)]; value != "" {
+			names = append(names, strings.Split(value, ",")...)
 		}
 	}
+	sort.Strings(names)
+	value := strings.Join(slices.Compact(names), ",")
 
+	annotationKey := instanceKey(listenersAnnotation, r.InstanceID)
+	current, ok := httpRoute.Annotations[annotationKey]
+	switch {
+	case ok && current == value, !ok && value == "":
+		return false
+	case value == "":
+		delete(httpRoute.Annotations, annotationKey)
+	default:
+		if httpRoute.Annotations == nil {
+			httpRoute.Annotations = make(map[string]string)
+		}
+		httpRoute.Annotations[annotationKey] = value
+	}
+	return true
+}
+
+// routeHostnames returns the route's non-empty hostnames in ASCII form,
+// skipping invalid internationalized names and handling empty
+// entries according to OnEmptyHostname.
+func (r *HTTPRouteReconciler) routeHostnames(httpRoute *gatewayv1.HTTPRoute) ([]string, error) {
+	var hostnames []string
+	var empty bool
+	for _, hostname := range httpRoute.Spec.Hostnames {
+		if hostname == "" {
+			empty = true
+			continue
+		}
+		normalized, err := normalizeHostname(string(hostname))
+		if err != nil {
+			r.Recorder.Eventf(httpRoute, corev1.EventTypeWarning, "InvalidHostname",
+				"ignoring hostname %s: %v", hostname, err)
+			continue
+		}
+		hostnames = append(hostnames, normalized)
+	}
+	if !empty {
+		return hostnames, nil
+	}
+
+	switch r.OnEmptyHostname {
+	case EmptyHostnameError:
+		return nil, fmt.Errorf("route %s/%s lists an empty hostname", httpRoute.Namespace, httpRoute.Name)
+	case EmptyHostnameEvent:
+		r.Recorder.Event(httpRoute, corev1.EventTypeWarning, "EmptyHostname",
+			"route lists an empty hostname, ignoring it")
+	}
+	return hostnames, nil
+}
+
+// removeListeners removes the route's listeners from every managed Gateway
+// it attaches to or still tracks listeners on.
+func (r *HTTPRouteReconciler) removeListeners(ctx context.Context, httpRoute *gatewayv1.HTTPRoute, summary *reconcileSummary) error {
+	targeted := r.routeGateways(httpRoute)
+	for _, key := range r.managedGateways() {
+		attached := slices.Contains(targeted, key)
+		if _, tracked := httpRoute.Annotations[r.managedHostnamesKey(key)]; !attached && !tracked {
+			continue
+		}
+		err := retryGatewayConflicts(summary, func() error {
+			return r.removeGatewayListeners(ctx, httpRoute, key, attached, summary)
+		})
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (r *HTTPRouteReconciler) removeListeners(ctx context.Context, httpRoute *gatewayv1.HTTPRoute) error {
-	log := log.FromContext(ctx)
+// releaseRoute removes the listeners of a route the controller stops
+// managing, then drops its bookkeeping annotations and finalizer. Dry-run
+// routes keep them and report false.
+func (r *HTTPRouteReconciler) releaseRoute(ctx context.Context, httpRoute *gatewayv1.HTTPRoute, summary *reconcileSummary) (bool, error) {
+	if err := r.removeListeners(ctx, httpRoute, summary); err != nil {
+		return false, err
+	}
+	if r.isDryRun(httpRoute) {
+		return false, nil
+	}
+
+	for _, key := range r.managedGateways() {
+		delete(httpRoute.Annotations, r.managedHostnamesKey(key))
+	}
+	delete(httpRoute.Annotations, instanceKey(listenersAnnotation, r.InstanceID))
+	delete(httpRoute.Annotations, instanceKey(managedIssuerAnnotation, r.InstanceID))
+	controllerutil.RemoveFinalizer(httpRoute, r.finalizer())
+	if err := r.Update(ctx, httpRoute); err != nil {
+		return false, fmt.Errorf("failed to release httproute: %w", err)
+	}
+	return true, nil
+}
+
+func (r *HTTPRouteReconciler) removeGatewayListeners(ctx context.Context, httpRoute *gatewayv1.HTTPRoute,
+	key types.NamespacedName, attached bool, summary *reconcileSummary) error {
+	log := log.FromContext(ctx).WithValues("gateway", key)
 
 	var gateway gatewayv1.Gateway
-	if err := r.Get(ctx, types.NamespacedName{
-		Name:      r.GatewayName,
-		Namespace: r.GatewayNamespace,
-	}, &gateway); err != nil {
+	if err := r.Get(ctx, key, &gateway); err != nil {
 		return client.IgnoreNotFound(err)
 	}
 
-	listenersToRemove := make(map[string]bool)
-	// Include current hostnames
+	// Remove listeners of current hostnames on every configured port, unless
+	// the name is held by a different hostname's listener, plus everything
+	// tracked in the annotation. Hostnames outside the include pattern are
+	// left to whichever instance manages them.
+	currentHostnames := make(map[string]string)
+	ports, _ := r.routeListenerPorts(httpRoute)
+	redirect := r.httpRedirect(httpRoute)
 	for _, hostname := range httpRoute.Spec.Hostnames {
-		listenersToRemove[hostnameToListenerName(string(hostname))] = true
+		if hostname == "" || !attached {
+			continue
+		}
+		normalized, err := normalizeHostname(string(hostname))
+		if err != nil {
+			continue
+		}
+		if r.HostnameIncludePattern != nil && !r.HostnameIncludePattern.MatchString(normalized) {
+			continue
+		}
+		for _, name := range r.hostnameListenerNames(normalized, ports, redirect) {
+			currentHostnames[name] = normalized
+		}
 	}
-	// Include previously managed hostnames from annotation
-	if prev := httpRoute.Annotations[managedHostnamesAnnotation]; prev != "" {
+	annotationKey := r.managedHostnamesKey(key)
+	tracked := make(map[string]bool)
+	if prev := httpRoute.Annotations[annotationKey]; prev != "" {
 		for _, name := range strings.Split(prev, ",") {
-			listenersToRemove[name] = true
+			tracked[name] = true
+		}
+	}
+	// Listeners the controller did not create are never removed
+	created, recorded := createdListeners(&gateway, r.managedListenersKey())
+	shouldRemove := func(l gatewayv1.Listener) bool {
+		if recorded && !created[string(l.Name)] {
+			return false
+		}
+		if tracked[string(l.Name)] {
+			return true
+		}
+		hostname, ok := currentHostnames[string(l.Name)]
+		return ok && l.Hostname != nil && string(*l.Hostname) == hostname
+	}
+	// Listeners another route still references outlive this one
+	var references map[string]bool
+	if slices.ContainsFunc(gateway.Spec.Listeners, shouldRemove) {
+		var err error
+		if references, err = r.listenerReferences(ctx, httpRoute, key); err != nil {
+			return err
 		}
 	}
 
-	patch := client.MergeFrom(gateway.DeepCopy())
+	original := gateway.DeepCopy()
+	dryRun := r.isDryRun(httpRoute)
 
-	var newListeners []gatewayv1.Listener
+	var removedNames []string
+	// Never nil, so the patch carries an empty array rather than null
+	newListeners := make([]gatewayv1.Listener, 0, len(gateway.Spec.Listeners))
 	for _, l := range gateway.Spec.Listeners {
-		if listenersToRemove[string(l.Name)] {
-			log.Info("removing listener", "listener", l.Name)
+		if shouldRemove(l) && references[string(l.Name)] {
+			log.Info("keeping listener referenced by another route", "listener", l.Name)
+		} else if shouldRemove(l) {
+			if dryRun {
+				log.Info("dry-run: would remove listener", "listener", l.Name)
+				r.Recorder.Eventf(httpRoute, corev1.EventTypeNormal, "DryRunRemoveListener",
+					"would remove listener %s", string(l.Name))
+			} else {
+				log.Info("removing listener", "listener", l.Name)
+				removedNames = append(removedNames, string(l.Name))
+			}
 			continue
 		}
 		newListeners = append(newListeners, l)
 	}
 
-	if len(newListeners) == len(gateway.Spec.Listeners) {
+	if dryRun || len(newListeners) == len(gateway.Spec.Listeners) {
 		return nil
 	}
 
 	gateway.Spec.Listeners = newListeners
-	if err := r.Patch(ctx, &gateway, patch); err != nil {
-		return fmt.Errorf("failed to patch gateway: %w", err)
+	managed, err := r.listenerOwnership(ctx, httpRoute, annotationKey, nil)
+	if err != nil {
+		return err
+	}
+	r.recordCreatedListeners(&gateway, original, managed, nil, removedNames)
+	if err := r.patchGateway(ctx, &gateway, original, managed); err != nil {
+		return err
+	}
+	if err := r.pruneCertificates(ctx, original, &gateway); err != nil {
+		return err
+	}
+	if err := r.syncReferenceGrants(ctx, httpRoute, original, &gateway); err != nil {
+		return err
+	}
+	summary.removed += len(removedNames)
+	summary.gatewayPatched = true
+	for _, name := range removedNames {
+		r.recordListenerEvent(httpRoute, &gateway, corev1.EventTypeNormal, "ListenerRemoved",
+			"removed listener %s", name)
 	}
-
 	return nil
 }
 
-func hostnameToListenerName(hostname string) string {
-	sanitized := strings.ReplaceAll(hostname, ".", "-")
-	sanitized = strings.ReplaceAll(sanitized, "*", "wildcard")
-	return fmt.Sprintf("https-%s", sanitized)
+// normalizeHostname converts an internationalized hostname to its punycode
+// ASCII form and drops the trailing dot of a fully qualified name, so
+// listeners, secrets and validation all see the same name. A leading wildcard
+// label is kept as is.
+func normalizeHostname(hostname string) (string, error) {
+	hostname = trimTrailingDots(hostname)
+	if isASCII(hostname) {
+		return hostname, nil
+	}
+	prefix, name := "", hostname
+	if rest, ok := strings.CutPrefix(hostname, "*."); ok {
+		prefix, name = "*.", rest
+	}
+	ascii, err := idna.Lookup.ToASCII(name)
+	if err != nil {
+		return "", fmt.Errorf("invalid internationalized hostname: %w", err)
+	}
+	return prefix + ascii, nil
 }
 
-func hostnameToSecretName(hostname string) string {
-	sanitized := strings.ReplaceAll(hostname, ".", "-")
-	sanitized = strings.ReplaceAll(sanitized, "*", "wildcard")
-	return fmt.Sprintf("%s-tls", sanitized)
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// trimTrailingDots strips the root label dots of a fully qualified hostname
+// such as example.com.
+func trimTrailingDots(hostname string) string {
+	return strings.TrimRight(hostname, ".")
+}
+
+// buildListener constructs the HTTPS listener for a hostname on a port,
+// terminating TLS with the given certificate secret.
+func (r *HTTPRouteReconciler) buildListener(name, hostname string, port gatewayv1.PortNumber, secretNamespace, secretName string,
+	tlsOptions map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue) gatewayv1.Listener {
+	ns := gatewayv1.Namespace(secretNamespace)
+	hostnameVal := gatewayv1.Hostname(hostname)
+	tlsMode := gatewayv1.TLSModeTerminate
+	allowAll := gatewayv1.NamespacesFromAll
+
+	return gatewayv1.Listener{
+		Name:     gatewayv1.SectionName(name),
+		Hostname: &hostnameVal,
+		Port:     port,
+		Protocol: gatewayv1.HTTPSProtocolType,
+		AllowedRoutes: &gatewayv1.AllowedRoutes{
+			Namespaces: &gatewayv1.RouteNamespaces{
+				From: &allowAll,
+			},
+		},
+		TLS: &gatewayv1.ListenerTLSConfig{
+			Mode: &tlsMode,
+			CertificateRefs: []gatewayv1.SecretObjectReference{
+				{
+					Name:      gatewayv1.ObjectName(secretName),
+					Namespace: &ns,
+				},
+			},
+			Options: tlsOptions,
+		},
+	}
+}
+
+// orderManagedListeners stably reorders the managed listeners so exact
+// hostnames come before wildcards, for implementations where listener order
+// decides matching precedence. Managed listeners only trade places among
+// themselves, so manual listeners keep their positions.
+func orderManagedListeners(listeners []gatewayv1.Listener, managed func(name string) bool) {
+	var slots []int
+	var ordered []gatewayv1.Listener
+	for i, l := range listeners {
+		if managed(string(l.Name)) {
+			slots = append(slots, i)
+			ordered = append(ordered, l)
+		}
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return !isWildcardListener(ordered[i]) && isWildcardListener(ordered[j])
+	})
+	for i, slot := range slots {
+		listeners[slot] = ordered[i]
+	}
+}
+
+func isWildcardListener(l gatewayv1.Listener) bool {
+	return l.Hostname != nil && strings.HasPrefix(string(*l.Hostname), "*.")
+}
+
+// listenerPorts returns the ports listeners are created on, the first being
+// the primary port.
+func (r *HTTPRouteReconciler) listenerPorts() []gatewayv1.PortNumber {
+	if len(r.ListenerPorts) != 0 {
+		return r.ListenerPorts
+	}
+	if r.DefaultListenerPort != 0 {
+		return []gatewayv1.PortNumber{r.DefaultListenerPort}
+	}
+	return []gatewayv1.PortNumber{defaultListenerPort}
+}
+
+// routeListenerPorts returns the ports the route's listeners are created on:
+// the single port of the listener-port annotation, or listenerPorts when the
+// annotation is absent. An invalid annotation falls back to listenerPorts
+// and is returned as an error for the caller to report.
+func (r *HTTPRouteReconciler) routeListenerPorts(httpRoute *gatewayv1.HTTPRoute) ([]gatewayv1.PortNumber, error) {
+	value, ok := httpRoute.Annotations[listenerPortAnnotation]
+	if !ok {
+		return r.listenerPorts(), nil
+	}
+	port, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || port < 1 || port > 65535 {
+		return r.listenerPorts(), fmt.Errorf("annotation %s must be a port between 1 and 65535, got %q", listenerPortAnnotation, value)
+	}
+	return []gatewayv1.PortNumber{gatewayv1.PortNumber(port)}, nil
+}
+
+// listenerName returns the listener name for a hostname on a port, shortened
+// to MaxListenerNameLength when set. Listeners on the primary port keep the
+// plain hostname-derived name; other ports are qualified with the port number.
+func (r *HTTPRouteReconciler) listenerName(hostname string, port gatewayv1.PortNumber) string {
+	name := r.hostnameToListenerName(hostname)
+	if port != r.listenerPorts()[0] {
+		name = fmt.Sprintf("%s-%d", name, port)
+	}
+	return truncateName(name, r.MaxListenerNameLength)
+}
+
+// truncateName shortens name to at most maxLen characters by cutting it and
+// appending a hash of the full name, so distinct long names stay distinct and
+// the result is stable. A maxLen of zero disables truncation.
+func truncateName(name string, maxLen int) string {
+	if maxLen <= 0 || len(name) <= maxLen {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:nameHashLength]
+	prefix := strings.TrimRight(name[:maxLen-nameHashLength-1], "-")
+	return prefix + "-" + hash
 }
 
 func (r *HTTPRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if r.ObserveOnly {
+		r.enableObserveOnly()
+	}
+	if err := setupIndexes(context.Background(), mgr.GetFieldIndexer(), r.AllowedHostnamesAnnotation); err != nil {
+		return fmt.Errorf("failed to set up indexes: %w", err)
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&gatewayv1.HTTPRoute{}).
-		Watches(&gatewayv1.Gateway{}, handler.EnqueueRequestsFromMapFunc(r.gatewayToHTTPRoutes)).
-		Complete(r)
+		Watches(&gatewayv1.Gateway{}, handler.EnqueueRequestsFromMapFunc(r.gatewayToHTTPRoutes),
+			builder.WithPredicates(r.managedGatewayPredicate())).
+		Watches(&corev1.Namespace{}, r.namespacePolicyHandler(),
+			builder.WithPredicates(r.namespacePolicyPredicate()))
+	if r.ManageCertificates {
+		cert := &unstructured.Unstructured{}
+		cert.SetGroupVersionKind(certificateGVK)
+		b = b.Watches(cert, handler.EnqueueRequestsFromMapFunc(r.certificateToHTTPRoutes),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetLabels()[managedByLabel] == managedByValue
+			})))
+	}
+	if r.ConfigMap.Name != "" {
+		b = b.Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.configMapToHTTPRoutes),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetName() == r.ConfigMap.Name && obj.GetNamespace() == r.ConfigMap.Namespace
+			})))
+	}
+	return b.Complete(r)
 }
 
-// gatewayToHTTPRoutes maps a Gateway event back to all HTTPRoutes that reference it,
-// enabling re-reconciliation when a managed listener is manually deleted.
+// managedGatewayPredicate drops events for Gateways other than the managed
+// one before they reach the work queue mapping.
+func (r *HTTPRouteReconciler) managedGatewayPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(r.isManagedGateway)
+}
+
+// isManagedGateway reports whether obj is one of the Gateways this controller
+// manages.
+func (r *HTTPRouteReconciler) isManagedGateway(obj client.Object) bool {
+	return slices.Contains(r.managedGateways(), client.ObjectKeyFromObject(obj))
+}
+
+// gatewayToHTTPRoutes maps a Gateway event back to the HTTPRoutes that attach
+// to it or still track listeners on it, enabling re-reconciliation when a
+// managed listener is manually deleted.
 func (r *HTTPRouteReconciler) gatewayToHTTPRoutes(ctx context.Context, obj client.Object) []reconcile.Request {
 	gateway, ok := obj.(*gatewayv1.Gateway)
 	if !ok {
 		return nil
 	}
 
-	if gateway.Name != r.GatewayName || gateway.Namespace != r.GatewayNamespace {
+	if !r.isManagedGateway(gateway) {
 		return nil
 	}
 
+	key := client.ObjectKeyFromObject(gateway)
+	return r.managedRouteRequests(ctx, func(route *gatewayv1.HTTPRoute) bool {
+		if _, tracked := route.Annotations[r.managedHostnamesKey(key)]; tracked {
+			return true
+		}
+		return slices.Contains(r.routeGateways(route), key)
+	})
+}
+
+// managedRouteRequests lists reconcile requests for every route the
+// controller manages that matches, a nil match accepting every route. The
+// list options narrow the routes listed, e.g. to a namespace.
+func (r *HTTPRouteReconciler) managedRouteRequests(ctx context.Context, match func(route *gatewayv1.HTTPRoute) bool,
+	opts ...client.ListOption) []reconcile.Request {
 	var httpRouteList gatewayv1.HTTPRouteList
-	if err := r.List(ctx, &httpRouteList); err != nil {
+	if err := r.List(ctx, &httpRouteList, opts...); err != nil {
 		return nil
 	}
 
 	var requests []reconcile.Request
 	for _, route := range httpRouteList.Items {
-		if !r.hasCertAnnotation(&route) {
+		if !r.shouldManage(&route) || isIgnored(&route) {
+			continue
+		}
+		if !controllerutil.ContainsFinalizer(&route, r.finalizer()) {
+			continue
+		}
+		if !r.targetsManagedGateway(&route) && !r.tracksManagedGateway(&route) {
 			continue
 		}
-		if !controllerutil.ContainsFinalizer(&route, finalizerName) {
+		if match != nil && !match(&route) {
 			continue
 		}
 		requests = append(requests, reconcile.Request{
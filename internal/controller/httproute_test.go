@@ -2,18 +2,30 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-logr/logr/funcr"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -22,6 +34,7 @@ func init() {
 }
 
 func TestHostnameToListenerName(t *testing.T) {
+	r := newReconciler()
 	tests := []struct {
 		hostname string
 		expected string
@@ -32,11 +45,13 @@ func TestHostnameToListenerName(t *testing.T) {
 		{"a.b.c.d.example.com", "https-a-b-c-d-example-com"},
 		{"example", "https-example"},
 		{"", "https-"},
+		{"example.com.", "https-example-com"},
+		{"*.example.com.", "https-wildcard-example-com"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.hostname, func(t *testing.T) {
-			result := hostnameToListenerName(tt.hostname)
+			result := r.hostnameToListenerName(tt.hostname)
 			if result != tt.expected {
 				t.Errorf("hostnameToListenerName(%q) = %q, want %q", tt.hostname, result, tt.expected)
 			}
@@ -45,6 +60,7 @@ func TestHostnameToListenerName(t *testing.T) {
 }
 
 func TestHostnameToSecretName(t *testing.T) {
+	r := newReconciler()
 	tests := []struct {
 		hostname string
 		expected string
@@ -55,11 +71,13 @@ func TestHostnameToSecretName(t *testing.T) {
 		{"a.b.c.d.example.com", "a-b-c-d-example-com-tls"},
 		{"example", "example-tls"},
 		{"", "-tls"},
+		{"example.com.", "example-com-tls"},
+		{"sub.example.com.", "sub-example-com-tls"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.hostname, func(t *testing.T) {
-			result := hostnameToSecretName(tt.hostname)
+			result := r.hostnameToSecretName(tt.hostname)
 			if result != tt.expected {
 				t.Errorf("hostnameToSecretName(%q) = %q, want %q", tt.hostname, result, tt.expected)
 			}
@@ -67,19 +85,71 @@ func TestHostnameToSecretName(t *testing.T) {
 	}
 }
 
+func TestListenerName_MaxLength(t *testing.T) {
+	r := newReconciler()
+	long := "a-very-long-subdomain-name-for-testing.team-with-a-long-name.example.com"
+
+	tests := []struct {
+		name     string
+		hostname string
+		maxLen   int
+	}{
+		{"within limit", "app.example.com", 63},
+		{"exactly at limit", "abcdefghijklmnopqrstuvwxyz-abcdefghijklmnopqrstuvwxyz.com", 63},
+		{"beyond limit", long, 63},
+		{"short limit", long, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r.MaxListenerNameLength = tt.maxLen
+			full := r.hostnameToListenerName(tt.hostname)
+			name := r.listenerName(tt.hostname, 443)
+
+			if len(name) > tt.maxLen {
+				t.Errorf("listenerName(%q) = %q exceeds %d characters", tt.hostname, name, tt.maxLen)
+			}
+			if len(full) <= tt.maxLen && name != full {
+				t.Errorf("listenerName(%q) = %q, want untouched %q", tt.hostname, name, full)
+			}
+			if len(full) > tt.maxLen && (name == full || name[len(name)-nameHashLength-1] != '-') {
+				t.Errorf("listenerName(%q) = %q, want truncated name with hash suffix", tt.hostname, name)
+			}
+			if again := r.listenerName(tt.hostname, 443); again != name {
+				t.Errorf("listenerName(%q) is not stable: %q vs %q", tt.hostname, name, again)
+			}
+		})
+	}
+
+	r.MaxListenerNameLength = 40
+	other := strings.Replace(long, "testing", "staging", 1)
+	if r.listenerName(long, 443) == r.listenerName(other, 443) {
+		t.Error("distinct long hostnames should produce distinct truncated names")
+	}
+
+	r.MaxListenerNameLength = 0
+	if name := r.listenerName(long, 443); name != r.hostnameToListenerName(long) {
+		t.Errorf("zero max length should disable truncation, got %q", name)
+	}
+}
+
 func newReconciler(objs ...client.Object) *HTTPRouteReconciler {
 	cb := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...)
 	cb = cb.WithStatusSubresource(objs...)
+	cb = cb.WithIndex(&corev1.Namespace{}, hostnameClaimIndex, hostnameClaimIndexer("gateway-auto-listener/allowed-hostnames"))
 
 	return &HTTPRouteReconciler{
-		Client:                     cb.Build(),
-		Scheme:                     scheme.Scheme,
-		Recorder:                   record.NewFakeRecorder(10),
-		GatewayName:                "default",
-		GatewayNamespace:           "nginx-gateway",
-		AllowedDomainSuffix:        "example.com",
-		ValidatedNSPrefix:          "tenant-",
-		AllowedHostnamesAnnotation: "gateway-auto-listener/allowed-hostnames",
+		Client:                       cb.Build(),
+		Scheme:                       scheme.Scheme,
+		Recorder:                     record.NewFakeRecorder(100),
+		GatewayName:                  "default",
+		GatewayNamespace:             "nginx-gateway",
+		AllowedDomainSuffix:          "example.com",
+		ValidatedNSPrefix:            "tenant-",
+		AllowedHostnamesAnnotation:   "gateway-auto-listener/allowed-hostnames",
+		RejectHostnameClaimConflicts: true,
+		CorrectListenerDrift:         true,
+		MaxListenerNameLength:        63,
 	}
 }
 
@@ -120,6 +190,105 @@ func TestValidateHostname_TenantDefaultSuffix(t *testing.T) {
 	if err == nil {
 		t.Error("non-matching hostname should be rejected")
 	}
+
+	// A fully qualified name validates like its dotless form
+	if err := r.validateHostname(ctx, "app.tenant-123.example.com.", "tenant-123"); err != nil {
+		t.Errorf("trailing-dot default suffix hostname should be allowed, got: %v", err)
+	}
+	if err := r.validateHostname(ctx, "evil.other.com.", "tenant-123"); err == nil {
+		t.Error("trailing-dot non-matching hostname should be rejected")
+	}
+}
+
+func TestValidateHostname_DefaultHostnameTemplate(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-acme"}}
+	tests := []struct {
+		template string
+		hostname string
+		allowed  bool
+	}{
+		{DefaultHostnameTemplate, "app.tenant-acme.example.com", true},
+		{DefaultHostnameTemplate, "a.b.tenant-acme.example.com", true},
+		{DefaultHostnameTemplate, "app.tenant-acme--prod.example.com", false},
+		{DefaultHostnameTemplate, "app.eviltenant-acme.example.com", false},
+		{DefaultHostnameTemplate, "app.tenant-acmexexample.com", false},
+		{".{{.Namespace}}--*.{{.Suffix}}", "app.tenant-acme--prod.example.com", true},
+		{".{{.Namespace}}--*.{{.Suffix}}", "app.tenant-acme--staging.example.com", true},
+		{".{{.Namespace}}--*.{{.Suffix}}", "app.tenant-acme--prod.x.example.com", false},
+		{".{{.Namespace}}--*.{{.Suffix}}", "app.tenant-acme.example.com", false},
+		{".{{.Namespace}}--*.{{.Suffix}}", "app.tenant-other--prod.example.com", false},
+		{"{{.Namespace}}--*.{{.Suffix}}", "tenant-acme--dev.example.com", true},
+		{"{{.Namespace}}--*.{{.Suffix}}", "app.tenant-acme--dev.example.com", true},
+		{"{{.Namespace}}--*.{{.Suffix}}", "tenant-x-tenant-acme--dev.example.com", false},
+		{"{{.Namespace}}--*.{{.Suffix}}", "app.tenant-x-tenant-acme--dev.example.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template+" "+tt.hostname, func(t *testing.T) {
+			tmpl, err := ParseHostnameTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			r := newReconciler(ns)
+			r.DefaultHostnameTemplate = tmpl
+			err = r.validateHostname(context.Background(), tt.hostname, "tenant-acme")
+			if allowed := err == nil; allowed != tt.allowed {
+				t.Errorf("expected allowed=%v, got error: %v", tt.allowed, err)
+			}
+		})
+	}
+}
+
+func TestValidateHostname_Apex(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-123"}}
+	r := newReconciler(ns)
+	ctx := context.Background()
+
+	if err := r.validateHostname(ctx, "example.com", "tenant-123"); err == nil {
+		t.Error("apex hostname should be rejected without allow-apex")
+	}
+
+	r.AllowApex = true
+	if err := r.validateHostname(ctx, "example.com", "tenant-123"); err != nil {
+		t.Errorf("apex hostname should be allowed with allow-apex, got: %v", err)
+	}
+	// Only the exact suffix is the apex
+	for _, hostname := range []string{"other.example.com", "badexample.com"} {
+		if err := r.validateHostname(ctx, hostname, "tenant-123"); err == nil {
+			t.Errorf("%s should still be rejected with allow-apex", hostname)
+		}
+	}
+}
+
+func TestValidateHostname_LabelSelector(t *testing.T) {
+	labeled := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "team-a",
+		Labels: map[string]string{"tenant": "true"},
+	}}
+	unlabeled := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}}
+	r := newReconciler(labeled, unlabeled)
+	r.ValidatedNSPrefix = ""
+	r.ValidatedNSSelector = labels.SelectorFromSet(labels.Set{"tenant": "true"})
+	ctx := context.Background()
+
+	if err := r.validateHostname(ctx, "evil.other.com", "team-a"); err == nil {
+		t.Error("labeled namespace should be validated and reject foreign hostname")
+	}
+	if err := r.validateHostname(ctx, "app.team-a.example.com", "team-a"); err != nil {
+		t.Errorf("labeled namespace default suffix should be allowed, got: %v", err)
+	}
+	if err := r.validateHostname(ctx, "evil.other.com", "team-b"); err != nil {
+		t.Errorf("unlabeled namespace should not be validated, got: %v", err)
+	}
+	if err := r.validateHostname(ctx, "evil.other.com", "team-gone"); err != nil {
+		t.Errorf("missing namespace should have no policy, got: %v", err)
+	}
+
+	// The prefix still applies alongside the selector
+	r.ValidatedNSPrefix = "team-b"
+	if err := r.validateHostname(ctx, "evil.other.com", "team-b"); err == nil {
+		t.Error("prefix-matched namespace should be validated alongside the selector")
+	}
 }
 
 func TestValidateHostname_CustomDomains(t *testing.T) {
@@ -146,6 +315,12 @@ func TestValidateHostname_CustomDomains(t *testing.T) {
 		t.Errorf("subdomain of custom domain should be allowed, got: %v", err)
 	}
 
+	// Fully qualified subdomain match
+	err = r.validateHostname(ctx, "sub.custom.org.", "tenant-456")
+	if err != nil {
+		t.Errorf("trailing-dot subdomain of custom domain should be allowed, got: %v", err)
+	}
+
 	// Subdomain of second entry
 	err = r.validateHostname(ctx, "test.another.net", "tenant-456")
 	if err != nil {
@@ -159,6 +334,56 @@ func TestValidateHostname_CustomDomains(t *testing.T) {
 	}
 }
 
+func TestValidateHostname_MessyAnnotationLenient(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenant-messy",
+			Annotations: map[string]string{
+				"gateway-auto-listener/allowed-hostnames": ",custom.org,, ,another.net,",
+			},
+		},
+	}
+	r := newReconciler(ns)
+	fakeRecorder := record.NewFakeRecorder(10)
+	r.Recorder = fakeRecorder
+	ctx := context.Background()
+
+	if err := r.validateHostname(ctx, "sub.another.net", "tenant-messy"); err != nil {
+		t.Errorf("valid entry among empty segments should be allowed, got: %v", err)
+	}
+	if err := r.validateHostname(ctx, "evil.example.org", "tenant-messy"); err == nil {
+		t.Error("non-matching hostname should be rejected")
+	}
+	if events := drainEvents(fakeRecorder); len(events) != 0 {
+		t.Errorf("lenient parsing should not record events, got %v", events)
+	}
+}
+
+func TestValidateHostname_MessyAnnotationStrict(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenant-messy",
+			Annotations: map[string]string{
+				"gateway-auto-listener/allowed-hostnames": "custom.org,,",
+			},
+		},
+	}
+	r := newReconciler(ns)
+	r.StrictAllowedHostnames = true
+	fakeRecorder := record.NewFakeRecorder(10)
+	r.Recorder = fakeRecorder
+	ctx := context.Background()
+
+	if err := r.validateHostname(ctx, "custom.org", "tenant-messy"); err != nil {
+		t.Errorf("valid entry should still be allowed in strict mode, got: %v", err)
+	}
+
+	events := drainEvents(fakeRecorder)
+	if len(events) != 1 || !strings.HasPrefix(events[0], "Warning MalformedAllowedHostnames") {
+		t.Errorf("expected a MalformedAllowedHostnames event, got %v", events)
+	}
+}
+
 func TestValidateHostname_EmptyAllowedDomainSuffix(t *testing.T) {
 	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-789"}}
 	r := newReconciler(ns)
@@ -172,6 +397,36 @@ func TestValidateHostname_EmptyAllowedDomainSuffix(t *testing.T) {
 	}
 }
 
+func TestValidateHostname_NamespaceNotFound(t *testing.T) {
+	r := newReconciler()
+	ctx := context.Background()
+
+	err := r.validateHostname(ctx, "shop.acme.com", "tenant-gone")
+	if err == nil || err.Error() != "hostname shop.acme.com not allowed for namespace tenant-gone" {
+		t.Errorf("expected a missing namespace to reject custom hostnames as not allowed, got: %v", err)
+	}
+	if err := r.validateHostname(ctx, "app.tenant-gone.example.com", "tenant-gone"); err != nil {
+		t.Errorf("expected the default subdomain to be allowed, got: %v", err)
+	}
+}
+
+func TestValidateHostname_NamespaceGetError(t *testing.T) {
+	r := newReconciler()
+	r.Client = interceptor.NewClient(r.Client.(client.WithWatch), interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if _, ok := obj.(*corev1.Namespace); ok {
+				return apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, key.Name, errors.New("rbac"))
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	})
+
+	err := r.validateHostname(context.Background(), "shop.acme.com", "tenant-123")
+	if !apierrors.IsForbidden(err) || !strings.Contains(err.Error(), "failed to get namespace") {
+		t.Errorf("expected the namespace error to propagate, got: %v", err)
+	}
+}
+
 func TestReconcile_SkipWithoutAnnotation(t *testing.T) {
 	gateway := &gatewayv1.Gateway{
 		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
@@ -283,6 +538,84 @@ func TestReconcile_CreatesListener(t *testing.T) {
 	}
 }
 
+func TestReconcile_FinalizerAddedOnceUnderConflict(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-route",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"test.example.com"},
+		},
+	}
+
+	var updates int
+	c := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(gateway, httpRoute).
+		WithStatusSubresource(httpRoute).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				updates++
+				if updates == 1 {
+					return apierrors.NewConflict(schema.GroupResource{Group: "gateway.networking.k8s.io", Resource: "httproutes"},
+						obj.GetName(), errors.New("route changed"))
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	r := newReconciler()
+	r.Client = c
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "test-route", Namespace: "default"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updates != 2 {
+		t.Errorf("expected the conflicting update to be retried once, got %d updates", updates)
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, types.NamespacedName{Name: "test-route", Namespace: "default"}, &route)
+	if len(route.Finalizers) != 1 || route.Finalizers[0] != finalizerName {
+		t.Errorf("expected exactly one finalizer, got %v", route.Finalizers)
+	}
+
+	// The finalizer pass returns before provisioning listeners
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected no listeners after the finalizer pass, got %d", len(gw.Spec.Listeners))
+	}
+
+	// A further reconcile does not add the finalizer again
+	_, err = r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "test-route", Namespace: "default"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = r.Get(ctx, types.NamespacedName{Name: "test-route", Namespace: "default"}, &route)
+	if len(route.Finalizers) != 1 {
+		t.Errorf("expected exactly one finalizer after second pass, got %v", route.Finalizers)
+	}
+}
+
 func TestReconcile_IssuerAnnotation(t *testing.T) {
 	gateway := &gatewayv1.Gateway{
 		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
@@ -554,6 +887,75 @@ func TestReconcile_BootstrapSetsAnnotation(t *testing.T) {
 	}
 }
 
+func TestReconcile_BootstrapMultipleHostnames(t *testing.T) {
+	ns := gatewayv1.Namespace("nginx-gateway")
+	existingHostname := gatewayv1.Hostname("one.example.com")
+	tlsMode := gatewayv1.TLSModeTerminate
+
+	// Gateway already carries the listener for one of the hostnames
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners: []gatewayv1.Listener{
+				{
+					Name:     "https-one-example-com",
+					Hostname: &existingHostname,
+					Port:     443,
+					Protocol: gatewayv1.HTTPSProtocolType,
+					TLS: &gatewayv1.ListenerTLSConfig{
+						Mode: &tlsMode,
+						CertificateRefs: []gatewayv1.SecretObjectReference{
+							{Name: "one-example-com-tls", Namespace: &ns},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Pre-upgrade route with finalizer but no managed-hostnames annotation
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "multi-route",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"two.example.com", "one.example.com", "three.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	ctx := context.Background()
+
+	// Reconcile twice to verify the bootstrap is idempotent
+	for i := 0; i < 2; i++ {
+		_, err := r.Reconcile(ctx, ctrl.Request{
+			NamespacedName: types.NamespacedName{Name: "multi-route", Namespace: "default"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error on reconcile %d: %v", i+1, err)
+		}
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 3 {
+		t.Fatalf("expected 3 listeners without duplicates, got %d", len(gw.Spec.Listeners))
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, types.NamespacedName{Name: "multi-route", Namespace: "default"}, &route)
+	expected := "https-one-example-com,https-three-example-com,https-two-example-com"
+	if route.Annotations[managedHostnamesAnnotation] != expected {
+		t.Errorf("expected annotation %q, got %q", expected, route.Annotations[managedHostnamesAnnotation])
+	}
+}
+
 func TestReconcile_ManualListenerNotRemoved(t *testing.T) {
 	manualHostname := gatewayv1.Hostname("manual.example.com")
 	tlsMode := gatewayv1.TLSModeTerminate
@@ -686,17 +1088,1805 @@ func TestReconcile_DisallowedHostname_RecordsEvent(t *testing.T) {
 	}
 }
 
-func TestReconcile_NotFound(t *testing.T) {
-	r := newReconciler()
+func TestReconcile_EnforceOnPolicyChange(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tenant-acme",
+			Annotations: map[string]string{
+				"gateway-auto-listener/allowed-hostnames": "acme.com",
+			},
+		},
+	}
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "shop",
+			Namespace:  "tenant-acme",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"shop.acme.com", "app.tenant-acme.example.com"},
+		},
+	}
+
+	r := newReconciler(ns, gateway, httpRoute)
+	r.EnforceOnPolicyChange = true
 	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "shop", Namespace: "tenant-acme"}}
 
-	result, err := r.Reconcile(ctx, ctrl.Request{
-		NamespacedName: types.NamespacedName{Name: "nonexistent", Namespace: "default"},
-	})
-	if err != nil {
-		t.Fatalf("unexpected error for not-found: %v", err)
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if result.RequeueAfter != 0 {
-		t.Error("should not requeue for not-found")
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 2 {
+		t.Fatalf("expected 2 listeners before the policy change, got %d", len(gw.Spec.Listeners))
+	}
+
+	// Tighten the policy: the custom domain is no longer allowed
+	_ = r.Get(ctx, types.NamespacedName{Name: "tenant-acme"}, ns)
+	ns.Annotations = nil
+	if err := r.Update(ctx, ns); err != nil {
+		t.Fatalf("failed to update namespace: %v", err)
+	}
+
+	fakeRecorder := record.NewFakeRecorder(10)
+	r.Recorder = fakeRecorder
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 || string(gw.Spec.Listeners[0].Name) != "https-app-tenant-acme-example-com" {
+		t.Errorf("expected only the still-allowed listener to remain, got %v", gw.Spec.Listeners)
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if route.Annotations[managedHostnamesAnnotation] != "https-app-tenant-acme-example-com" {
+		t.Errorf("expected pruned listener to be dropped from the annotation, got %q", route.Annotations[managedHostnamesAnnotation])
+	}
+
+	events := drainEvents(fakeRecorder)
+	want := "Warning HostnameNoLongerAllowed removed listener https-shop-acme-com: hostname shop.acme.com is no longer allowed for namespace tenant-acme"
+	if !slices.Contains(events, want) {
+		t.Errorf("expected event %q, got %v", want, events)
+	}
+}
+
+func TestReconcile_PolicyLookupErrorKeepsListeners(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "tenant-acme",
+			Annotations: map[string]string{"gateway-auto-listener/allowed-hostnames": "acme.com"},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "shop",
+			Namespace:   "tenant-acme",
+			Finalizers:  []string{finalizerName},
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{Hostnames: []gatewayv1.Hostname{"shop.acme.com"}},
+	}
+	r := newReconciler(ns, emptyGateway(), httpRoute)
+	r.EnforceOnPolicyChange = true
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "shop", Namespace: "tenant-acme"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Claim lookups fail from now on; the route changes so the reconcile
+	// is not skipped
+	r.Client = interceptor.NewClient(r.Client.(client.WithWatch), interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if _, ok := list.(*corev1.NamespaceList); ok {
+				return apierrors.NewServiceUnavailable("etcd unavailable")
+			}
+			return c.List(ctx, list, opts...)
+		},
+	})
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	route.Labels = map[string]string{"touched": "true"}
+	if err := r.Update(ctx, &route); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	drainEvents(r.Recorder.(*record.FakeRecorder))
+
+	if _, err := r.Reconcile(ctx, req); err == nil {
+		t.Fatal("expected the lookup error to be returned")
+	}
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if names := listenerNames(&gw); len(names) != 1 || names[0] != "https-shop-acme-com" {
+		t.Errorf("expected the listener to survive a failed policy lookup, got %v", names)
+	}
+	events := drainEvents(r.Recorder.(*record.FakeRecorder))
+	if got := eventsWithReason(events, "HostnameNoLongerAllowed"); len(got) != 0 {
+		t.Errorf("expected no HostnameNoLongerAllowed events, got %v", got)
+	}
+	if got := eventsWithReason(events, "HostnameValidationFailed"); len(got) != 0 {
+		t.Errorf("expected no HostnameValidationFailed events, got %v", got)
+	}
+}
+
+func TestReconcile_PolicyChangeNotEnforcedByDefault(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-acme"}}
+	shopHostname := gatewayv1.Hostname("shop.acme.com")
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners: []gatewayv1.Listener{
+				{Name: "https-shop-acme-com", Hostname: &shopHostname, Port: 443, Protocol: gatewayv1.HTTPSProtocolType},
+			},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "shop",
+			Namespace:  "tenant-acme",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+				managedHostnamesAnnotation:       "https-shop-acme-com",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"shop.acme.com"},
+		},
+	}
+
+	r := newReconciler(ns, gateway, httpRoute)
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "shop", Namespace: "tenant-acme"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 {
+		t.Errorf("expected disallowed listener to be kept without enforcement, got %d listeners", len(gw.Spec.Listeners))
+	}
+}
+
+func TestReconcile_RequeueAfterSuccess(t *testing.T) {
+	for _, requeueAfter := range []time.Duration{0, 5 * time.Minute} {
+		t.Run(requeueAfter.String(), func(t *testing.T) {
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{},
+				},
+			}
+			httpRoute := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "app",
+					Namespace:  "default",
+					Finalizers: []string{finalizerName},
+					Annotations: map[string]string{
+						"cert-manager.io/cluster-issuer": "letsencrypt",
+					},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"app.example.com"},
+				},
+			}
+
+			r := newReconciler(gateway, httpRoute)
+			r.RequeueAfterSuccess = requeueAfter
+
+			result, err := r.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.RequeueAfter != requeueAfter {
+				t.Errorf("expected RequeueAfter %s, got %s", requeueAfter, result.RequeueAfter)
+			}
+		})
+	}
+}
+
+func TestReconcile_NotFound(t *testing.T) {
+	r := newReconciler()
+	ctx := context.Background()
+
+	result, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "nonexistent", Namespace: "default"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error for not-found: %v", err)
+	}
+	if result.RequeueAfter != 0 {
+		t.Error("should not requeue for not-found")
+	}
+}
+
+func TestReconcile_DryRunRouteRecordsDiff(t *testing.T) {
+	ns := gatewayv1.Namespace("nginx-gateway")
+	oldHostname := gatewayv1.Hostname("old.example.com")
+	tlsMode := gatewayv1.TLSModeTerminate
+	allowAll := gatewayv1.NamespacesFromAll
+
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners: []gatewayv1.Listener{
+				{
+					Name:     "https-old-example-com",
+					Hostname: &oldHostname,
+					Port:     443,
+					Protocol: gatewayv1.HTTPSProtocolType,
+					AllowedRoutes: &gatewayv1.AllowedRoutes{
+						Namespaces: &gatewayv1.RouteNamespaces{From: &allowAll},
+					},
+					TLS: &gatewayv1.ListenerTLSConfig{
+						Mode: &tlsMode,
+						CertificateRefs: []gatewayv1.SecretObjectReference{
+							{Name: "old-example-com-tls", Namespace: &ns},
+						},
+					},
+				},
+			},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-route",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+				managedHostnamesAnnotation:       "https-old-example-com",
+				dryRunAnnotation:                 "true",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"new.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	fakeRecorder := record.NewFakeRecorder(10)
+	r.Recorder = fakeRecorder
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "test-route", Namespace: "default"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 || string(gw.Spec.Listeners[0].Name) != "https-old-example-com" {
+		t.Fatalf("expected gateway to be unchanged in dry-run, got %v", gw.Spec.Listeners)
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, types.NamespacedName{Name: "test-route", Namespace: "default"}, &route)
+	if route.Annotations[managedHostnamesAnnotation] != "https-old-example-com" {
+		t.Errorf("expected managed-hostnames annotation to be unchanged, got %q", route.Annotations[managedHostnamesAnnotation])
+	}
+
+	events := drainEvents(fakeRecorder)
+	wantEvents := []string{
+		"Normal DryRunRemoveListener would remove listener https-old-example-com",
+		"Normal DryRunAddListener would add listener https-new-example-com for hostname new.example.com",
+	}
+	for _, want := range wantEvents {
+		if !slices.Contains(events, want) {
+			t.Errorf("expected event %q, got %v", want, events)
+		}
+	}
+}
+
+func TestReconcile_DryRunRouteDeletionRemovesListener(t *testing.T) {
+	ns := gatewayv1.Namespace("nginx-gateway")
+	hostname := gatewayv1.Hostname("test.example.com")
+	tlsMode := gatewayv1.TLSModeTerminate
+
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners: []gatewayv1.Listener{
+				{
+					Name:     "https-test-example-com",
+					Hostname: &hostname,
+					Port:     443,
+					Protocol: gatewayv1.HTTPSProtocolType,
+					TLS: &gatewayv1.ListenerTLSConfig{
+						Mode: &tlsMode,
+						CertificateRefs: []gatewayv1.SecretObjectReference{
+							{Name: "test-example-com-tls", Namespace: &ns},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	now := metav1.NewTime(time.Now())
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-route",
+			Namespace:         "default",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+				dryRunAnnotation:                 "true",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"test.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	fakeRecorder := record.NewFakeRecorder(10)
+	r.Recorder = fakeRecorder
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "test-route", Namespace: "default"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected the listener to be removed along with the finalizer, got %v", listenerNames(&gw))
+	}
+
+	for _, event := range drainEvents(fakeRecorder) {
+		if strings.Contains(event, "DryRunRemoveListener") {
+			t.Errorf("expected no dry-run event on deletion, got %q", event)
+		}
+	}
+}
+
+func TestReconcile_DryRunFlag(t *testing.T) {
+	ns := gatewayv1.Namespace("nginx-gateway")
+	oldHostname := gatewayv1.Hostname("old.example.com")
+	tlsMode := gatewayv1.TLSModeTerminate
+	existingListener := gatewayv1.Listener{
+		Name:     "https-old-example-com",
+		Hostname: &oldHostname,
+		Port:     443,
+		Protocol: gatewayv1.HTTPSProtocolType,
+		TLS: &gatewayv1.ListenerTLSConfig{
+			Mode:            &tlsMode,
+			CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "old-example-com-tls", Namespace: &ns}},
+		},
+	}
+	now := metav1.NewTime(time.Now())
+
+	tests := []struct {
+		name       string
+		route      *gatewayv1.HTTPRoute
+		wantEvents []string
+	}{
+		{
+			name: "create without finalizer",
+			route: &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-route",
+					Namespace:   "default",
+					Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{Hostnames: []gatewayv1.Hostname{"new.example.com"}},
+			},
+			wantEvents: []string{
+				"Normal DryRunAddListener would add listener https-new-example-com for hostname new.example.com",
+			},
+		},
+		{
+			name: "change",
+			route: &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-route",
+					Namespace:  "default",
+					Finalizers: []string{finalizerName},
+					Annotations: map[string]string{
+						clusterIssuerAnnotation:    "letsencrypt",
+						managedHostnamesAnnotation: "https-old-example-com",
+					},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{Hostnames: []gatewayv1.Hostname{"new.example.com"}},
+			},
+			wantEvents: []string{
+				"Normal DryRunRemoveListener would remove listener https-old-example-com",
+				"Normal DryRunAddListener would add listener https-new-example-com for hostname new.example.com",
+			},
+		},
+		{
+			name: "delete",
+			route: &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-route",
+					Namespace:         "default",
+					DeletionTimestamp: &now,
+					Finalizers:        []string{finalizerName},
+					Annotations: map[string]string{
+						clusterIssuerAnnotation:    "letsencrypt",
+						managedHostnamesAnnotation: "https-old-example-com",
+					},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{Hostnames: []gatewayv1.Hostname{"old.example.com"}},
+			},
+			wantEvents: []string{
+				"Normal DryRunRemoveListener would remove listener https-old-example-com",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{existingListener},
+				},
+			}
+
+			var writes int
+			recorder := record.NewFakeRecorder(100)
+			r := newReconciler()
+			r.Client = writeCountingClient(&writes, gateway, tt.route)
+			r.Recorder = recorder
+			r.DryRun = true
+			ctx := context.Background()
+
+			_, err := r.Reconcile(ctx, ctrl.Request{
+				NamespacedName: types.NamespacedName{Name: "test-route", Namespace: "default"},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if writes != 0 {
+				t.Errorf("expected no writes in dry-run mode, got %d", writes)
+			}
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			if names := listenerNames(&gw); len(names) != 1 || names[0] != "https-old-example-com" {
+				t.Errorf("expected the Gateway listeners to be untouched, got %v", names)
+			}
+			events := drainEvents(recorder)
+			for _, want := range tt.wantEvents {
+				if !slices.Contains(events, want) {
+					t.Errorf("expected event %q, got %v", want, events)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcile_NewListenerPortEvent(t *testing.T) {
+	httpHostname := gatewayv1.Hostname("plain.example.com")
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners: []gatewayv1.Listener{
+				{Name: "http", Hostname: &httpHostname, Port: 80, Protocol: gatewayv1.HTTPProtocolType},
+			},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-route",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"one.example.com", "two.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	r.ReportNewListenerPorts = true
+	fakeRecorder := record.NewFakeRecorder(10)
+	r.Recorder = fakeRecorder
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "test-route", Namespace: "default"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the first listener on the new port is reported
+	events := eventsWithReason(drainEvents(fakeRecorder), "NewListenerPort")
+	if len(events) != 1 || !strings.HasPrefix(events[0], "Normal NewListenerPort listener https-one-example-com uses port 443") {
+		t.Errorf("expected a single NewListenerPort event, got %v", events)
+	}
+
+	// Port 443 is now in use, so further reconciles stay quiet
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, types.NamespacedName{Name: "test-route", Namespace: "default"}, &route)
+	route.Spec.Hostnames = append(route.Spec.Hostnames, "three.example.com")
+	if err := r.Update(ctx, &route); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	_, err = r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "test-route", Namespace: "default"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if events := eventsWithReason(drainEvents(fakeRecorder), "NewListenerPort"); len(events) != 0 {
+		t.Errorf("expected no events for an already used port, got %v", events)
+	}
+}
+
+func TestListenerName_Ports(t *testing.T) {
+	r := newReconciler()
+	r.ListenerPorts = []gatewayv1.PortNumber{443, 8443}
+
+	if name := r.listenerName("app.example.com", 443); name != "https-app-example-com" {
+		t.Errorf("primary port listener name = %q, want unqualified name", name)
+	}
+	if name := r.listenerName("app.example.com", 8443); name != "https-app-example-com-8443" {
+		t.Errorf("secondary port listener name = %q, want port-qualified name", name)
+	}
+}
+
+func TestReconcile_MultiplePorts(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-route",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"test.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	r.ListenerPorts = []gatewayv1.PortNumber{443, 8443}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-route", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	ports := make(map[string]gatewayv1.PortNumber)
+	for _, l := range gw.Spec.Listeners {
+		ports[string(l.Name)] = l.Port
+	}
+	if len(ports) != 2 || ports["https-test-example-com"] != 443 || ports["https-test-example-com-8443"] != 8443 {
+		t.Fatalf("expected listeners on 443 and 8443, got %v", ports)
+	}
+	for _, l := range gw.Spec.Listeners {
+		if string(l.TLS.CertificateRefs[0].Name) != "test-example-com-tls" {
+			t.Errorf("listener %s should share the hostname secret, got %q", l.Name, l.TLS.CertificateRefs[0].Name)
+		}
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if route.Annotations[managedHostnamesAnnotation] != "https-test-example-com,https-test-example-com-8443" {
+		t.Errorf("expected both listeners tracked, got %q", route.Annotations[managedHostnamesAnnotation])
+	}
+
+	// Deleting the route removes the listeners on every port
+	if err := r.Delete(ctx, &route); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected all per-port listeners removed, got %v", gw.Spec.Listeners)
+	}
+}
+
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+// eventsWithReason filters recorded events down to the given reason.
+func eventsWithReason(events []string, reason string) []string {
+	var filtered []string
+	for _, e := range events {
+		if _, rest, ok := strings.Cut(e, " "); ok && strings.HasPrefix(rest, reason+" ") {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+func TestReconcile_ListenerPortAnnotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotation  string
+		defaultPort gatewayv1.PortNumber
+		wantName    string
+		wantPort    gatewayv1.PortNumber
+		wantEvent   bool
+	}{
+		{name: "absent", wantName: "https-test-example-com", wantPort: 443},
+		{name: "global default", defaultPort: 8443, wantName: "https-test-example-com", wantPort: 8443},
+		{name: "override", annotation: "9443", wantName: "https-test-example-com-9443", wantPort: 9443},
+		{name: "override matching default", annotation: "8443", defaultPort: 8443, wantName: "https-test-example-com", wantPort: 8443},
+		{name: "not a number", annotation: "https", wantName: "https-test-example-com", wantPort: 443, wantEvent: true},
+		{name: "out of range", annotation: "70000", wantName: "https-test-example-com", wantPort: 443, wantEvent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{},
+				},
+			}
+			annotations := map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"}
+			if tt.annotation != "" {
+				annotations[listenerPortAnnotation] = tt.annotation
+			}
+			httpRoute := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-route",
+					Namespace:   "default",
+					Finalizers:  []string{finalizerName},
+					Annotations: annotations,
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"test.example.com"},
+				},
+			}
+
+			r := newReconciler(gateway, httpRoute)
+			r.DefaultListenerPort = tt.defaultPort
+			fakeRecorder := record.NewFakeRecorder(10)
+			r.Recorder = fakeRecorder
+			ctx := context.Background()
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-route", Namespace: "default"}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			if len(gw.Spec.Listeners) != 1 {
+				t.Fatalf("expected 1 listener, got %d", len(gw.Spec.Listeners))
+			}
+			if l := gw.Spec.Listeners[0]; string(l.Name) != tt.wantName || l.Port != tt.wantPort {
+				t.Errorf("expected listener %s on port %d, got %s on port %d", tt.wantName, tt.wantPort, l.Name, l.Port)
+			}
+
+			events := eventsWithReason(drainEvents(fakeRecorder), "InvalidListenerPort")
+			if tt.wantEvent != (len(events) == 1) {
+				t.Errorf("expected InvalidListenerPort event: %v, got %v", tt.wantEvent, events)
+			}
+		})
+	}
+}
+
+func TestManagedGatewayPredicate(t *testing.T) {
+	r := newReconciler()
+	p := r.managedGatewayPredicate()
+
+	managed := &gatewayv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"}}
+	others := []*gatewayv1.Gateway{
+		{ObjectMeta: metav1.ObjectMeta{Name: "internal", Namespace: "nginx-gateway"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "other"}},
+	}
+
+	if !p.Create(event.CreateEvent{Object: managed}) ||
+		!p.Update(event.UpdateEvent{ObjectOld: managed, ObjectNew: managed}) ||
+		!p.Delete(event.DeleteEvent{Object: managed}) {
+		t.Error("expected events for the managed gateway to pass")
+	}
+	for _, gw := range others {
+		if p.Create(event.CreateEvent{Object: gw}) ||
+			p.Update(event.UpdateEvent{ObjectOld: gw, ObjectNew: gw}) ||
+			p.Delete(event.DeleteEvent{Object: gw}) ||
+			p.Generic(event.GenericEvent{Object: gw}) {
+			t.Errorf("expected events for gateway %s/%s to be filtered", gw.Namespace, gw.Name)
+		}
+	}
+}
+
+func TestReconcile_OnEmptyHostname(t *testing.T) {
+	tests := []struct {
+		mode      string
+		wantErr   bool
+		wantEvent bool
+	}{
+		{mode: "", wantErr: false, wantEvent: false},
+		{mode: EmptyHostnameSkip, wantErr: false, wantEvent: false},
+		{mode: EmptyHostnameError, wantErr: true, wantEvent: false},
+		{mode: EmptyHostnameEvent, wantErr: false, wantEvent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{},
+				},
+			}
+			httpRoute := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "app",
+					Namespace:  "default",
+					Finalizers: []string{finalizerName},
+					Annotations: map[string]string{
+						"cert-manager.io/cluster-issuer": "letsencrypt",
+					},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"", "app.example.com"},
+				},
+			}
+
+			r := newReconciler(gateway, httpRoute)
+			r.OnEmptyHostname = tt.mode
+			fakeRecorder := record.NewFakeRecorder(10)
+			r.Recorder = fakeRecorder
+			ctx := context.Background()
+
+			_, err := r.Reconcile(ctx, ctrl.Request{
+				NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			wantListeners := 1
+			if tt.wantErr {
+				wantListeners = 0
+			}
+			if len(gw.Spec.Listeners) != wantListeners {
+				t.Errorf("expected %d listeners, got %d", wantListeners, len(gw.Spec.Listeners))
+			}
+
+			events := drainEvents(fakeRecorder)
+			gotEvent := slices.Contains(events, "Warning EmptyHostname route lists an empty hostname, ignoring it")
+			if gotEvent != tt.wantEvent {
+				t.Errorf("expected EmptyHostname event %v, got events %v", tt.wantEvent, events)
+			}
+		})
+	}
+}
+
+func TestTargetsManagedGateway(t *testing.T) {
+	r := newReconciler()
+	group := gatewayv1.Group(gatewayv1.GroupName)
+	coreGroup := gatewayv1.Group("")
+	gatewayKind := gatewayv1.Kind("Gateway")
+	serviceKind := gatewayv1.Kind("Service")
+	gatewayNamespace := gatewayv1.Namespace("nginx-gateway")
+
+	tests := []struct {
+		name       string
+		parentRefs []gatewayv1.ParentReference
+		want       bool
+	}{
+		{"no parentRefs", nil, true},
+		{"managed gateway", []gatewayv1.ParentReference{
+			{Group: &group, Kind: &gatewayKind, Namespace: &gatewayNamespace, Name: "default"},
+		}, true},
+		{"defaulted group and kind", []gatewayv1.ParentReference{
+			{Namespace: &gatewayNamespace, Name: "default"},
+		}, true},
+		{"other gateway", []gatewayv1.ParentReference{
+			{Namespace: &gatewayNamespace, Name: "internal"},
+		}, false},
+		{"gateway in route namespace", []gatewayv1.ParentReference{
+			{Name: "default"},
+		}, false},
+		{"mesh service", []gatewayv1.ParentReference{
+			{Group: &coreGroup, Kind: &serviceKind, Namespace: &gatewayNamespace, Name: "default"},
+		}, false},
+		{"mesh service and managed gateway", []gatewayv1.ParentReference{
+			{Group: &coreGroup, Kind: &serviceKind, Name: "app"},
+			{Namespace: &gatewayNamespace, Name: "default"},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: gatewayv1.HTTPRouteSpec{
+					CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: tt.parentRefs},
+				},
+			}
+			if got := r.targetsManagedGateway(route); got != tt.want {
+				t.Errorf("targetsManagedGateway() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcile_MeshParentRefIgnored(t *testing.T) {
+	coreGroup := gatewayv1.Group("")
+	serviceKind := gatewayv1.Kind("Service")
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{
+					{Group: &coreGroup, Kind: &serviceKind, Name: "app"},
+				},
+			},
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	for range 2 {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if controllerutil.ContainsFinalizer(&route, finalizerName) {
+		t.Error("expected no finalizer on a mesh route")
+	}
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected no listeners for a mesh route, got %d", len(gw.Spec.Listeners))
+	}
+
+	if reqs := r.gatewayToHTTPRoutes(ctx, &gw); len(reqs) != 0 {
+		t.Errorf("expected mesh route not to be mapped from gateway events, got %v", reqs)
+	}
+}
+
+func TestReconcile_RouteMovedOffManagedGateway(t *testing.T) {
+	httpRoute := certificateRoute(map[string]string{clusterIssuerAnnotation: "letsencrypt"})
+	gatewayNamespace := gatewayv1.Namespace("nginx-gateway")
+	httpRoute.Spec.ParentRefs = []gatewayv1.ParentReference{{Name: "default", Namespace: &gatewayNamespace}}
+	r := newReconciler(emptyGateway(), httpRoute)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+	gwKey := types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, gwKey, &gw)
+	if len(gw.Spec.Listeners) != 1 {
+		t.Fatalf("expected a listener, got %v", listenerNames(&gw))
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	route.Spec.ParentRefs[0].Name = "other"
+	if err := r.Update(ctx, &route); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if reqs := r.gatewayToHTTPRoutes(ctx, &gw); len(reqs) != 1 {
+		t.Errorf("expected the detached route to still be mapped from gateway events, got %v", reqs)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_ = r.Get(ctx, gwKey, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected the listener removed, got %v", listenerNames(&gw))
+	}
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if controllerutil.ContainsFinalizer(&route, finalizerName) {
+		t.Error("expected the finalizer removed")
+	}
+	if _, ok := route.Annotations[managedHostnamesAnnotation]; ok {
+		t.Errorf("expected the managed-hostnames annotation removed, got %q", route.Annotations[managedHostnamesAnnotation])
+	}
+	if events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "RouteDetached"); len(events) != 1 {
+		t.Errorf("expected one RouteDetached event, got %v", events)
+	}
+}
+
+// TestReconcile_ConcurrentSameRoute hammers a single route with concurrent
+// reconciles. The controller's work queue never runs the same key in
+// parallel, but even without that guarantee concurrent writers must only
+// lose with a conflict, leaving a consistent state once reconciled again.
+func TestReconcile_ConcurrentSameRoute(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com", "api.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	r.Recorder = record.NewFakeRecorder(1000)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := r.Reconcile(ctx, req); err != nil && !apierrors.IsConflict(err) {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("unexpected non-conflict error: %v", err)
+	}
+
+	// Settle like the work queue would after conflicts
+	for range 2 {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	var names []string
+	for _, l := range gw.Spec.Listeners {
+		names = append(names, string(l.Name))
+	}
+	slices.Sort(names)
+	want := []string{"https-api-example-com", "https-app-example-com"}
+	if !slices.Equal(names, want) {
+		t.Errorf("expected listeners %v, got %v", want, names)
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if route.Annotations[managedHostnamesAnnotation] != strings.Join(want, ",") {
+		t.Errorf("expected managed-hostnames %q, got %q", strings.Join(want, ","), route.Annotations[managedHostnamesAnnotation])
+	}
+	if n := len(route.Finalizers); n != 1 {
+		t.Errorf("expected exactly one finalizer, got %d", n)
+	}
+}
+
+func TestReconcile_LogsRouteUID(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "default",
+			UID:        "2f1c9a6e-route-uid",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+
+	var lines []string
+	sink := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{Verbosity: 1})
+	ctx := ctrl.LoggerInto(context.Background(), sink)
+
+	r := newReconciler(gateway, httpRoute)
+	_, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var found bool
+	for _, line := range lines {
+		if strings.Contains(line, `"msg"="adding listener"`) {
+			found = true
+			if !strings.Contains(line, `"uid"="2f1c9a6e-route-uid"`) {
+				t.Errorf("expected route UID in log line, got %s", line)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("expected an adding listener log line, got %v", lines)
+	}
+}
+
+func TestReconcile_HostnameIncludePattern(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.staging.example.com", "app.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	r.HostnameIncludePattern = regexp.MustCompile(`\.staging\.example\.com$`)
+	fakeRecorder := record.NewFakeRecorder(10)
+	r.Recorder = fakeRecorder
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 || string(gw.Spec.Listeners[0].Name) != "https-app-staging-example-com" {
+		t.Errorf("expected only the included hostname to get a listener, got %v", gw.Spec.Listeners)
+	}
+
+	events := drainEvents(fakeRecorder)
+	want := `Normal HostnameExcludedByFilter hostname app.example.com does not match the include pattern \.staging\.example\.com$`
+	if !slices.Contains(events, want) {
+		t.Errorf("expected event %q, got %v", want, events)
+	}
+}
+
+func TestReconcile_NilGatewayListeners(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        nil,
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+
+	var patches []string
+	cb := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(gateway, httpRoute).WithStatusSubresource(httpRoute).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if _, ok := obj.(*gatewayv1.Gateway); ok {
+					data, err := patch.Data(obj)
+					if err != nil {
+						return err
+					}
+					patches = append(patches, string(data))
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		})
+	r := newReconciler()
+	r.Client = cb.Build()
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 || gw.Spec.Listeners[0].Name != "https-app-example-com" {
+		t.Fatalf("expected listener added to a gateway with nil listeners, got %v", gw.Spec.Listeners)
+	}
+	if gw.Labels[managedByLabel] != managedByValue {
+		t.Errorf("expected managed-by label, got %v", gw.Labels)
+	}
+
+	// Removing the only listener must patch an empty array, not null
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	route.Spec.Hostnames = nil
+	if err := r.Update(ctx, &route); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(patches) != 2 {
+		t.Fatalf("expected 2 gateway patches, got %v", patches)
+	}
+	for _, p := range patches {
+		if strings.Contains(p, `"listeners":null`) {
+			t.Errorf("expected no null listeners in patch, got %s", p)
+		}
+	}
+	if !strings.Contains(patches[1], `"listeners":[]`) {
+		t.Errorf("expected removal patch to carry an empty listeners array, got %s", patches[1])
+	}
+}
+
+func TestReconcile_ListenerNameConflict(t *testing.T) {
+	otherHostname := gatewayv1.Hostname("other.example.com")
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners: []gatewayv1.Listener{
+				{Name: "https-app-example-com", Hostname: &otherHostname, Port: 443, Protocol: gatewayv1.HTTPSProtocolType},
+			},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	fakeRecorder := record.NewFakeRecorder(10)
+	r.Recorder = fakeRecorder
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := drainEvents(fakeRecorder)
+	want := `Warning ListenerNameConflict listener https-app-example-com for hostname app.example.com already exists on Gateway nginx-gateway/default for hostname "other.example.com"`
+	if !slices.Contains(events, want) {
+		t.Errorf("expected event %q, got %v", want, events)
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if got := route.Annotations[managedHostnamesAnnotation]; got != "" {
+		t.Errorf("expected the conflicting listener not to be tracked, got %q", got)
+	}
+
+	// Deleting the route must leave the other hostname's listener alone
+	if err := r.Delete(ctx, &route); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 || *gw.Spec.Listeners[0].Hostname != otherHostname {
+		t.Errorf("expected the conflicting listener to be kept, got %v", gw.Spec.Listeners)
+	}
+}
+
+func TestReconcile_ListenerNameConflictThrottled(t *testing.T) {
+	otherHostname := gatewayv1.Hostname("other.example.com")
+	gateway := emptyGateway()
+	gateway.Spec.Listeners = []gatewayv1.Listener{
+		{Name: "https-app-example-com", Hostname: &otherHostname, Port: 443, Protocol: gatewayv1.HTTPSProtocolType},
+	}
+	r := newReconciler(gateway, certificateRoute(map[string]string{clusterIssuerAnnotation: "letsencrypt"}))
+	fakeRecorder := record.NewFakeRecorder(10)
+	r.Recorder = fakeRecorder
+	r.EventThrottle = time.Hour
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	for range 3 {
+		// Forget the fingerprint so every pass runs the full reconcile
+		r.fingerprints.forget(req.NamespacedName)
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if events := eventsWithReason(drainEvents(fakeRecorder), "ListenerNameConflict"); len(events) != 1 {
+		t.Errorf("expected one ListenerNameConflict event within the throttle interval, got %v", events)
+	}
+}
+
+func TestNormalizeHostname(t *testing.T) {
+	tests := []struct {
+		hostname string
+		expected string
+	}{
+		{"example.com", "example.com"},
+		{"café.example.com", "xn--caf-dma.example.com"},
+		{"CAFÉ.example.com", "xn--caf-dma.example.com"},
+		{"*.café.example.com", "*.xn--caf-dma.example.com"},
+		{"example.com.", "example.com"},
+		{"café.example.com.", "xn--caf-dma.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hostname, func(t *testing.T) {
+			result, err := normalizeHostname(tt.hostname)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("normalizeHostname(%q) = %q, want %q", tt.hostname, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReconcile_InternationalizedHostname(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "cafe",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"café.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "cafe", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 {
+		t.Fatalf("expected 1 listener, got %d", len(gw.Spec.Listeners))
+	}
+	listener := gw.Spec.Listeners[0]
+	if string(listener.Name) != "https-xn--caf-dma-example-com" {
+		t.Errorf("expected punycode listener name, got %q", listener.Name)
+	}
+	if listener.Hostname == nil || string(*listener.Hostname) != "xn--caf-dma.example.com" {
+		t.Errorf("expected punycode hostname, got %v", listener.Hostname)
+	}
+	if listener.TLS == nil || string(listener.TLS.CertificateRefs[0].Name) != "xn--caf-dma-example-com-tls" {
+		t.Errorf("expected punycode secret name, got %+v", listener.TLS)
+	}
+
+	// Deleting the route removes the punycode listener again
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if err := r.Delete(ctx, &route); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected listener removed, got %v", listenerNames(&gw))
+	}
+}
+
+func TestReconcile_ProtocolAnnotation(t *testing.T) {
+	tests := []struct {
+		protocol  string
+		want      gatewayv1.ProtocolType
+		wantEvent bool
+	}{
+		{"", gatewayv1.HTTPSProtocolType, false},
+		{"HTTPS", gatewayv1.HTTPSProtocolType, false},
+		{"TLS", gatewayv1.TLSProtocolType, false},
+		{"HTTP", gatewayv1.HTTPSProtocolType, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.protocol, func(t *testing.T) {
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{},
+				},
+			}
+			annotations := map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			}
+			if tt.protocol != "" {
+				annotations[protocolAnnotation] = tt.protocol
+			}
+			httpRoute := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "app",
+					Namespace:   "default",
+					Finalizers:  []string{finalizerName},
+					Annotations: annotations,
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"app.example.com"},
+				},
+			}
+
+			r := newReconciler(gateway, httpRoute)
+			ctx := context.Background()
+			if _, err := r.Reconcile(ctx, ctrl.Request{
+				NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"},
+			}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			if len(gw.Spec.Listeners) != 1 {
+				t.Fatalf("expected 1 listener, got %d", len(gw.Spec.Listeners))
+			}
+			listener := gw.Spec.Listeners[0]
+			if listener.Protocol != tt.want {
+				t.Errorf("expected protocol %s, got %s", tt.want, listener.Protocol)
+			}
+			if listener.TLS == nil || *listener.TLS.Mode != gatewayv1.TLSModeTerminate || len(listener.TLS.CertificateRefs) != 1 {
+				t.Errorf("expected terminating TLS with a certificate ref, got %+v", listener.TLS)
+			}
+			events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "InvalidProtocol")
+			if got := len(events) > 0; got != tt.wantEvent {
+				t.Errorf("expected InvalidProtocol event %v, got %v", tt.wantEvent, events)
+			}
+		})
+	}
+}
+
+func TestDisambiguatedSecretName(t *testing.T) {
+	r := newReconciler()
+	pairs := [][2]string{
+		{"a.b.example.com", "a-b.example.com"},
+		{"*.example.com", "wildcard.example.com"},
+	}
+	for _, pair := range pairs {
+		if r.hostnameToSecretName(pair[0]) != r.hostnameToSecretName(pair[1]) {
+			t.Fatalf("expected %s and %s to collide", pair[0], pair[1])
+		}
+		first, second := r.disambiguatedSecretName(pair[0]), r.disambiguatedSecretName(pair[1])
+		if first == second {
+			t.Errorf("expected distinct secret names for %s and %s, got %s", pair[0], pair[1], first)
+		}
+		for _, name := range []string{first, second} {
+			if !strings.HasSuffix(name, "-tls") {
+				t.Errorf("expected %s to keep the -tls suffix", name)
+			}
+		}
+		if r.disambiguatedSecretName(pair[0]) != first {
+			t.Errorf("expected stable secret name for %s", pair[0])
+		}
+	}
+}
+
+func TestReconcile_SecretNameCollision(t *testing.T) {
+	for name, disambiguate := range map[string]bool{"shared": false, "disambiguated": true} {
+		t.Run(name, func(t *testing.T) {
+			r := newReconciler()
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners: []gatewayv1.Listener{
+						// A manually added listener, named differently
+						r.buildListener("legacy", "a-b.example.com", 443, "nginx-gateway", "a-b-example-com-tls", nil),
+					},
+				},
+			}
+			httpRoute := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "app",
+					Namespace:  "default",
+					Finalizers: []string{finalizerName},
+					Annotations: map[string]string{
+						"cert-manager.io/cluster-issuer": "letsencrypt",
+					},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"a.b.example.com"},
+				},
+			}
+			r = newReconciler(gateway, httpRoute)
+			r.DisambiguateSecretNames = disambiguate
+			ctx := context.Background()
+
+			if _, err := r.Reconcile(ctx, ctrl.Request{
+				NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"},
+			}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "SecretNameCollision")
+			if len(events) != 1 {
+				t.Errorf("expected one SecretNameCollision event, got %v", events)
+			}
+
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			var secret string
+			for _, l := range gw.Spec.Listeners {
+				if l.Name == "legacy" {
+					continue
+				}
+				secret = string(l.TLS.CertificateRefs[0].Name)
+			}
+			want := "a-b-example-com-tls"
+			if disambiguate {
+				want = r.disambiguatedSecretName("a.b.example.com")
+			}
+			if secret != want {
+				t.Errorf("expected secret %s, got %s", want, secret)
+			}
+		})
+	}
+}
+
+func TestReconcile_ListenersAnnotation(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"b.example.com", "a.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if got := route.Annotations[listenersAnnotation]; got != "https-a-example-com,https-b-example-com" {
+		t.Errorf("unexpected listeners annotation after create: %q", got)
+	}
+
+	route.Spec.Hostnames = []gatewayv1.Hostname{"c.example.com"}
+	if err := r.Update(ctx, &route); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if got := route.Annotations[listenersAnnotation]; got != "https-c-example-com" {
+		t.Errorf("unexpected listeners annotation after change: %q", got)
+	}
+
+	// A hand-edited annotation is restored
+	route.Annotations[listenersAnnotation] = "edited"
+	if err := r.Update(ctx, &route); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if got := route.Annotations[listenersAnnotation]; got != "https-c-example-com" {
+		t.Errorf("expected edited annotation restored, got %q", got)
+	}
+}
+
+func TestReconcile_TenantAndPlatformAllowedRoutes(t *testing.T) {
+	tests := []struct {
+		namespace string
+		hostname  string
+		want      gatewayv1.FromNamespaces
+	}{
+		{"tenant-a", "app.tenant-a.example.com", gatewayv1.NamespacesFromSame},
+		{"platform", "app.platform.org", gatewayv1.NamespacesFromAll},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{},
+				},
+			}
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tt.namespace}}
+			httpRoute := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "app",
+					Namespace:  tt.namespace,
+					Finalizers: []string{finalizerName},
+					Annotations: map[string]string{
+						"cert-manager.io/cluster-issuer": "letsencrypt",
+					},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(tt.hostname)},
+				},
+			}
+
+			r := newReconciler(gateway, ns, httpRoute)
+			r.TenantAllowedRoutes = gatewayv1.NamespacesFromSame
+			r.PlatformAllowedRoutes = gatewayv1.NamespacesFromAll
+			ctx := context.Background()
+			if _, err := r.Reconcile(ctx, ctrl.Request{
+				NamespacedName: types.NamespacedName{Name: "app", Namespace: tt.namespace},
+			}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			if len(gw.Spec.Listeners) != 1 {
+				t.Fatalf("expected 1 listener, got %v", listenerNames(&gw))
+			}
+			from := gw.Spec.Listeners[0].AllowedRoutes.Namespaces.From
+			if from == nil || *from != tt.want {
+				t.Errorf("expected AllowedRoutes from %s, got %v", tt.want, from)
+			}
+		})
+	}
+}
+
+func TestOrderManagedListeners(t *testing.T) {
+	r := newReconciler()
+	listener := func(name, hostname string) gatewayv1.Listener {
+		return r.buildListener(name, hostname, 443, "nginx-gateway", name+"-tls", nil)
+	}
+	listeners := []gatewayv1.Listener{
+		listener("wild-a", "*.a.example.com"),
+		listener("manual-wild", "*.manual.example.com"),
+		listener("exact-a", "a.example.com"),
+		listener("manual", "manual.example.com"),
+		listener("wild-b", "*.b.example.com"),
+		listener("exact-b", "b.example.com"),
+	}
+	manual := map[string]bool{"manual-wild": true, "manual": true}
+
+	orderManagedListeners(listeners, func(name string) bool { return !manual[name] })
+
+	var names []string
+	for _, l := range listeners {
+		names = append(names, string(l.Name))
+	}
+	want := []string{"exact-a", "manual-wild", "exact-b", "manual", "wild-a", "wild-b"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("expected order %v, got %v", want, names)
+	}
+}
+
+func TestReconcile_ExactListenersBeforeWildcards(t *testing.T) {
+	r := newReconciler()
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners: []gatewayv1.Listener{
+				r.buildListener("manual", "*.manual.example.com", 443, "nginx-gateway", "manual-tls", nil),
+			},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"*.example.com", "app.example.com"},
+		},
+	}
+
+	r = newReconciler(gateway, httpRoute)
+	ctx := context.Background()
+	if _, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	var got []string
+	for _, l := range gw.Spec.Listeners {
+		got = append(got, string(l.Name))
+	}
+	want := []string{"manual", "https-app-example-com", "https-wildcard-example-com"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected listener order %v, got %v", want, got)
+	}
+}
+
+func TestReconcile_MaxListeners(t *testing.T) {
+	r := newReconciler()
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: "nginx"},
+	}
+	for i := range 62 {
+		hostname := fmt.Sprintf("manual-%d.example.com", i)
+		gateway.Spec.Listeners = append(gateway.Spec.Listeners,
+			r.buildListener(fmt.Sprintf("manual-%d", i), hostname, 443, "nginx-gateway", "manual-tls", nil))
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Finalizers:  []string{finalizerName},
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"a.example.com", "b.example.com", "c.example.com", "d.example.com"},
+		},
+	}
+
+	r = newReconciler(gateway, httpRoute)
+	r.MaxListeners = 64
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("expected no error at the listener limit, got %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 64 {
+		t.Fatalf("expected the Gateway to be filled up to 64 listeners, got %d", len(gw.Spec.Listeners))
+	}
+	names := listenerNames(&gw)
+	for _, want := range []string{"https-a-example-com", "https-b-example-com"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("expected listener %s to fit, got %v", want, names)
+		}
+	}
+
+	events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "ListenerLimitExceeded")
+	want := "Warning ListenerLimitExceeded Gateway nginx-gateway/default is at its limit of 64 listeners; no listeners added for hostnames c.example.com, d.example.com"
+	if len(events) != 1 || events[0] != want {
+		t.Errorf("expected event %q, got %v", want, events)
+	}
+
+	// Skipped listeners are not tracked, so they are retried later
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if tracked := route.Annotations[managedHostnamesAnnotation]; tracked != "https-a-example-com,https-b-example-com" {
+		t.Errorf("expected only the added listeners to be tracked, got %q", tracked)
 	}
 }
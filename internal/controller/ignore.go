@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// ignoreAnnotation opts a route out of listener management with "true". A
+// route that was already managed has its listeners removed and its finalizer
+// dropped.
+const ignoreAnnotation = "gateway-auto-listener/ignore"
+
+// isIgnored reports whether the route carries ignoreAnnotation. An
+// unparseable value does not ignore the route.
+func isIgnored(route client.Object) bool {
+	ignored, err := strconv.ParseBool(route.GetAnnotations()[ignoreAnnotation])
+	return err == nil && ignored
+}
+
+// releaseIgnoredRoute removes the listeners of a route that gained
+// ignoreAnnotation, then drops its bookkeeping annotations and finalizer so
+// the controller no longer holds on to it. Routes never managed are left
+// untouched.
+func (r *HTTPRouteReconciler) releaseIgnoredRoute(ctx context.Context, httpRoute *gatewayv1.HTTPRoute, summary *reconcileSummary) error {
+	if !controllerutil.ContainsFinalizer(httpRoute, r.finalizer()) {
+		return nil
+	}
+	released, err := r.releaseRoute(ctx, httpRoute, summary)
+	if err != nil || !released {
+		return err
+	}
+
+	log.FromContext(ctx).Info("route ignored, released its listeners")
+	r.Recorder.Event(httpRoute, corev1.EventTypeNormal, "RouteIgnored",
+		"route is annotated "+ignoreAnnotation+", released its listeners")
+	return nil
+}
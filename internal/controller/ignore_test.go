@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestReconcile_IgnoredRouteSkipped(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+			Annotations: map[string]string{
+				clusterIssuerAnnotation: "letsencrypt",
+				ignoreAnnotation:        "true",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+	for range 2 {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if controllerutil.ContainsFinalizer(&route, finalizerName) {
+		t.Error("expected no finalizer on an ignored route")
+	}
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected no listeners for an ignored route, got %v", listenerNames(&gw))
+	}
+	if events := drainEvents(r.Recorder.(*record.FakeRecorder)); len(events) != 0 {
+		t.Errorf("expected no events for a route that was never managed, got %v", events)
+	}
+}
+
+func TestReconcile_IgnoreReleasesManagedRoute(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Finalizers:  []string{finalizerName},
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 {
+		t.Fatalf("expected the route's listener, got %v", listenerNames(&gw))
+	}
+	drainEvents(r.Recorder.(*record.FakeRecorder))
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	route.Annotations[ignoreAnnotation] = "true"
+	if err := r.Update(ctx, &route); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected the listener to be removed, got %v", listenerNames(&gw))
+	}
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if controllerutil.ContainsFinalizer(&route, finalizerName) {
+		t.Error("expected the finalizer to be dropped")
+	}
+	for _, key := range []string{managedHostnamesAnnotation, listenersAnnotation} {
+		if _, ok := route.Annotations[key]; ok {
+			t.Errorf("expected annotation %s to be dropped", key)
+		}
+	}
+	if events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "RouteIgnored"); len(events) != 1 {
+		t.Errorf("expected one RouteIgnored event, got %v", events)
+	}
+
+	// Removing the annotation hands the route back to the controller
+	delete(route.Annotations, ignoreAnnotation)
+	if err := r.Update(ctx, &route); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	for range 2 {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 {
+		t.Errorf("expected the listener to be re-added, got %v", listenerNames(&gw))
+	}
+}
+
+func TestGatewayToHTTPRoutes_SkipsIgnoredRoutes(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec:       gatewayv1.GatewaySpec{GatewayClassName: "nginx"},
+	}
+	route := func(name string, annotations map[string]string) *gatewayv1.HTTPRoute {
+		annotations[clusterIssuerAnnotation] = "letsencrypt"
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Finalizers:  []string{finalizerName},
+				Annotations: annotations,
+			},
+		}
+	}
+
+	r := newReconciler(gateway,
+		route("managed", map[string]string{}),
+		route("ignored", map[string]string{ignoreAnnotation: "true"}),
+		route("not-ignored", map[string]string{ignoreAnnotation: "false"}),
+	)
+	requests := r.gatewayToHTTPRoutes(context.Background(), gateway)
+
+	got := make(map[string]bool)
+	for _, req := range requests {
+		got[req.Name] = true
+	}
+	if len(got) != 2 || !got["managed"] || !got["not-ignored"] {
+		t.Errorf("expected requests for managed and not-ignored only, got %v", requests)
+	}
+}
@@ -0,0 +1,257 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// hostnameClaimIndex is the ownership index over Namespaces, keyed by every
+// custom domain the namespace claims through the allowed-hostnames annotation.
+const hostnameClaimIndex = "gateway-auto-listener.hostnameClaims"
+
+// patternClaimKey is the hostnameClaimIndex key of namespaces with glob: or
+// regex: entries. Those cannot be looked up by hostname, so they are all
+// candidates for any hostname. It is no valid hostname, so no domain entry
+// is indexed under it.
+const patternClaimKey = "*pattern*"
+
+// hostnameClaimIndexer returns the extractor for hostnameClaimIndex, reading
+// claims from the given namespace annotation key.
+func hostnameClaimIndexer(annotation string) client.IndexerFunc {
+	return func(obj client.Object) []string {
+		entries, _ := parseAllowedHostnames(obj.GetAnnotations()[annotation])
+		keys := make([]string, 0, len(entries))
+		pattern := false
+		for _, entry := range entries {
+			if isPatternEntry(entry) {
+				pattern = true
+				continue
+			}
+			keys = append(keys, entry)
+		}
+		if pattern {
+			keys = append(keys, patternClaimKey)
+		}
+		return keys
+	}
+}
+
+// setupIndexes registers the ownership index fields with the manager's cache.
+func setupIndexes(ctx context.Context, indexer client.FieldIndexer, allowedHostnamesAnnotation string) error {
+	if allowedHostnamesAnnotation == "" {
+		return nil
+	}
+	return indexer.IndexField(ctx, &corev1.Namespace{}, hostnameClaimIndex, hostnameClaimIndexer(allowedHostnamesAnnotation))
+}
+
+// Prefixes of allowed-hostnames entries matched as patterns rather than as
+// a domain and its subdomains.
+const (
+	globEntryPrefix  = "glob:"
+	regexEntryPrefix = "regex:"
+)
+
+// isPatternEntry reports whether an allowed-hostnames entry is a glob: or
+// regex: pattern.
+func isPatternEntry(entry string) bool {
+	return strings.HasPrefix(entry, globEntryPrefix) || strings.HasPrefix(entry, regexEntryPrefix)
+}
+
+// parseAllowedHostnames leniently parses a comma-separated allowed-hostnames
+// annotation value. Surrounding whitespace is trimmed, and empty segments or
+// entries with embedded whitespace or invalid internationalized names are
+// dropped; malformed reports whether any such entry was found. Entries are
+// returned in ASCII form; glob: and regex: entries are kept as written.
+func parseAllowedHostnames(value string) (entries []string, malformed bool) {
+	if strings.TrimSpace(value) == "" {
+		return nil, false
+	}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.ContainsAny(entry, " \t\n") {
+			malformed = true
+			continue
+		}
+		if isPatternEntry(entry) {
+			entries = append(entries, entry)
+			continue
+		}
+		normalized, err := normalizeHostname(entry)
+		if err != nil {
+			malformed = true
+			continue
+		}
+		entries = append(entries, normalized)
+	}
+	return entries, malformed
+}
+
+// allowedHostnamesCache memoizes parsed allowed-hostnames annotations and
+// their compiled regex entries per namespace. An entry is replaced when the
+// namespace's resourceVersion changes and evicted when it is deleted, so the
+// cache holds no more than the live namespaces' current entries.
+type allowedHostnamesCache struct {
+	mu         sync.Mutex
+	namespaces map[string]parsedAllowedHostnames
+}
+
+// match reports whether an allowed-hostnames entry of the namespace admits
+// the hostname. A glob: entry matches label by label, so * stands for exactly
+// one label or part of one; a regex: entry is matched as written, so it needs
+// anchors to match the whole hostname. Other entries admit the domain and
+// its subdomains.
+func (c *allowedHostnamesCache) match(namespace, hostname, entry string) (bool, error) {
+	switch {
+	case strings.HasPrefix(entry, globEntryPrefix):
+		return matchHostnameGlob(hostname, strings.ToLower(strings.TrimPrefix(entry, globEntryPrefix)))
+	case strings.HasPrefix(entry, regexEntryPrefix):
+		re, err := c.regexp(namespace, strings.TrimPrefix(entry, regexEntryPrefix))
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(hostname), nil
+	default:
+		return hostname == entry || strings.HasSuffix(hostname, "."+entry), nil
+	}
+}
+
+func matchHostnameGlob(hostname, pattern string) (bool, error) {
+	labels, patternLabels := strings.Split(hostname, "."), strings.Split(pattern, ".")
+	if len(labels) != len(patternLabels) {
+		// Still surface a malformed pattern
+		_, err := path.Match(pattern, "")
+		return false, err
+	}
+	for i := range labels {
+		matched, err := path.Match(patternLabels[i], labels[i])
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// regexp returns the compiled regex entry of the namespace, compiling each
+// pattern once per cached version of the namespace.
+func (c *allowedHostnamesCache) regexp(namespace, pattern string) (*regexp.Regexp, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.namespaces[namespace]
+	if re, found := cached.regexps[pattern]; found {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if ok && cached.regexps != nil {
+		cached.regexps[pattern] = re
+	}
+	return re, nil
+}
+
+// forget evicts the cached entries of a deleted namespace.
+func (c *allowedHostnamesCache) forget(namespace string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.namespaces, namespace)
+}
+
+type parsedAllowedHostnames struct {
+	resourceVersion string
+	entries         []string
+	malformed       bool
+	regexps         map[string]*regexp.Regexp
+}
+
+// parse returns the parsed allowed-hostnames annotation of the namespace,
+// reusing the previous result while the namespace is unchanged.
+func (c *allowedHostnamesCache) parse(ns *corev1.Namespace, annotation string) (entries []string, malformed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.namespaces[ns.Name]; ok && ns.ResourceVersion != "" && cached.resourceVersion == ns.ResourceVersion {
+		return cached.entries, cached.malformed
+	}
+
+	entries, malformed = parseAllowedHostnames(ns.Annotations[annotation])
+	if c.namespaces == nil {
+		c.namespaces = make(map[string]parsedAllowedHostnames)
+	}
+	c.namespaces[ns.Name] = parsedAllowedHostnames{
+		resourceVersion: ns.ResourceVersion,
+		entries:         entries,
+		malformed:       malformed,
+		regexps:         make(map[string]*regexp.Regexp),
+	}
+	return entries, malformed
+}
+
+// hostnameClaimOwner returns the validated namespace owning the claim on a
+// hostname: the oldest namespace with an allowed-hostnames entry matching
+// it, whatever the entry's kind, with the namespace name breaking ties so
+// the outcome does not depend on reconcile order. An empty result means no
+// validated namespace claims the hostname.
+func (r *HTTPRouteReconciler) hostnameClaimOwner(ctx context.Context, hostname string) (string, error) {
+	// Domain entries matching the hostname are the hostname itself or one of
+	// its parent domains
+	keys := []string{patternClaimKey}
+	for domain := hostname; domain != ""; {
+		keys = append(keys, domain)
+		_, parent, found := strings.Cut(domain, ".")
+		if !found {
+			break
+		}
+		domain = parent
+	}
+
+	var owner *corev1.Namespace
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		var namespaces corev1.NamespaceList
+		if err := r.List(ctx, &namespaces, client.MatchingFields{hostnameClaimIndex: key}); err != nil {
+			return "", fmt.Errorf("failed to list namespaces claiming %s: %w", hostname, err)
+		}
+		for i := range namespaces.Items {
+			ns := &namespaces.Items[i]
+			if seen[ns.Name] || !r.isValidatedNamespace(ns) {
+				continue
+			}
+			seen[ns.Name] = true
+			if !r.claimsHostname(ns, hostname) {
+				continue
+			}
+			if owner == nil || ns.CreationTimestamp.Before(&owner.CreationTimestamp) ||
+				(ns.CreationTimestamp.Equal(&owner.CreationTimestamp) && ns.Name < owner.Name) {
+				owner = ns
+			}
+		}
+	}
+	if owner == nil {
+		return "", nil
+	}
+	return owner.Name, nil
+}
+
+// claimsHostname reports whether one of the evaluated allowed-hostnames
+// entries of ns matches hostname. Invalid entries claim nothing.
+func (r *HTTPRouteReconciler) claimsHostname(ns *corev1.Namespace, hostname string) bool {
+	entries, _ := r.allowedHostnames.parse(ns, r.AllowedHostnamesAnnotation)
+	if r.MaxAllowedHostnames > 0 && len(entries) > r.MaxAllowedHostnames {
+		entries = entries[:r.MaxAllowedHostnames]
+	}
+	for _, entry := range entries {
+		if matched, err := r.allowedHostnames.match(ns.Name, hostname, entry); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,359 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func claimingNamespace(name string, created time.Time, claims string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+			Annotations: map[string]string{
+				"gateway-auto-listener/allowed-hostnames": claims,
+			},
+		},
+	}
+}
+
+func TestParseAllowedHostnames(t *testing.T) {
+	tests := []struct {
+		value     string
+		entries   string
+		malformed bool
+	}{
+		{"", "", false},
+		{"a.org", "a.org", false},
+		{"a.org, b.net", "a.org|b.net", false},
+		{" a.org, ,b.net,", "a.org|b.net", true},
+		{",,", "", true},
+		{"a.org b.net, c.io", "c.io", true},
+		{"glob:*.Preview.acme.io, regex:^pr-\\d+\\.acme\\.io$", `glob:*.Preview.acme.io|regex:^pr-\d+\.acme\.io$`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			entries, malformed := parseAllowedHostnames(tt.value)
+			if strings.Join(entries, "|") != tt.entries || malformed != tt.malformed {
+				t.Errorf("parseAllowedHostnames(%q) = %v, %v, want %q, %v",
+					tt.value, entries, malformed, tt.entries, tt.malformed)
+			}
+		})
+	}
+}
+
+func TestValidateHostname_ClaimConflict(t *testing.T) {
+	now := time.Now()
+	first := claimingNamespace("tenant-a", now.Add(-time.Hour), "shared.org")
+	second := claimingNamespace("tenant-b", now, "shared.org, own.org")
+	r := newReconciler(first, second)
+	ctx := context.Background()
+
+	if err := r.validateHostname(ctx, "app.shared.org", "tenant-a"); err != nil {
+		t.Errorf("oldest claimant should be allowed, got: %v", err)
+	}
+
+	err := r.validateHostname(ctx, "app.shared.org", "tenant-b")
+	if !errors.Is(err, errHostnameClaimConflict) {
+		t.Errorf("second claimant should get a claim conflict, got: %v", err)
+	}
+
+	if err := r.validateHostname(ctx, "own.org", "tenant-b"); err != nil {
+		t.Errorf("unshared custom domain should be allowed, got: %v", err)
+	}
+
+	r.RejectHostnameClaimConflicts = false
+	if err := r.validateHostname(ctx, "app.shared.org", "tenant-b"); err != nil {
+		t.Errorf("conflict detection disabled should allow both claimants, got: %v", err)
+	}
+}
+
+func TestValidateHostname_ClaimConflictTieBreak(t *testing.T) {
+	created := time.Now()
+	r := newReconciler(
+		claimingNamespace("tenant-z", created, "shared.org"),
+		claimingNamespace("tenant-m", created, "shared.org"),
+	)
+	ctx := context.Background()
+
+	if err := r.validateHostname(ctx, "shared.org", "tenant-m"); err != nil {
+		t.Errorf("name tie-break should favour tenant-m, got: %v", err)
+	}
+	if err := r.validateHostname(ctx, "shared.org", "tenant-z"); !errors.Is(err, errHostnameClaimConflict) {
+		t.Errorf("tenant-z should lose the tie-break, got: %v", err)
+	}
+}
+
+func TestValidateHostname_ClaimConflictAcrossEntryKinds(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name  string
+		entry string
+	}{
+		{"glob", "glob:*.shared.org"},
+		{"regex", `regex:^app\.shared\.org$`},
+		{"parent domain", "shared.org"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReconciler(
+				claimingNamespace("tenant-a", now.Add(-time.Hour), "app.shared.org"),
+				claimingNamespace("tenant-b", now, tt.entry),
+			)
+			ctx := context.Background()
+
+			err := r.validateHostname(ctx, "app.shared.org", "tenant-b")
+			if !errors.Is(err, errHostnameClaimConflict) {
+				t.Errorf("younger %s entry should lose to the older exact claim, got: %v", tt.name, err)
+			}
+			if err := r.validateHostname(ctx, "app.shared.org", "tenant-a"); err != nil {
+				t.Errorf("older exact claim should be allowed, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateHostname_OlderPatternClaimWins(t *testing.T) {
+	now := time.Now()
+	r := newReconciler(
+		claimingNamespace("tenant-a", now.Add(-time.Hour), "glob:*.shared.org"),
+		claimingNamespace("tenant-b", now, "app.shared.org"),
+	)
+	ctx := context.Background()
+
+	if err := r.validateHostname(ctx, "app.shared.org", "tenant-b"); !errors.Is(err, errHostnameClaimConflict) {
+		t.Errorf("younger exact entry should lose to the older glob claim, got: %v", err)
+	}
+	if err := r.validateHostname(ctx, "other.shared.org", "tenant-a"); err != nil {
+		t.Errorf("glob claimant should be allowed, got: %v", err)
+	}
+}
+
+func TestReconcile_ClaimConflictRecordsEvent(t *testing.T) {
+	now := time.Now()
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "shop",
+			Namespace:  "tenant-b",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"shop.shared.org"},
+		},
+	}
+
+	r := newReconciler(
+		claimingNamespace("tenant-a", now.Add(-time.Hour), "shared.org"),
+		claimingNamespace("tenant-b", now, "shared.org"),
+		gateway, httpRoute,
+	)
+	fakeRecorder := record.NewFakeRecorder(10)
+	r.Recorder = fakeRecorder
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "shop", Namespace: "tenant-b"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected no listener for conflicting claim, got %d", len(gw.Spec.Listeners))
+	}
+
+	events := drainEvents(fakeRecorder)
+	if len(events) != 1 || !strings.HasPrefix(events[0], "Warning HostnameClaimConflict") {
+		t.Errorf("expected a HostnameClaimConflict event, got %v", events)
+	}
+}
+
+func TestValidateHostname_MaxAllowedHostnames(t *testing.T) {
+	ns := claimingNamespace("tenant-acme", time.Now(), "one.com,two.com,three.com")
+	r := newReconciler(ns)
+	r.MaxAllowedHostnames = 2
+	fakeRecorder := record.NewFakeRecorder(10)
+	r.Recorder = fakeRecorder
+	ctx := context.Background()
+
+	if err := r.validateHostname(ctx, "shop.two.com", "tenant-acme"); err != nil {
+		t.Errorf("expected entry within the limit to be allowed, got: %v", err)
+	}
+	if err := r.validateHostname(ctx, "shop.three.com", "tenant-acme"); err == nil {
+		t.Error("expected entry beyond the limit to be ignored")
+	}
+
+	events := drainEvents(fakeRecorder)
+	want := "Warning TooManyAllowedHostnames annotation gateway-auto-listener/allowed-hostnames has 3 entries, only the first 2 are evaluated"
+	if len(events) == 0 || events[0] != want {
+		t.Errorf("expected event %q, got %v", want, events)
+	}
+
+	r.MaxAllowedHostnames = 0
+	if err := r.validateHostname(ctx, "shop.three.com", "tenant-acme"); err != nil {
+		t.Errorf("expected no limit when disabled, got: %v", err)
+	}
+}
+
+func TestAllowedHostnamesCache(t *testing.T) {
+	ns := claimingNamespace("tenant-acme", time.Now(), "acme.com")
+	r := newReconciler(ns)
+	ctx := context.Background()
+
+	if err := r.validateHostname(ctx, "shop.acme.com", "tenant-acme"); err != nil {
+		t.Fatalf("expected hostname to be allowed, got: %v", err)
+	}
+
+	var current corev1.Namespace
+	_ = r.Get(ctx, types.NamespacedName{Name: "tenant-acme"}, &current)
+	cached := r.allowedHostnames.namespaces["tenant-acme"]
+	if cached.resourceVersion != current.ResourceVersion || strings.Join(cached.entries, ",") != "acme.com" {
+		t.Errorf("expected parsed entries cached for resourceVersion %s, got %+v", current.ResourceVersion, cached)
+	}
+
+	// A changed namespace must not be served from the cache
+	current.Annotations["gateway-auto-listener/allowed-hostnames"] = "acme.org"
+	if err := r.Update(ctx, &current); err != nil {
+		t.Fatalf("failed to update namespace: %v", err)
+	}
+	if err := r.validateHostname(ctx, "shop.acme.com", "tenant-acme"); err == nil {
+		t.Error("expected stale cache entry to be replaced after the namespace changed")
+	}
+	if err := r.validateHostname(ctx, "shop.acme.org", "tenant-acme"); err != nil {
+		t.Errorf("expected updated entry to be allowed, got: %v", err)
+	}
+
+	// An unchanged resourceVersion is served from the cache
+	r.allowedHostnames.namespaces["tenant-acme"] = parsedAllowedHostnames{
+		resourceVersion: current.ResourceVersion,
+		entries:         []string{"cached.net"},
+	}
+	if err := r.validateHostname(ctx, "shop.cached.net", "tenant-acme"); err != nil {
+		t.Errorf("expected cached entries to be used, got: %v", err)
+	}
+}
+
+func TestAllowedHostnamesCache_Eviction(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "tenant-acme",
+		Annotations: map[string]string{"gateway-auto-listener/allowed-hostnames": `regex:^pr-\d+\.acme\.com$`},
+	}}
+	r := newReconciler(ns)
+	ctx := context.Background()
+
+	if err := r.validateHostname(ctx, "pr-1.acme.com", "tenant-acme"); err != nil {
+		t.Fatalf("expected hostname to be allowed, got: %v", err)
+	}
+	if got := len(r.allowedHostnames.namespaces["tenant-acme"].regexps); got != 1 {
+		t.Errorf("expected the regex entry compiled with the namespace, got %d", got)
+	}
+
+	// A changed namespace drops the regexps of its previous entries
+	var current corev1.Namespace
+	_ = r.Get(ctx, types.NamespacedName{Name: "tenant-acme"}, &current)
+	current.Annotations["gateway-auto-listener/allowed-hostnames"] = "acme.org"
+	if err := r.Update(ctx, &current); err != nil {
+		t.Fatalf("failed to update namespace: %v", err)
+	}
+	if err := r.validateHostname(ctx, "shop.acme.org", "tenant-acme"); err != nil {
+		t.Fatalf("expected updated entry to be allowed, got: %v", err)
+	}
+	if got := len(r.allowedHostnames.namespaces["tenant-acme"].regexps); got != 0 {
+		t.Errorf("expected no regexps left after the entry changed, got %d", got)
+	}
+
+	r.namespacePolicyHandler().Delete(ctx, event.DeleteEvent{Object: &current}, nil)
+	if _, ok := r.allowedHostnames.namespaces["tenant-acme"]; ok {
+		t.Error("expected the deleted namespace to be evicted from the cache")
+	}
+}
+
+func TestParseAllowedHostnames_Internationalized(t *testing.T) {
+	entries, malformed := parseAllowedHostnames("café.example.org, shop.example.org")
+	if malformed {
+		t.Error("expected no malformed entries")
+	}
+	want := []string{"xn--caf-dma.example.org", "shop.example.org"}
+	if !slices.Equal(entries, want) {
+		t.Errorf("expected %v, got %v", want, entries)
+	}
+}
+
+func TestValidateHostname_PatternEntries(t *testing.T) {
+	ns := claimingNamespace("tenant-acme", time.Now(),
+		`acme.com, glob:*.preview.acme.io, glob:pr-*.acme.net, regex:^pr-\d+\.acme\.org$, regex:^(broken$`)
+	r := newReconciler(ns)
+	ctx := context.Background()
+
+	tests := []struct {
+		hostname string
+		allowed  bool
+	}{
+		// Plain entries keep matching the domain and its subdomains
+		{"acme.com", true},
+		{"shop.acme.com", true},
+		{"a.b.acme.com", true},
+		{"notacme.com", false},
+		// Globs match label by label
+		{"feature-x.preview.acme.io", true},
+		{"a.b.preview.acme.io", false},
+		{"preview.acme.io", false},
+		{"pr-12.acme.net", true},
+		{"app.pr-12.acme.net", false},
+		// Regexes match as written
+		{"pr-42.acme.org", true},
+		{"pr-x.acme.org", false},
+		{"app.pr-42.acme.org", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hostname, func(t *testing.T) {
+			err := r.validateHostname(ctx, tt.hostname, "tenant-acme")
+			if tt.allowed && err != nil {
+				t.Errorf("expected %s to be allowed, got: %v", tt.hostname, err)
+			}
+			if !tt.allowed && err == nil {
+				t.Errorf("expected %s to be rejected", tt.hostname)
+			}
+		})
+	}
+}
+
+func TestValidateHostname_InvalidPatternEntriesSkipped(t *testing.T) {
+	ns := claimingNamespace("tenant-acme", time.Now(), `regex:(unclosed, glob:[a-.acme.io, acme.com`)
+	r := newReconciler(ns)
+	ctx := context.Background()
+
+	if err := r.validateHostname(ctx, "shop.acme.com", "tenant-acme"); err != nil {
+		t.Errorf("expected valid entries to apply despite invalid ones, got: %v", err)
+	}
+	if err := r.validateHostname(ctx, "shop.acme.io", "tenant-acme"); err == nil {
+		t.Error("expected invalid entries to match nothing")
+	}
+}
@@ -0,0 +1,28 @@
+package controller
+
+import "strings"
+
+// instanceKey scopes a prefixed key such as an annotation or finalizer name
+// to a controller instance, so instances sharing a Gateway keep separate
+// bookkeeping. An empty instance ID leaves the key unchanged.
+func instanceKey(key, instanceID string) string {
+	if instanceID == "" {
+		return key
+	}
+	prefix, name, _ := strings.Cut(key, "/")
+	return prefix + "/" + truncateName(name+"."+instanceID, maxAnnotationNameLength)
+}
+
+// finalizer returns the route finalizer of this instance.
+func (r *HTTPRouteReconciler) finalizer() string {
+	return instanceKey(finalizerName, r.InstanceID)
+}
+
+// fieldOwner returns the server-side apply field manager of this instance,
+// so one instance's apply never relinquishes another's listeners.
+func (r *HTTPRouteReconciler) fieldOwner() string {
+	if r.InstanceID == "" {
+		return fieldManager
+	}
+	return fieldManager + "-" + r.InstanceID
+}
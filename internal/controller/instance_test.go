@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestInstanceKey(t *testing.T) {
+	if key := instanceKey(managedHostnamesAnnotation, ""); key != managedHostnamesAnnotation {
+		t.Errorf("expected an empty instance ID to keep %s, got %s", managedHostnamesAnnotation, key)
+	}
+	if key := instanceKey(finalizerName, "blue"); key != "gateway-auto-listener/finalizer.blue" {
+		t.Errorf("unexpected key %s", key)
+	}
+	long := instanceKey(managedHostnamesAnnotation+"."+strings.Repeat("n", 40), strings.Repeat("i", 40))
+	if _, name, _ := strings.Cut(long, "/"); len(name) > maxAnnotationNameLength {
+		t.Errorf("expected key name within %d characters, got %s", maxAnnotationNameLength, long)
+	}
+}
+
+func TestReconcile_InstancesShareGateway(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"blue.example.com", "green.example.com"},
+		},
+	}
+
+	blue := newReconciler(gateway, httpRoute)
+	blue.InstanceID = "blue"
+	blue.HostnameIncludePattern = regexp.MustCompile(`^blue\.`)
+	green := newReconciler()
+	green.Client = blue.Client
+	green.InstanceID = "green"
+	green.HostnameIncludePattern = regexp.MustCompile(`^green\.`)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	reconcile := func(r *HTTPRouteReconciler) {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error from instance %s: %v", r.InstanceID, err)
+		}
+	}
+	gatewayListeners := func() []string {
+		t.Helper()
+		var gw gatewayv1.Gateway
+		if err := blue.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw); err != nil {
+			t.Fatalf("failed to get gateway: %v", err)
+		}
+		return listenerNames(&gw)
+	}
+
+	// Finalizer and listener passes of both instances, interleaved
+	for _, r := range []*HTTPRouteReconciler{blue, green, blue, green, blue, green} {
+		reconcile(r)
+	}
+	want := []string{"https-blue-example-com", "https-green-example-com"}
+	if names := gatewayListeners(); !slices.Equal(names, want) {
+		t.Fatalf("expected listeners %v, got %v", want, names)
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = blue.Get(ctx, req.NamespacedName, &route)
+	for _, r := range []*HTTPRouteReconciler{blue, green} {
+		if !controllerutil.ContainsFinalizer(&route, r.finalizer()) {
+			t.Errorf("expected finalizer of instance %s, got %v", r.InstanceID, route.Finalizers)
+		}
+	}
+	if value := route.Annotations[blue.managedHostnamesKey(blue.primaryGateway())]; value != "https-blue-example-com" {
+		t.Errorf("expected instance blue to track only its listener, got %q", value)
+	}
+
+	// Deleting the route: each instance prunes only its own listener
+	if err := blue.Delete(ctx, &route); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	reconcile(blue)
+	if names := gatewayListeners(); !slices.Equal(names, []string{"https-green-example-com"}) {
+		t.Fatalf("expected instance blue to leave green's listener, got %v", names)
+	}
+	reconcile(green)
+	if names := gatewayListeners(); len(names) != 0 {
+		t.Errorf("expected no listeners once both instances cleaned up, got %v", names)
+	}
+	if err := blue.Get(ctx, req.NamespacedName, &route); !apierrors.IsNotFound(err) {
+		t.Errorf("expected route to be gone after both finalizers were removed, got %v", err)
+	}
+}
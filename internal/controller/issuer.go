@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// managedIssuerAnnotation records the issuer a route's listeners were last
+// created for, so RecreateOnIssuerChange can detect a switch.
+const managedIssuerAnnotation = "gateway-auto-listener/managed-issuer"
+
+// Kinds of cert-manager issuers a Certificate's issuerRef may name.
+const (
+	clusterIssuerKind = "ClusterIssuer"
+	issuerKind        = "Issuer"
+)
+
+// issuerRequeueInterval is how often a route whose listeners were skipped
+// for a missing issuer is reconciled again, as issuers are not watched.
+const issuerRequeueInterval = time.Minute
+
+// AddIssuersToScheme registers the cert-manager ClusterIssuer and Issuer as
+// unstructured types with the scheme, for ValidateIssuer.
+func AddIssuersToScheme(s *runtime.Scheme) {
+	for _, kind := range []string{clusterIssuerKind, issuerKind} {
+		gvk := certificateGVK.GroupVersion().WithKind(kind)
+		s.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+		s.AddKnownTypeWithName(gvk.GroupVersion().WithKind(kind+"List"), &unstructured.UnstructuredList{})
+	}
+}
+
+// routeIssuer returns the route's issuer as Kind/name, the cluster issuer
+// taking precedence.
+func routeIssuer(httpRoute *gatewayv1.HTTPRoute) string {
+	if name, ok := httpRoute.Annotations[clusterIssuerAnnotation]; ok {
+		return clusterIssuerKind + "/" + name
+	}
+	if name, ok := httpRoute.Annotations[issuerAnnotation]; ok {
+		return issuerKind + "/" + name
+	}
+	return ""
+}
+
+// ParseIssuerKind validates a -force-issuer-kind value: empty, ClusterIssuer
+// or Issuer.
+func ParseIssuerKind(value string) (string, error) {
+	switch value {
+	case "", clusterIssuerKind, issuerKind:
+		return value, nil
+	}
+	return "", fmt.Errorf("issuer kind must be %s or %s, got %q", clusterIssuerKind, issuerKind, value)
+}
+
+// issuerRef returns the kind and name of the issuer the route's Certificates
+// reference: the kind follows the annotation naming it unless ForceIssuerKind
+// overrides it. ok is false when the route names no issuer.
+func (r *HTTPRouteReconciler) issuerRef(httpRoute *gatewayv1.HTTPRoute) (kind, name string, ok bool) {
+	kind, name, ok = strings.Cut(routeIssuer(httpRoute), "/")
+	if ok && r.ForceIssuerKind != "" {
+		kind = r.ForceIssuerKind
+	}
+	return kind, name, ok
+}
+
+// validateIssuerRef checks an issuerRef before it is written to a
+// Certificate.
+func validateIssuerRef(kind, name string) error {
+	if _, err := ParseIssuerKind(kind); err != nil || kind == "" {
+		return fmt.Errorf("invalid issuer kind %q", kind)
+	}
+	if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+		return fmt.Errorf("invalid %s name %q: %s", kind, name, strings.Join(msgs, ", "))
+	}
+	return nil
+}
+
+// issuerChanged reports whether RecreateOnIssuerChange is set and the route's
+// issuer differs from the one its listeners were created for. Routes without
+// a recorded issuer have not changed.
+func (r *HTTPRouteReconciler) issuerChanged(httpRoute *gatewayv1.HTTPRoute) bool {
+	if !r.RecreateOnIssuerChange {
+		return false
+	}
+	previous, ok := httpRoute.Annotations[instanceKey(managedIssuerAnnotation, r.InstanceID)]
+	return ok && previous != routeIssuer(httpRoute)
+}
+
+// recordIssuer stores the route's current issuer in managedIssuerAnnotation,
+// scoped to the InstanceID.
+func (r *HTTPRouteReconciler) recordIssuer(ctx context.Context, httpRoute *gatewayv1.HTTPRoute) error {
+	issuer := routeIssuer(httpRoute)
+	annotationKey := instanceKey(managedIssuerAnnotation, r.InstanceID)
+	previous, ok := httpRoute.Annotations[annotationKey]
+	if ok && previous == issuer {
+		return nil
+	}
+	if ok {
+		log.FromContext(ctx).Info("issuer changed, recreating listeners", "from", previous, "to", issuer)
+		r.Recorder.Eventf(httpRoute, corev1.EventTypeNormal, "IssuerChanged",
+			"issuer changed from %s to %s, recreating listeners", previous, issuer)
+	}
+
+	if httpRoute.Annotations == nil {
+		httpRoute.Annotations = make(map[string]string)
+	}
+	httpRoute.Annotations[annotationKey] = issuer
+	if err := r.Update(ctx, httpRoute); err != nil {
+		return fmt.Errorf("failed to update httproute annotation: %w", err)
+	}
+	return nil
+}
+
+// issuerExists reports whether the route's issuer exists. An Issuer is looked
+// up in namespace, where the Certificate is issued, i.e. the Gateway's. Routes
+// naming no issuer pass, and without cert-manager installed no issuer exists.
+func (r *HTTPRouteReconciler) issuerExists(ctx context.Context, httpRoute *gatewayv1.HTTPRoute, namespace string) (bool, error) {
+	kind, name, ok := r.issuerRef(httpRoute)
+	if !ok {
+		return true, nil
+	}
+	issuer := &unstructured.Unstructured{}
+	issuer.SetGroupVersionKind(certificateGVK.GroupVersion().WithKind(kind))
+	key := types.NamespacedName{Name: name}
+	if kind == issuerKind {
+		key.Namespace = namespace
+	}
+	err := r.Get(ctx, key, issuer)
+	switch {
+	case apierrors.IsNotFound(err) || meta.IsNoMatchError(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to get %s %s: %w", kind, name, err)
+	}
+	return true, nil
+}
+
+// describeIssuer names the route's issuer for events, with the namespace an
+// Issuer is looked up in.
+func (r *HTTPRouteReconciler) describeIssuer(httpRoute *gatewayv1.HTTPRoute, namespace string) string {
+	kind, name, _ := r.issuerRef(httpRoute)
+	if kind == issuerKind {
+		return kind + "/" + name + " in namespace " + namespace
+	}
+	return kind + "/" + name
+}
@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func init() {
+	AddIssuersToScheme(scheme.Scheme)
+}
+
+func issuerObject(kind, namespace, name string) *unstructured.Unstructured {
+	issuer := &unstructured.Unstructured{}
+	issuer.SetGroupVersionKind(certificateGVK.GroupVersion().WithKind(kind))
+	issuer.SetNamespace(namespace)
+	issuer.SetName(name)
+	return issuer
+}
+
+func TestRouteIssuer(t *testing.T) {
+	tests := []struct {
+		annotations map[string]string
+		expected    string
+	}{
+		{map[string]string{clusterIssuerAnnotation: "letsencrypt"}, "ClusterIssuer/letsencrypt"},
+		{map[string]string{issuerAnnotation: "internal-ca"}, "Issuer/internal-ca"},
+		{map[string]string{clusterIssuerAnnotation: "letsencrypt", issuerAnnotation: "internal-ca"}, "ClusterIssuer/letsencrypt"},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		route := &gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+		if got := routeIssuer(route); got != tt.expected {
+			t.Errorf("routeIssuer(%v) = %q, want %q", tt.annotations, got, tt.expected)
+		}
+	}
+}
+
+func TestParseIssuerKind(t *testing.T) {
+	for _, value := range []string{"", "ClusterIssuer", "Issuer"} {
+		if got, err := ParseIssuerKind(value); err != nil || got != value {
+			t.Errorf("ParseIssuerKind(%q) = %q, %v, want it accepted", value, got, err)
+		}
+	}
+	for _, value := range []string{"issuer", "ExternalIssuer"} {
+		if _, err := ParseIssuerKind(value); err == nil {
+			t.Errorf("ParseIssuerKind(%q) should fail", value)
+		}
+	}
+}
+
+func TestReconcile_RecreateOnIssuerChange(t *testing.T) {
+	tests := []struct {
+		name          string
+		managedIssuer string
+		wantRecreate  bool
+	}{
+		{"issuer changed", "ClusterIssuer/letsencrypt-staging", true},
+		{"issuer unchanged", "ClusterIssuer/letsencrypt", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReconciler()
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "default",
+					Namespace:   "nginx-gateway",
+					Annotations: map[string]string{managedListenersAnnotation: "https-app-example-com"},
+				},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners: []gatewayv1.Listener{
+						r.buildListener("https-app-example-com", "app.example.com", 443, "nginx-gateway", "app-example-com-tls", nil),
+					},
+				},
+			}
+			httpRoute := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "app",
+					Namespace:  "default",
+					Finalizers: []string{finalizerName},
+					Annotations: map[string]string{
+						clusterIssuerAnnotation:    "letsencrypt",
+						managedHostnamesAnnotation: "https-app-example-com",
+						managedIssuerAnnotation:    tt.managedIssuer,
+					},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"app.example.com"},
+				},
+			}
+
+			var listenerCounts []int
+			r.RecreateOnIssuerChange = true
+			r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(gateway, httpRoute).WithStatusSubresource(httpRoute).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						if gw, ok := obj.(*gatewayv1.Gateway); ok {
+							listenerCounts = append(listenerCounts, len(gw.Spec.Listeners))
+						}
+						return c.Patch(ctx, obj, patch, opts...)
+					},
+				}).Build()
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+			for range 2 {
+				if _, err := r.Reconcile(ctx, req); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if tt.wantRecreate {
+				if len(listenerCounts) != 2 || listenerCounts[0] != 0 || listenerCounts[1] != 1 {
+					t.Errorf("expected the listener removed and re-added, got patches with %v listeners", listenerCounts)
+				}
+				if events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "IssuerChanged"); len(events) != 1 {
+					t.Errorf("expected one IssuerChanged event, got %v", events)
+				}
+			} else if len(listenerCounts) != 0 {
+				t.Errorf("expected no gateway patches, got patches with %v listeners", listenerCounts)
+			}
+
+			var route gatewayv1.HTTPRoute
+			_ = r.Get(ctx, req.NamespacedName, &route)
+			if route.Annotations[managedIssuerAnnotation] != "ClusterIssuer/letsencrypt" {
+				t.Errorf("expected current issuer recorded, got %q", route.Annotations[managedIssuerAnnotation])
+			}
+			if route.Annotations[managedHostnamesAnnotation] != "https-app-example-com" {
+				t.Errorf("expected listener tracked again, got %q", route.Annotations[managedHostnamesAnnotation])
+			}
+		})
+	}
+}
+
+func TestReconcile_ValidateIssuer(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		issuer      client.Object
+		wantCreated bool
+	}{
+		{
+			name:        "cluster issuer exists",
+			annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+			issuer:      issuerObject("ClusterIssuer", "", "letsencrypt"),
+			wantCreated: true,
+		},
+		{
+			name:        "issuer exists in gateway namespace",
+			annotations: map[string]string{issuerAnnotation: "internal-ca"},
+			issuer:      issuerObject("Issuer", "nginx-gateway", "internal-ca"),
+			wantCreated: true,
+		},
+		{
+			name:        "cluster issuer missing",
+			annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+			issuer:      issuerObject("ClusterIssuer", "", "letsencrypt-staging"),
+		},
+		{
+			name:        "issuer only in route namespace",
+			annotations: map[string]string{issuerAnnotation: "internal-ca"},
+			issuer:      issuerObject("Issuer", "default", "internal-ca"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReconciler(emptyGateway(), certificateRoute(tt.annotations), tt.issuer)
+			r.ValidateIssuer = true
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+			result, err := r.Reconcile(ctx, req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "IssuerNotFound")
+			if tt.wantCreated {
+				if len(gw.Spec.Listeners) != 1 {
+					t.Errorf("expected the listener to be created, got %v", listenerNames(&gw))
+				}
+				if len(events) != 0 {
+					t.Errorf("expected no IssuerNotFound events, got %v", events)
+				}
+				return
+			}
+			if len(gw.Spec.Listeners) != 0 {
+				t.Errorf("expected no listener for a missing issuer, got %v", listenerNames(&gw))
+			}
+			if len(events) != 1 {
+				t.Errorf("expected one IssuerNotFound event, got %v", events)
+			}
+			if result.RequeueAfter != issuerRequeueInterval {
+				t.Errorf("expected requeue after %v, got %v", issuerRequeueInterval, result.RequeueAfter)
+			}
+			var route gatewayv1.HTTPRoute
+			_ = r.Get(ctx, req.NamespacedName, &route)
+			if got := route.Annotations[managedHostnamesAnnotation]; got != "" {
+				t.Errorf("expected the skipped listener untracked, got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// Patterns and limits of the Gateway API listener schema.
+var (
+	sectionNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	hostnamePattern    = regexp.MustCompile(`^(\*\.)?[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+const maxListenerFieldLength = 253
+
+// validateListener checks a constructed listener against the parts of the
+// Gateway API schema and CEL rules the API server would otherwise reject the
+// whole Gateway patch for, naming the offending field.
+func validateListener(l gatewayv1.Listener) error {
+	var errs []error
+
+	switch name := string(l.Name); {
+	case name == "":
+		errs = append(errs, errors.New("name is empty"))
+	case len(name) > maxListenerFieldLength:
+		errs = append(errs, fmt.Errorf("name is %d characters, more than %d", len(name), maxListenerFieldLength))
+	case !sectionNamePattern.MatchString(name):
+		errs = append(errs, fmt.Errorf("name %q is not a lowercase DNS subdomain", name))
+	}
+
+	if l.Hostname != nil {
+		switch hostname := string(*l.Hostname); {
+		case len(hostname) > maxListenerFieldLength:
+			errs = append(errs, fmt.Errorf("hostname is %d characters, more than %d", len(hostname), maxListenerFieldLength))
+		case net.ParseIP(hostname) != nil:
+			errs = append(errs, fmt.Errorf("hostname %q is an IP address", hostname))
+		case !hostnamePattern.MatchString(hostname):
+			errs = append(errs, fmt.Errorf("hostname %q is not a lowercase DNS name with an optional leading wildcard", hostname))
+		}
+	}
+
+	if l.Port < 1 || l.Port > 65535 {
+		errs = append(errs, fmt.Errorf("port %d is outside 1-65535", l.Port))
+	}
+
+	switch l.Protocol {
+	case gatewayv1.HTTPSProtocolType, gatewayv1.TLSProtocolType:
+		if l.TLS == nil {
+			errs = append(errs, fmt.Errorf("protocol %s requires tls", l.Protocol))
+		}
+	case gatewayv1.HTTPProtocolType, gatewayv1.TCPProtocolType, gatewayv1.UDPProtocolType:
+		if l.TLS != nil {
+			errs = append(errs, fmt.Errorf("protocol %s must not set tls", l.Protocol))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("protocol %q is not supported", l.Protocol))
+	}
+
+	if l.TLS != nil {
+		mode := gatewayv1.TLSModeTerminate
+		if l.TLS.Mode != nil {
+			mode = *l.TLS.Mode
+		}
+		switch mode {
+		case gatewayv1.TLSModeTerminate:
+			if len(l.TLS.CertificateRefs) == 0 && len(l.TLS.Options) == 0 {
+				errs = append(errs, errors.New("tls mode Terminate requires certificateRefs or options"))
+			}
+		case gatewayv1.TLSModePassthrough:
+			if len(l.TLS.CertificateRefs) > 0 {
+				errs = append(errs, errors.New("tls mode Passthrough must not set certificateRefs"))
+			}
+			if l.Protocol == gatewayv1.HTTPSProtocolType {
+				errs = append(errs, errors.New("tls mode Passthrough is not allowed for protocol HTTPS"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("tls mode %q is not supported", mode))
+		}
+		for i, ref := range l.TLS.CertificateRefs {
+			if ref.Name == "" {
+				errs = append(errs, fmt.Errorf("tls certificateRefs[%d] has an empty name", i))
+			}
+		}
+	}
+
+	if l.AllowedRoutes != nil && l.AllowedRoutes.Namespaces != nil && l.AllowedRoutes.Namespaces.From != nil {
+		switch from := *l.AllowedRoutes.Namespaces.From; from {
+		case gatewayv1.NamespacesFromAll, gatewayv1.NamespacesFromSame:
+		case gatewayv1.NamespacesFromSelector:
+			if l.AllowedRoutes.Namespaces.Selector == nil {
+				errs = append(errs, errors.New("allowedRoutes namespaces from Selector requires a selector"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("allowedRoutes namespaces from %q is not supported", from))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// coveringWildcard returns the name of a wildcard listener on the port whose
+// hostname already matches hostname, e.g. *.example.com for app.example.com,
+// or "" when there is none.
+func coveringWildcard(listeners []gatewayv1.Listener, hostname string, port gatewayv1.PortNumber) string {
+	for _, l := range listeners {
+		if l.Port != port || l.Hostname == nil {
+			continue
+		}
+		suffix, ok := strings.CutPrefix(string(*l.Hostname), "*")
+		if ok && string(*l.Hostname) != hostname && strings.HasSuffix(hostname, suffix) {
+			return string(l.Name)
+		}
+	}
+	return ""
+}
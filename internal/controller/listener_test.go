@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestValidateListener(t *testing.T) {
+	r := newReconciler()
+	valid := func() gatewayv1.Listener {
+		return r.buildListener("https-app-example-com", "app.example.com", 443, "nginx-gateway", "app-example-com-tls", nil)
+	}
+	if err := validateListener(valid()); err != nil {
+		t.Fatalf("expected a built listener to be valid, got: %v", err)
+	}
+
+	passthrough := gatewayv1.TLSModePassthrough
+	ip := gatewayv1.Hostname("10.0.0.1")
+	upper := gatewayv1.Hostname("App.example.com")
+	selector := gatewayv1.NamespacesFromSelector
+
+	tests := []struct {
+		name   string
+		modify func(l *gatewayv1.Listener)
+		want   string
+	}{
+		{"empty name", func(l *gatewayv1.Listener) { l.Name = "" }, "name is empty"},
+		{"long name", func(l *gatewayv1.Listener) { l.Name = gatewayv1.SectionName(strings.Repeat("a", 254)) }, "more than 253"},
+		{"uppercase name", func(l *gatewayv1.Listener) { l.Name = "HTTPS-app" }, "not a lowercase DNS subdomain"},
+		{"IP hostname", func(l *gatewayv1.Listener) { l.Hostname = &ip }, "is an IP address"},
+		{"uppercase hostname", func(l *gatewayv1.Listener) { l.Hostname = &upper }, "not a lowercase DNS name"},
+		{"zero port", func(l *gatewayv1.Listener) { l.Port = 0 }, "outside 1-65535"},
+		{"HTTPS without TLS", func(l *gatewayv1.Listener) { l.TLS = nil }, "requires tls"},
+		{"HTTP with TLS", func(l *gatewayv1.Listener) { l.Protocol = gatewayv1.HTTPProtocolType }, "must not set tls"},
+		{"terminate without refs", func(l *gatewayv1.Listener) { l.TLS.CertificateRefs = nil }, "requires certificateRefs or options"},
+		{"passthrough with refs", func(l *gatewayv1.Listener) {
+			l.Protocol = gatewayv1.TLSProtocolType
+			l.TLS.Mode = &passthrough
+		}, "Passthrough must not set certificateRefs"},
+		{"HTTPS passthrough", func(l *gatewayv1.Listener) {
+			l.TLS.Mode = &passthrough
+			l.TLS.CertificateRefs = nil
+		}, "not allowed for protocol HTTPS"},
+		{"empty secret name", func(l *gatewayv1.Listener) { l.TLS.CertificateRefs[0].Name = "" }, "certificateRefs[0] has an empty name"},
+		{"selector without selector", func(l *gatewayv1.Listener) { l.AllowedRoutes.Namespaces.From = &selector }, "requires a selector"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := valid()
+			tt.modify(&l)
+			err := validateListener(l)
+			if err == nil {
+				t.Fatal("expected the listener to be rejected")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got: %v", tt.want, err)
+			}
+		})
+	}
+
+	l := valid()
+	l.Name = ""
+	l.Port = 0
+	if err := validateListener(l); err == nil || !strings.Contains(err.Error(), "name is empty") || !strings.Contains(err.Error(), "outside 1-65535") {
+		t.Errorf("expected every problem to be reported, got: %v", err)
+	}
+}
+
+func TestReconcile_ValidateListenersSkipsInvalid(t *testing.T) {
+	// 249 characters: a valid hostname whose listener name exceeds 253
+	long := strings.Join([]string{strings.Repeat("a", 63), strings.Repeat("b", 63), strings.Repeat("c", 63), strings.Repeat("d", 45)}, ".") + ".example.com"
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Finalizers:  []string{finalizerName},
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(long), "app.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	r.MaxListenerNameLength = 0
+	r.ValidateListeners = true
+	fakeRecorder := record.NewFakeRecorder(10)
+	r.Recorder = fakeRecorder
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if names := listenerNames(&gw); !slices.Equal(names, []string{"https-app-example-com"}) {
+		t.Errorf("expected only the valid listener to be added, got %v", names)
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, types.NamespacedName{Name: "app", Namespace: "default"}, &route)
+	if value := route.Annotations[managedHostnamesAnnotation]; value != "https-app-example-com" {
+		t.Errorf("expected only the valid listener to be tracked, got %q", value)
+	}
+
+	invalid := eventsWithReason(drainEvents(fakeRecorder), "InvalidListener")
+	if len(invalid) != 1 || !strings.Contains(invalid[0], "name is 255 characters, more than 253") {
+		t.Errorf("expected one InvalidListener event naming the name length, got %v", invalid)
+	}
+}
+
+func TestCoveringWildcard(t *testing.T) {
+	wildcard := gatewayv1.Hostname("*.example.com")
+	exact := gatewayv1.Hostname("app.example.com")
+	listeners := []gatewayv1.Listener{
+		{Name: "https-wildcard", Hostname: &wildcard, Port: 443},
+		{Name: "https-app", Hostname: &exact, Port: 443},
+		{Name: "catch-all", Port: 443},
+	}
+
+	tests := []struct {
+		hostname string
+		port     gatewayv1.PortNumber
+		want     string
+	}{
+		{"app.example.com", 443, "https-wildcard"},
+		{"a.b.example.com", 443, "https-wildcard"},
+		{"app.example.com", 8443, ""},
+		{"example.com", 443, ""},
+		{"app.other.org", 443, ""},
+		{"*.example.com", 443, ""},
+	}
+	for _, tt := range tests {
+		if got := coveringWildcard(listeners, tt.hostname, tt.port); got != tt.want {
+			t.Errorf("coveringWildcard(%q, %d) = %q, want %q", tt.hostname, tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestReconcile_SkipWildcardCovered(t *testing.T) {
+	wildcard := gatewayv1.Hostname("*.example.com")
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners: []gatewayv1.Listener{
+				{Name: "https-wildcard", Hostname: &wildcard, Port: 443, Protocol: gatewayv1.HTTPSProtocolType},
+			},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Finalizers:  []string{finalizerName},
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com", "app.other.org"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	r.SkipWildcardCovered = true
+	fakeRecorder := record.NewFakeRecorder(10)
+	r.Recorder = fakeRecorder
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if names := listenerNames(&gw); !slices.Equal(names, []string{"https-app-other-org", "https-wildcard"}) {
+		t.Errorf("expected only the uncovered hostname to get a listener, got %v", names)
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, types.NamespacedName{Name: "app", Namespace: "default"}, &route)
+	if value := route.Annotations[managedHostnamesAnnotation]; value != "https-app-other-org" {
+		t.Errorf("expected the covered hostname to be left untracked, got %q", value)
+	}
+
+	covered := eventsWithReason(drainEvents(fakeRecorder), "HostnameCoveredByWildcard")
+	if len(covered) != 1 || !strings.Contains(covered[0], "app.example.com is served by wildcard listener https-wildcard") {
+		t.Errorf("expected one HostnameCoveredByWildcard event for app.example.com, got %v", covered)
+	}
+}
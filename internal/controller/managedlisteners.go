@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"sort"
+	"strings"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// managedListenersAnnotation lists, on the Gateway, the listeners the
+// controller created. Removal only touches listeners in it, so a manual
+// listener whose name collides with a hostname's listener survives the
+// routes using it.
+const managedListenersAnnotation = "gateway-auto-listener/managed-listeners"
+
+// managedListenersKey returns the Gateway annotation of this instance
+// listing the listeners it created.
+func (r *HTTPRouteReconciler) managedListenersKey() string {
+	return instanceKey(managedListenersAnnotation, r.InstanceID)
+}
+
+// createdListeners returns the listener names recorded in the Gateway's
+// annotationKey and whether the Gateway has the annotation at all. Gateways
+// written before the annotation existed have none; their removals fall back
+// to the routes' bookkeeping.
+func createdListeners(gateway *gatewayv1.Gateway, annotationKey string) (map[string]bool, bool) {
+	value, ok := gateway.Annotations[annotationKey]
+	names := make(map[string]bool)
+	if value != "" {
+		for _, name := range strings.Split(value, ",") {
+			names[name] = true
+		}
+	}
+	return names, ok
+}
+
+// setCreatedListeners records names in the Gateway's annotationKey, keeping
+// only those of listeners still on the Gateway. The annotation stays when
+// empty, so the Gateway is not mistaken for one predating it.
+func setCreatedListeners(gateway *gatewayv1.Gateway, annotationKey string, names map[string]bool) {
+	var value []string
+	for _, l := range gateway.Spec.Listeners {
+		if names[string(l.Name)] {
+			value = append(value, string(l.Name))
+		}
+	}
+	sort.Strings(value)
+	if gateway.Annotations == nil {
+		gateway.Annotations = make(map[string]string)
+	}
+	gateway.Annotations[annotationKey] = strings.Join(value, ",")
+}
+
+// recordCreatedListeners updates the managed-listeners annotation of gateway
+// for the listeners added and removed since original. A Gateway without the
+// annotation is seeded with the listeners of original that managed reports,
+// as those predate it.
+func (r *HTTPRouteReconciler) recordCreatedListeners(gateway, original *gatewayv1.Gateway,
+	managed func(name string) bool, added, removed []string) {
+	key := r.managedListenersKey()
+	names, ok := createdListeners(original, key)
+	if !ok {
+		for _, l := range original.Spec.Listeners {
+			if managed(string(l.Name)) {
+				names[string(l.Name)] = true
+			}
+		}
+	}
+	for _, name := range removed {
+		delete(names, name)
+	}
+	for _, name := range added {
+		names[name] = true
+	}
+	setCreatedListeners(gateway, key, names)
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestReconcile_ManagedListenersAnnotation(t *testing.T) {
+	route := certificateRoute(map[string]string{clusterIssuerAnnotation: "letsencrypt"})
+	route.Spec.Hostnames = []gatewayv1.Hostname{"app.example.com", "api.example.com"}
+	r := newReconciler(emptyGateway(), route)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+	gwKey := types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}
+	annotation := func() string {
+		t.Helper()
+		var gw gatewayv1.Gateway
+		if err := r.Get(ctx, gwKey, &gw); err != nil {
+			t.Fatalf("failed to get gateway: %v", err)
+		}
+		return gw.Annotations[managedListenersAnnotation]
+	}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := annotation(), "https-api-example-com,https-app-example-com"; got != want {
+		t.Errorf("expected created listeners %q, got %q", want, got)
+	}
+
+	var current gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &current)
+	current.Spec.Hostnames = []gatewayv1.Hostname{"app.example.com"}
+	if err := r.Update(ctx, &current); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := annotation(), "https-app-example-com"; got != want {
+		t.Errorf("expected created listeners %q after removing a hostname, got %q", want, got)
+	}
+
+	_ = r.Get(ctx, req.NamespacedName, &current)
+	if err := r.Delete(ctx, &current); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, gwKey, &gw)
+	if value, ok := gw.Annotations[managedListenersAnnotation]; !ok || value != "" {
+		t.Errorf("expected an empty managed-listeners annotation after deletion, got %q (present: %v)", value, ok)
+	}
+}
+
+func TestReconcile_ManualListenerSurvivesRouteDeletion(t *testing.T) {
+	gateway := emptyGateway()
+	gateway.Spec.Listeners = []gatewayv1.Listener{
+		newReconciler().buildListener("https-app-example-com", "app.example.com", 443, "nginx-gateway", "manual-tls", nil),
+	}
+	r := newReconciler(gateway, certificateRoute(map[string]string{clusterIssuerAnnotation: "letsencrypt"}))
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	// The route tracks the manual listener it shares a name with
+	for range 2 {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if got := route.Annotations[managedHostnamesAnnotation]; got != "https-app-example-com" {
+		t.Fatalf("expected the route to track the listener, got %q", got)
+	}
+
+	if err := r.Delete(ctx, &route); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 || gw.Spec.Listeners[0].TLS.CertificateRefs[0].Name != "manual-tls" {
+		t.Errorf("expected the manual listener to survive route deletion, got %v", listenerNames(&gw))
+	}
+	if got := gw.Annotations[managedListenersAnnotation]; got != "" {
+		t.Errorf("expected no created listeners recorded, got %q", got)
+	}
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Namespace classes used as a low-cardinality metric label.
+const (
+	namespaceClassTenant   = "tenant"
+	namespaceClassPlatform = "platform"
+	namespaceClassOther    = "other"
+)
+
+var listenersCreatedTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gateway_auto_listener_listeners_created_total",
+		Help: "Number of Gateway listeners created, by namespace class of the owning route.",
+	},
+	[]string{"namespace_class"},
+)
+
+var hostnamesWouldRejectTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gateway_auto_listener_hostnames_would_reject_total",
+		Help: "Number of hostnames admitted in validation shadow mode that would otherwise have been rejected, by namespace class.",
+	},
+	[]string{"namespace_class"},
+)
+
+var driftCorrectionsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "gateway_auto_listener_drift_corrections_total",
+		Help: "Number of manual edits to managed listeners patched back to the desired state, by drifted field.",
+	},
+	[]string{"type"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(listenersCreatedTotal, hostnamesWouldRejectTotal, driftCorrectionsTotal)
+}
+
+// namespaceClass buckets a namespace for metric labels: tenant namespaces
+// match ValidatedNSPrefix, platform namespaces are the rest when a prefix is
+// configured, and everything is other when it isn't.
+func (r *HTTPRouteReconciler) namespaceClass(namespace string) string {
+	_, prefix := r.validationPolicy()
+	switch {
+	case prefix == "":
+		return namespaceClassOther
+	case strings.HasPrefix(namespace, prefix):
+		return namespaceClassTenant
+	default:
+		return namespaceClassPlatform
+	}
+}
@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		t.Fatalf("failed to read counter: %v", err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestNamespaceClass(t *testing.T) {
+	r := newReconciler()
+
+	tests := []struct {
+		prefix    string
+		namespace string
+		expected  string
+	}{
+		{"tenant-", "tenant-123", namespaceClassTenant},
+		{"tenant-", "nginx-gateway", namespaceClassPlatform},
+		{"", "tenant-123", namespaceClassOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix+tt.namespace, func(t *testing.T) {
+			r.ValidatedNSPrefix = tt.prefix
+			if got := r.namespaceClass(tt.namespace); got != tt.expected {
+				t.Errorf("namespaceClass(%q) = %q, want %q", tt.namespace, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReconcile_ListenersCreatedMetric(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-123"}}
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "tenant-123",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"a.tenant-123.example.com", "b.tenant-123.example.com"},
+		},
+	}
+
+	r := newReconciler(ns, gateway, httpRoute)
+	ctx := context.Background()
+
+	tenant := listenersCreatedTotal.WithLabelValues(namespaceClassTenant)
+	platform := listenersCreatedTotal.WithLabelValues(namespaceClassPlatform)
+	tenantBefore := counterValue(t, tenant)
+	platformBefore := counterValue(t, platform)
+
+	_, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "app", Namespace: "tenant-123"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := counterValue(t, tenant) - tenantBefore; got != 2 {
+		t.Errorf("expected tenant counter to increase by 2, got %v", got)
+	}
+	if got := counterValue(t, platform) - platformBefore; got != 0 {
+		t.Errorf("expected platform counter to be unchanged, got %v", got)
+	}
+}
+
+func TestReconcile_ShadowModeAdmitsRejectedHostname(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-bad"}}
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "bad-route",
+			Namespace:  "tenant-bad",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"evil.hacker.com"},
+		},
+	}
+
+	r := newReconciler(ns, gateway, httpRoute)
+	r.ValidationShadowMode = true
+	fakeRecorder := record.NewFakeRecorder(10)
+	r.Recorder = fakeRecorder
+	ctx := context.Background()
+
+	wouldReject := hostnamesWouldRejectTotal.WithLabelValues(namespaceClassTenant)
+	before := counterValue(t, wouldReject)
+
+	_, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "bad-route", Namespace: "tenant-bad"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 {
+		t.Errorf("expected listener to be created in shadow mode, got %d", len(gw.Spec.Listeners))
+	}
+
+	events := eventsWithReason(drainEvents(fakeRecorder), "WouldRejectHostname")
+	if len(events) != 1 || !strings.HasPrefix(events[0], "Warning WouldRejectHostname hostname evil.hacker.com") {
+		t.Errorf("expected a WouldRejectHostname event, got %v", events)
+	}
+	if got := counterValue(t, wouldReject) - before; got != 1 {
+		t.Errorf("expected would-reject counter to increase by 1, got %v", got)
+	}
+}
@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// GatewayMigration moves managed listeners from a previously managed Gateway
+// to the one now configured. It runs once on startup as a manager runnable;
+// listeners not tracked in any route's managed-hostnames annotation are left
+// on the old Gateway.
+type GatewayMigration struct {
+	client.Client
+	From types.NamespacedName
+	To   types.NamespacedName
+	// InstanceID limits the migration to the listeners of one controller
+	// instance, matching HTTPRouteReconciler.InstanceID.
+	InstanceID string
+}
+
+// Start runs the migration. It implements manager.Runnable.
+func (m *GatewayMigration) Start(ctx context.Context) error {
+	return m.Migrate(ctx)
+}
+
+// NeedLeaderElection makes only the leader migrate listeners.
+func (m *GatewayMigration) NeedLeaderElection() bool {
+	return true
+}
+
+// Migrate copies the managed listeners of the old Gateway that the new one
+// lacks, then removes them from the old Gateway.
+func (m *GatewayMigration) Migrate(ctx context.Context) error {
+	log := log.FromContext(ctx).WithValues("from", m.From, "to", m.To)
+
+	managed, err := managedListenerNames(ctx, m, instanceKey(managedHostnamesAnnotation, m.InstanceID), client.ObjectKey{})
+	if err != nil {
+		return err
+	}
+
+	createdKey := instanceKey(managedListenersAnnotation, m.InstanceID)
+	var moved []gatewayv1.Listener
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var from gatewayv1.Gateway
+		if err := m.Get(ctx, m.From, &from); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		// Manual listeners tracked by a route stay where they are
+		created, recorded := createdListeners(&from, createdKey)
+		movable := func(l gatewayv1.Listener) bool {
+			return managed[string(l.Name)] && (!recorded || created[string(l.Name)])
+		}
+		moved = nil
+		for _, l := range from.Spec.Listeners {
+			if movable(l) {
+				moved = append(moved, l)
+			}
+		}
+		if len(moved) == 0 {
+			return nil
+		}
+
+		var to gatewayv1.Gateway
+		if err := m.Get(ctx, m.To, &to); err != nil {
+			return fmt.Errorf("failed to get gateway %s: %w", m.To, err)
+		}
+		existing := make(map[string]bool)
+		for _, l := range to.Spec.Listeners {
+			existing[string(l.Name)] = true
+		}
+		patch := client.MergeFromWithOptions(to.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		// A listener already on the new Gateway is left alone, and not
+		// recorded as created when it is a manual one
+		var appended []string
+		for _, l := range moved {
+			if existing[string(l.Name)] {
+				continue
+			}
+			to.Spec.Listeners = append(to.Spec.Listeners, m.retarget(l))
+			appended = append(appended, string(l.Name))
+		}
+		if to.Labels == nil {
+			to.Labels = make(map[string]string)
+		}
+		to.Labels[managedByLabel] = managedByValue
+		if toCreated, toRecorded := createdListeners(&to, createdKey); toRecorded || recorded {
+			for _, name := range appended {
+				toCreated[name] = true
+			}
+			setCreatedListeners(&to, createdKey, toCreated)
+		}
+		if err := m.Patch(ctx, &to, patch); err != nil {
+			return err
+		}
+
+		patch = client.MergeFromWithOptions(from.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		var kept []gatewayv1.Listener
+		for _, l := range from.Spec.Listeners {
+			if !movable(l) {
+				kept = append(kept, l)
+			}
+		}
+		from.Spec.Listeners = kept
+		if recorded {
+			setCreatedListeners(&from, createdKey, created)
+		}
+		return m.Patch(ctx, &from, patch)
+	}); err != nil {
+		return fmt.Errorf("failed to migrate listeners: %w", err)
+	}
+
+	log.Info("migrated managed listeners", "count", len(moved))
+	return nil
+}
+
+// retarget points certificate refs in the old Gateway's namespace at the new
+// Gateway's namespace, matching the listeners the controller itself builds.
+func (m *GatewayMigration) retarget(l gatewayv1.Listener) gatewayv1.Listener {
+	l = *l.DeepCopy()
+	if l.TLS == nil {
+		return l
+	}
+	for i, ref := range l.TLS.CertificateRefs {
+		if ref.Namespace != nil && string(*ref.Namespace) == m.From.Namespace {
+			ns := gatewayv1.Namespace(m.To.Namespace)
+			l.TLS.CertificateRefs[i].Namespace = &ns
+		}
+	}
+	return l
+}
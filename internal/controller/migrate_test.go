@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestGatewayMigration_MovesOnlyManagedListeners(t *testing.T) {
+	oldNamespace := gatewayv1.Namespace("nginx-gateway")
+	appHostname := gatewayv1.Hostname("app.example.com")
+	manualHostname := gatewayv1.Hostname("manual.example.com")
+	oldGateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "old", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners: []gatewayv1.Listener{
+				{
+					Name:     "https-app-example-com",
+					Hostname: &appHostname,
+					Port:     443,
+					Protocol: gatewayv1.HTTPSProtocolType,
+					TLS: &gatewayv1.ListenerTLSConfig{
+						CertificateRefs: []gatewayv1.SecretObjectReference{
+							{Name: "app-example-com-tls", Namespace: &oldNamespace},
+						},
+					},
+				},
+				{Name: "https-manual", Hostname: &manualHostname, Port: 443, Protocol: gatewayv1.HTTPSProtocolType},
+			},
+		},
+	}
+	newGateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "edge"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+			Annotations: map[string]string{
+				managedHostnamesAnnotation: "https-app-example-com",
+			},
+		},
+	}
+
+	r := newReconciler(oldGateway, newGateway, httpRoute)
+	m := &GatewayMigration{
+		Client: r.Client,
+		From:   types.NamespacedName{Namespace: "nginx-gateway", Name: "old"},
+		To:     types.NamespacedName{Namespace: "edge", Name: "default"},
+	}
+	ctx := context.Background()
+
+	if err := m.Migrate(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var to gatewayv1.Gateway
+	_ = r.Get(ctx, m.To, &to)
+	if len(to.Spec.Listeners) != 1 || to.Spec.Listeners[0].Name != "https-app-example-com" {
+		t.Fatalf("expected the managed listener on the new gateway, got %v", to.Spec.Listeners)
+	}
+	if ns := to.Spec.Listeners[0].TLS.CertificateRefs[0].Namespace; ns == nil || *ns != "edge" {
+		t.Errorf("expected certificate ref retargeted to the new gateway namespace, got %v", ns)
+	}
+	if to.Labels[managedByLabel] != managedByValue {
+		t.Errorf("expected managed-by label on the new gateway")
+	}
+
+	var from gatewayv1.Gateway
+	_ = r.Get(ctx, m.From, &from)
+	if len(from.Spec.Listeners) != 1 || from.Spec.Listeners[0].Name != "https-manual" {
+		t.Errorf("expected only the manual listener to remain on the old gateway, got %v", from.Spec.Listeners)
+	}
+
+	// Running again is a no-op
+	if err := m.Migrate(ctx); err != nil {
+		t.Fatalf("unexpected error on second run: %v", err)
+	}
+	_ = r.Get(ctx, m.To, &to)
+	if len(to.Spec.Listeners) != 1 {
+		t.Errorf("expected migration to be idempotent, got %d listeners", len(to.Spec.Listeners))
+	}
+}
+
+func TestGatewayMigration_MissingOldGateway(t *testing.T) {
+	r := newReconciler()
+	m := &GatewayMigration{
+		Client: r.Client,
+		From:   types.NamespacedName{Namespace: "nginx-gateway", Name: "old"},
+		To:     types.NamespacedName{Namespace: "nginx-gateway", Name: "default"},
+	}
+	if err := m.Migrate(context.Background()); err != nil {
+		t.Errorf("expected a missing old gateway to be ignored, got: %v", err)
+	}
+}
+
+func TestGatewayMigration_KeepsManualListenerUnrecorded(t *testing.T) {
+	appHostname := gatewayv1.Hostname("app.example.com")
+	listener := func(cert string) gatewayv1.Listener {
+		return gatewayv1.Listener{
+			Name:     "https-app-example-com",
+			Hostname: &appHostname,
+			Port:     443,
+			Protocol: gatewayv1.HTTPSProtocolType,
+			TLS: &gatewayv1.ListenerTLSConfig{
+				CertificateRefs: []gatewayv1.SecretObjectReference{{Name: gatewayv1.ObjectName(cert)}},
+			},
+		}
+	}
+	oldGateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "old",
+			Namespace:   "nginx-gateway",
+			Annotations: map[string]string{managedListenersAnnotation: "https-app-example-com"},
+		},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{listener("app-example-com-tls")},
+		},
+	}
+	newGateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "default",
+			Namespace:   "edge",
+			Annotations: map[string]string{managedListenersAnnotation: ""},
+		},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{listener("manual-tls")},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Annotations: map[string]string{managedHostnamesAnnotation: "https-app-example-com"},
+		},
+	}
+
+	r := newReconciler(oldGateway, newGateway, httpRoute)
+	m := &GatewayMigration{
+		Client: r.Client,
+		From:   types.NamespacedName{Namespace: "nginx-gateway", Name: "old"},
+		To:     types.NamespacedName{Namespace: "edge", Name: "default"},
+	}
+	ctx := context.Background()
+
+	if err := m.Migrate(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var to gatewayv1.Gateway
+	_ = r.Get(ctx, m.To, &to)
+	if len(to.Spec.Listeners) != 1 || to.Spec.Listeners[0].TLS.CertificateRefs[0].Name != "manual-tls" {
+		t.Fatalf("expected the manual listener to stay untouched, got %v", to.Spec.Listeners)
+	}
+	if got := to.Annotations[managedListenersAnnotation]; got != "" {
+		t.Errorf("expected the manual listener not to be recorded as created, got %q", got)
+	}
+}
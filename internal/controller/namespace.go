@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"context"
+	"maps"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// namespacePolicyPredicate passes Namespace updates that change the hostname
+// policy of the namespace: its allowed-hostnames annotation or, with
+// ValidatedNSSelector set, its labels, and deletions, which evict the
+// namespace's cached policy. Other updates and creations are dropped so
+// unrelated namespace churn does not re-reconcile every route.
+func (r *HTTPRouteReconciler) namespacePolicyPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return true },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			if r.AllowedHostnamesAnnotation != "" &&
+				e.ObjectOld.GetAnnotations()[r.AllowedHostnamesAnnotation] != e.ObjectNew.GetAnnotations()[r.AllowedHostnamesAnnotation] {
+				return true
+			}
+			return r.ValidatedNSSelector != nil && !maps.Equal(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+		},
+	}
+}
+
+// namespacePolicyHandler enqueues the routes affected by a Namespace update
+// that passed namespacePolicyPredicate. It sees both versions of the
+// Namespace, so routes whose claims a dropped entry conflicted with are
+// re-validated too. A deleted Namespace is evicted from the allowed-hostnames
+// cache.
+func (r *HTTPRouteReconciler) namespacePolicyHandler() handler.EventHandler {
+	return handler.Funcs{
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			for _, req := range r.namespaceToHTTPRoutes(ctx, e.ObjectOld, e.ObjectNew) {
+				q.Add(req)
+			}
+		},
+		DeleteFunc: func(_ context.Context, e event.DeleteEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			r.allowedHostnames.forget(e.Object.GetName())
+		},
+	}
+}
+
+// namespaceToHTTPRoutes maps a Namespace whose hostname policy changed, given
+// its old and new version, to the managed HTTPRoutes in it, so routes
+// rejected before a custom domain was allowed are re-validated. As claims
+// decide which namespace owns a hostname, routes in namespaces with entries
+// overlapping the old or new ones are included as well.
+func (r *HTTPRouteReconciler) namespaceToHTTPRoutes(ctx context.Context, oldObj, newObj client.Object) []reconcile.Request {
+	if newObj == nil {
+		return nil
+	}
+	namespaces := map[string]bool{newObj.GetName(): true}
+	if r.AllowedHostnamesAnnotation != "" && r.RejectHostnameClaimConflicts {
+		entries, _ := parseAllowedHostnames(newObj.GetAnnotations()[r.AllowedHostnamesAnnotation])
+		if oldObj != nil {
+			old, _ := parseAllowedHostnames(oldObj.GetAnnotations()[r.AllowedHostnamesAnnotation])
+			entries = append(entries, old...)
+		}
+		if len(entries) > 0 {
+			var list corev1.NamespaceList
+			if err := r.List(ctx, &list); err != nil {
+				return nil
+			}
+			for i := range list.Items {
+				ns := &list.Items[i]
+				others, _ := parseAllowedHostnames(ns.Annotations[r.AllowedHostnamesAnnotation])
+				if claimsOverlap(entries, others) {
+					namespaces[ns.Name] = true
+				}
+			}
+		}
+	}
+	return r.managedRouteRequests(ctx, func(route *gatewayv1.HTTPRoute) bool {
+		return namespaces[route.Namespace]
+	})
+}
+
+// claimsOverlap reports whether an allowed-hostnames entry of a may admit a
+// hostname an entry of b admits. Domain entries overlap when one is the
+// other or a subdomain of it; glob: and regex: entries are assumed to
+// overlap anything.
+func claimsOverlap(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if isPatternEntry(x) || isPatternEntry(y) ||
+				x == y || strings.HasSuffix(x, "."+y) || strings.HasSuffix(y, "."+x) {
+				return true
+			}
+		}
+	}
+	return false
+}
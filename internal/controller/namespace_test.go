@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestNamespaceToHTTPRoutes(t *testing.T) {
+	route := func(namespace, name string, annotations map[string]string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   namespace,
+				Finalizers:  []string{finalizerName},
+				Annotations: annotations,
+			},
+		}
+	}
+	issuer := map[string]string{clusterIssuerAnnotation: "letsencrypt"}
+
+	r := newReconciler(
+		route("tenant-acme", "app", issuer),
+		route("tenant-acme", "shop", issuer),
+		route("tenant-acme", "plain", nil),
+		route("tenant-other", "app", issuer),
+	)
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-acme"}}
+
+	requests := r.namespaceToHTTPRoutes(context.Background(), ns, ns)
+	var got []types.NamespacedName
+	for _, req := range requests {
+		got = append(got, req.NamespacedName)
+	}
+	want := []types.NamespacedName{{Namespace: "tenant-acme", Name: "app"}, {Namespace: "tenant-acme", Name: "shop"}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("expected the managed routes of tenant-acme, got %v", got)
+	}
+}
+
+func TestNamespaceToHTTPRoutes_OverlappingClaims(t *testing.T) {
+	route := func(namespace string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "app",
+				Namespace:   namespace,
+				Finalizers:  []string{finalizerName},
+				Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+			},
+		}
+	}
+	now := time.Now()
+	changed := claimingNamespace("tenant-a", now, "shared.org")
+	previous := changed.DeepCopy()
+	previous.Annotations["gateway-auto-listener/allowed-hostnames"] = "old.io"
+
+	r := newReconciler(
+		changed,
+		claimingNamespace("tenant-sub", now, "app.shared.org"),
+		claimingNamespace("tenant-old", now, "old.io"),
+		claimingNamespace("tenant-glob", now, "glob:*.other.net"),
+		claimingNamespace("tenant-unrelated", now, "unrelated.com"),
+		route("tenant-a"), route("tenant-sub"), route("tenant-old"), route("tenant-glob"), route("tenant-unrelated"),
+	)
+
+	var got []string
+	for _, req := range r.namespaceToHTTPRoutes(context.Background(), previous, changed) {
+		got = append(got, req.Namespace)
+	}
+	slices.Sort(got)
+	want := []string{"tenant-a", "tenant-glob", "tenant-old", "tenant-sub"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected routes of namespaces with overlapping claims %v, got %v", want, got)
+	}
+}
+
+func TestNamespacePolicyPredicate(t *testing.T) {
+	namespace := func(annotation string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "tenant-acme",
+			Labels:      labels,
+			Annotations: map[string]string{"gateway-auto-listener/allowed-hostnames": annotation, "unrelated": annotation + "-x"},
+		}}
+	}
+
+	r := newReconciler()
+	p := r.namespacePolicyPredicate()
+
+	if !p.Update(event.UpdateEvent{ObjectOld: namespace("acme.com", nil), ObjectNew: namespace("acme.com,acme.org", nil)}) {
+		t.Error("expected an allowed-hostnames change to pass")
+	}
+	unrelated := namespace("acme.com", nil)
+	unrelated.Annotations["unrelated"] = "changed"
+	if p.Update(event.UpdateEvent{ObjectOld: namespace("acme.com", nil), ObjectNew: unrelated}) {
+		t.Error("expected an unrelated annotation change to be dropped")
+	}
+	relabelled := event.UpdateEvent{ObjectOld: namespace("acme.com", nil), ObjectNew: namespace("acme.com", map[string]string{"tenant": "true"})}
+	if p.Update(relabelled) {
+		t.Error("expected a label change to be dropped without a namespace selector")
+	}
+	if p.Create(event.CreateEvent{Object: namespace("acme.com", nil)}) {
+		t.Error("expected creations to be dropped")
+	}
+	if !p.Delete(event.DeleteEvent{Object: namespace("acme.com", nil)}) {
+		t.Error("expected deletions to pass, evicting the namespace from the allowed-hostnames cache")
+	}
+
+	r.ValidatedNSSelector = labels.SelectorFromSet(labels.Set{"tenant": "true"})
+	if !r.namespacePolicyPredicate().Update(relabelled) {
+		t.Error("expected a label change to pass with a namespace selector")
+	}
+}
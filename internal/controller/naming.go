@@ -0,0 +1,227 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// Default name templates, producing https-<sanitized> listeners and
+// <sanitized>-tls secrets.
+const (
+	DefaultListenerNameTemplate = "https-{{.Sanitized}}"
+	DefaultSecretNameTemplate   = "{{.Sanitized}}-tls"
+)
+
+var (
+	defaultListenerNameTemplate = template.Must(template.New("listener-name").Parse(DefaultListenerNameTemplate))
+	defaultSecretNameTemplate   = template.Must(template.New("secret-name").Parse(DefaultSecretNameTemplate))
+)
+
+// DefaultHostnameTemplate renders the suffix of a namespace's default
+// subdomains, <anything>.<namespace>.<suffix>.
+const DefaultHostnameTemplate = ".{{.Namespace}}.{{.Suffix}}"
+
+var defaultHostnameTemplate = template.Must(template.New("default-hostname").Parse(DefaultHostnameTemplate))
+
+// HostnameTemplateData is what the default hostname template is executed
+// with.
+type HostnameTemplateData struct {
+	// Namespace is the route's namespace.
+	Namespace string
+	// Suffix is the allowed domain suffix, without a trailing dot.
+	Suffix string
+}
+
+// ParseHostnameTemplate parses the template rendering the suffix a
+// namespace's default subdomains end with, and checks that it renders a
+// valid pattern mentioning the namespace.
+func ParseHostnameTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("default-hostname").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := renderHostnameSuffix(tmpl, "tenant-acme", "example.com")
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(rendered, "tenant-acme") {
+		return nil, fmt.Errorf("default hostname %q does not depend on .Namespace, so every namespace would share it", rendered)
+	}
+	if _, err := hostnameSuffixPattern(rendered); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+func renderHostnameSuffix(tmpl *template.Template, namespace, suffix string) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, HostnameTemplateData{Namespace: namespace, Suffix: suffix}); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
+
+// hostnameSuffixPattern compiles a rendered default hostname suffix into a
+// pattern matching hostnames ending with it, a * matching any characters
+// within one label, e.g. .tenant-acme--*.example.com. A suffix not starting
+// with a dot must start a label, so tenant-a--*.example.com does not match
+// tenant-x-tenant-a--dev.example.com.
+func hostnameSuffixPattern(suffix string) (*regexp.Regexp, error) {
+	if suffix == "" {
+		return nil, fmt.Errorf("default hostname suffix is empty")
+	}
+	if strings.HasPrefix(suffix, "-") {
+		return nil, fmt.Errorf("default hostname suffix %q starts within a label", suffix)
+	}
+	quoted := strings.ReplaceAll(regexp.QuoteMeta(suffix), `\*`, `[^.]*`)
+	if !strings.HasPrefix(suffix, ".") {
+		quoted = `(^|\.)` + quoted
+	}
+	return regexp.Compile(quoted + "$")
+}
+
+func (r *HTTPRouteReconciler) defaultHostnameTemplate() *template.Template {
+	if r.DefaultHostnameTemplate != nil {
+		return r.DefaultHostnameTemplate
+	}
+	return defaultHostnameTemplate
+}
+
+// isDefaultHostname reports whether the hostname is one of the namespace's
+// default subdomains under the suffix, per the default hostname template.
+func (r *HTTPRouteReconciler) isDefaultHostname(hostname, namespace, suffix string) bool {
+	rendered, err := renderHostnameSuffix(r.defaultHostnameTemplate(), namespace, suffix)
+	if err != nil {
+		return false
+	}
+	pattern, err := hostnameSuffixPattern(rendered)
+	return err == nil && pattern.MatchString(hostname)
+}
+
+// dns1123LabelPattern matches the characters of a DNS-1123 label. Length is
+// left to truncation and the API server.
+var dns1123LabelPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// NameTemplateData is what listener and secret name templates are executed
+// with.
+type NameTemplateData struct {
+	// Hostname is the route hostname in ASCII form, without a trailing dot.
+	Hostname string
+	// Sanitized is the hostname with dots replaced by dashes and a leading
+	// wildcard spelled out, e.g. wildcard-example-com.
+	Sanitized string
+}
+
+// ParseNameTemplate parses a listener or secret name template and checks
+// that it renders a valid name for a sample hostname.
+func ParseNameTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	for _, hostname := range []string{"app.example.com", "*.example.com"} {
+		rendered, err := renderName(tmpl, hostname, sanitizeHostname(hostname))
+		if err != nil {
+			return nil, err
+		}
+		if err := checkName(name, rendered); err != nil {
+			return nil, fmt.Errorf("for hostname %s: %w", hostname, err)
+		}
+	}
+	return tmpl, nil
+}
+
+// sanitizeHostname turns a hostname into the dash-separated form names are
+// built from.
+func sanitizeHostname(hostname string) string {
+	sanitized := strings.ReplaceAll(trimTrailingDots(hostname), ".", "-")
+	return strings.ReplaceAll(sanitized, "*", "wildcard")
+}
+
+func renderName(tmpl *template.Template, hostname, sanitized string) (string, error) {
+	var b strings.Builder
+	data := NameTemplateData{Hostname: trimTrailingDots(hostname), Sanitized: sanitized}
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
+
+// checkName reports a rendered name that is not a DNS-1123 label.
+func checkName(kind, name string) error {
+	if !dns1123LabelPattern.MatchString(name) {
+		return fmt.Errorf("%s %q is not a valid DNS-1123 label: use lowercase alphanumerics and '-', starting and ending with an alphanumeric", kind, name)
+	}
+	return nil
+}
+
+func (r *HTTPRouteReconciler) listenerNameTemplate() *template.Template {
+	if r.ListenerNameTemplate != nil {
+		return r.ListenerNameTemplate
+	}
+	return defaultListenerNameTemplate
+}
+
+func (r *HTTPRouteReconciler) secretNameTemplate() *template.Template {
+	if r.SecretNameTemplate != nil {
+		return r.SecretNameTemplate
+	}
+	return defaultSecretNameTemplate
+}
+
+// hostnameToListenerName renders the listener name template for a hostname.
+// A template that fails to render yields an empty name; checkHostnameNames
+// reports why.
+func (r *HTTPRouteReconciler) hostnameToListenerName(hostname string) string {
+	name, _ := renderName(r.listenerNameTemplate(), hostname, sanitizeHostname(hostname))
+	return name
+}
+
+// hostnameToSecretName renders the secret name template for a hostname.
+func (r *HTTPRouteReconciler) hostnameToSecretName(hostname string) string {
+	name, _ := renderName(r.secretNameTemplate(), hostname, sanitizeHostname(hostname))
+	return name
+}
+
+// disambiguatedSecretName qualifies the secret name of a hostname with a hash
+// of the hostname, so hostnames sanitizing to the same name get distinct
+// secrets.
+func (r *HTTPRouteReconciler) disambiguatedSecretName(hostname string) string {
+	sum := sha256.Sum256([]byte(hostname))
+	sanitized := sanitizeHostname(hostname) + "-" + hex.EncodeToString(sum[:])[:nameHashLength]
+	name, _ := renderName(r.secretNameTemplate(), hostname, sanitized)
+	return name
+}
+
+// aggregateSecretName returns the name of the certificate Secret shared by
+// the listeners of the sorted hostnames: the secret name of the first one,
+// qualified with a hash of them all, so every hostname set gets its own
+// multi-SAN Secret.
+func (r *HTTPRouteReconciler) aggregateSecretName(hostnames []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(hostnames, ",")))
+	sanitized := sanitizeHostname(hostnames[0]) + "-" + hex.EncodeToString(sum[:])[:nameHashLength]
+	name, _ := renderName(r.secretNameTemplate(), hostnames[0], sanitized)
+	return name
+}
+
+// checkHostnameNames reports a hostname whose listener or secret name does
+// not render to a valid name.
+func (r *HTTPRouteReconciler) checkHostnameNames(hostname string) error {
+	sanitized := sanitizeHostname(hostname)
+	listenerName, err := renderName(r.listenerNameTemplate(), hostname, sanitized)
+	if err != nil {
+		return err
+	}
+	if err := checkName("listener name", truncateName(listenerName, r.MaxListenerNameLength)); err != nil {
+		return err
+	}
+	secretName, err := renderName(r.secretNameTemplate(), hostname, sanitized)
+	if err != nil {
+		return err
+	}
+	return checkName("secret name", secretName)
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestParseNameTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{"default listener", DefaultListenerNameTemplate, ""},
+		{"default secret", DefaultSecretNameTemplate, ""},
+		{"custom", "{{.Sanitized}}-cert", ""},
+		{"syntax error", "{{.Sanitized", "unclosed action"},
+		{"unknown field", "{{.Host}}-tls", "can't evaluate field Host"},
+		{"dots", "{{.Hostname}}-tls", "not a valid DNS-1123 label"},
+		{"uppercase", "HTTPS-{{.Sanitized}}", "not a valid DNS-1123 label"},
+		{"trailing dash", "{{.Sanitized}}-", "not a valid DNS-1123 label"},
+		{"empty", "", "not a valid DNS-1123 label"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseNameTemplate("secret name", tt.text)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseHostnameTemplate(t *testing.T) {
+	for _, text := range []string{DefaultHostnameTemplate, ".{{.Namespace}}--*.{{.Suffix}}", "{{.Namespace}}--*.{{.Suffix}}"} {
+		if _, err := ParseHostnameTemplate(text); err != nil {
+			t.Errorf("expected %q to parse, got: %v", text, err)
+		}
+	}
+	for _, text := range []string{"{{.Namespace", ".{{.Missing}}.{{.Suffix}}", ".apps.{{.Suffix}}", "-{{.Namespace}}.{{.Suffix}}", ""} {
+		if _, err := ParseHostnameTemplate(text); err == nil {
+			t.Errorf("expected %q to be rejected", text)
+		}
+	}
+}
+
+func TestNameTemplates_Custom(t *testing.T) {
+	listenerTemplate, err := ParseNameTemplate("listener name", "gw-{{.Sanitized}}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secretTemplate, err := ParseNameTemplate("secret name", "{{.Sanitized}}-cert")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Finalizers:  []string{finalizerName},
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com", "*.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	r.ListenerNameTemplate = listenerTemplate
+	r.SecretNameTemplate = secretTemplate
+	ctx := context.Background()
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	secrets := make(map[string]string)
+	for _, l := range gw.Spec.Listeners {
+		secrets[string(l.Name)] = string(l.TLS.CertificateRefs[0].Name)
+	}
+	want := map[string]string{
+		"gw-app-example-com":      "app-example-com-cert",
+		"gw-wildcard-example-com": "wildcard-example-com-cert",
+	}
+	if len(secrets) != len(want) {
+		t.Fatalf("expected listeners %v, got %v", want, secrets)
+	}
+	for name, secret := range want {
+		if secrets[name] != secret {
+			t.Errorf("expected listener %s with secret %s, got %q", name, secret, secrets[name])
+		}
+	}
+
+	if name := r.disambiguatedSecretName("app.example.com"); !strings.HasPrefix(name, "app-example-com-") || !strings.HasSuffix(name, "-cert") {
+		t.Errorf("expected disambiguated name to follow the template, got %s", name)
+	}
+}
+
+func TestNameTemplates_IllegalName(t *testing.T) {
+	// Renders a valid name for the sample hostnames, but not for bad.example.com
+	listenerTemplate, err := ParseNameTemplate("listener name",
+		`{{if eq .Hostname "bad.example.com"}}Bad_{{end}}https-{{.Sanitized}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Finalizers:  []string{finalizerName},
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"bad.example.com", "good.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	r.ListenerNameTemplate = listenerTemplate
+	ctx := context.Background()
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if names := listenerNames(&gw); len(names) != 1 || names[0] != "https-good-example-com" {
+		t.Errorf("expected only the listener with a valid name, got %v", names)
+	}
+
+	events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "InvalidGeneratedName")
+	if len(events) != 1 || !strings.Contains(events[0], `listener name "Bad_https-bad-example-com" is not a valid DNS-1123 label`) {
+		t.Errorf("expected one InvalidGeneratedName event naming the bad listener name, got %v", events)
+	}
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// enableObserveOnly routes every write of the reconciler, including events,
+// to the log instead of the cluster. The reconcile logic runs unchanged and
+// sees its writes succeed.
+func (r *HTTPRouteReconciler) enableObserveOnly() {
+	r.Client = observeOnlyClient{Client: r.Client}
+	r.Recorder = observeOnlyRecorder{log: ctrl.Log.WithName("observe-only")}
+}
+
+// observeOnlyClient logs and drops all writes.
+type observeOnlyClient struct {
+	client.Client
+}
+
+func logWrite(ctx context.Context, verb string, obj client.Object, keysAndValues ...any) {
+	keysAndValues = append([]any{"verb", verb, "kind", fmt.Sprintf("%T", obj),
+		"object", client.ObjectKeyFromObject(obj)}, keysAndValues...)
+	log.FromContext(ctx).Info("observe-only: would write", keysAndValues...)
+}
+
+func (c observeOnlyClient) Create(ctx context.Context, obj client.Object, _ ...client.CreateOption) error {
+	logWrite(ctx, "create", obj)
+	return nil
+}
+
+func (c observeOnlyClient) Update(ctx context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	logWrite(ctx, "update", obj)
+	return nil
+}
+
+func (c observeOnlyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, _ ...client.PatchOption) error {
+	data, _ := patch.Data(obj)
+	logWrite(ctx, "patch", obj, "patch", string(data))
+	return nil
+}
+
+func (c observeOnlyClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, _ ...client.ApplyOption) error {
+	log.FromContext(ctx).Info("observe-only: would write", "verb", "apply", "kind", fmt.Sprintf("%T", obj))
+	return nil
+}
+
+func (c observeOnlyClient) Delete(ctx context.Context, obj client.Object, _ ...client.DeleteOption) error {
+	logWrite(ctx, "delete", obj)
+	return nil
+}
+
+func (c observeOnlyClient) DeleteAllOf(ctx context.Context, obj client.Object, _ ...client.DeleteAllOfOption) error {
+	logWrite(ctx, "deletecollection", obj)
+	return nil
+}
+
+func (c observeOnlyClient) Status() client.SubResourceWriter {
+	return observeOnlySubResource{SubResourceClient: c.Client.SubResource("status"), name: "status"}
+}
+
+func (c observeOnlyClient) SubResource(name string) client.SubResourceClient {
+	return observeOnlySubResource{SubResourceClient: c.Client.SubResource(name), name: name}
+}
+
+// observeOnlySubResource logs and drops subresource writes.
+type observeOnlySubResource struct {
+	client.SubResourceClient
+	name string
+}
+
+func (s observeOnlySubResource) Create(ctx context.Context, obj client.Object, _ client.Object, _ ...client.SubResourceCreateOption) error {
+	logWrite(ctx, "create", obj, "subresource", s.name)
+	return nil
+}
+
+func (s observeOnlySubResource) Update(ctx context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+	logWrite(ctx, "update", obj, "subresource", s.name)
+	return nil
+}
+
+func (s observeOnlySubResource) Patch(ctx context.Context, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+	logWrite(ctx, "patch", obj, "subresource", s.name)
+	return nil
+}
+
+// observeOnlyRecorder logs events instead of recording them.
+type observeOnlyRecorder struct {
+	log logr.Logger
+}
+
+var _ record.EventRecorder = observeOnlyRecorder{}
+
+func (r observeOnlyRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.log.Info("would record event", "type", eventtype, "reason", reason, "message", message)
+}
+
+func (r observeOnlyRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...any) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r observeOnlyRecorder) AnnotatedEventf(object runtime.Object, _ map[string]string, eventtype, reason, messageFmt string, args ...any) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
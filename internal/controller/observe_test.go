@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// writeCountingClient builds a fake client that counts every write reaching it.
+func writeCountingClient(writes *int, objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				*writes++
+				return c.Create(ctx, obj, opts...)
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				*writes++
+				return c.Update(ctx, obj, opts...)
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				*writes++
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+			Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+				*writes++
+				return c.Apply(ctx, obj, opts...)
+			},
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				*writes++
+				return c.Delete(ctx, obj, opts...)
+			},
+			DeleteAllOf: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteAllOfOption) error {
+				*writes++
+				return c.DeleteAllOf(ctx, obj, opts...)
+			},
+			SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+				*writes++
+				return c.SubResource(subResourceName).Create(ctx, obj, subResource, opts...)
+			},
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				*writes++
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				*writes++
+				return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+}
+
+func TestReconcile_ObserveOnlyWritesNothing(t *testing.T) {
+	ns := gatewayv1.Namespace("nginx-gateway")
+	oldHostname := gatewayv1.Hostname("old.example.com")
+	tlsMode := gatewayv1.TLSModeTerminate
+	existingListener := gatewayv1.Listener{
+		Name:     "https-old-example-com",
+		Hostname: &oldHostname,
+		Port:     443,
+		Protocol: gatewayv1.HTTPSProtocolType,
+		TLS: &gatewayv1.ListenerTLSConfig{
+			Mode:            &tlsMode,
+			CertificateRefs: []gatewayv1.SecretObjectReference{{Name: "old-example-com-tls", Namespace: &ns}},
+		},
+	}
+	now := metav1.NewTime(time.Now())
+
+	tests := []struct {
+		name  string
+		route *gatewayv1.HTTPRoute
+	}{
+		{
+			name: "create",
+			route: &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-route",
+					Namespace:   "default",
+					Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{Hostnames: []gatewayv1.Hostname{"test.example.com"}},
+			},
+		},
+		{
+			name: "change",
+			route: &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-route",
+					Namespace:  "default",
+					Finalizers: []string{finalizerName},
+					Annotations: map[string]string{
+						clusterIssuerAnnotation:    "letsencrypt",
+						managedHostnamesAnnotation: "old.example.com",
+					},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{Hostnames: []gatewayv1.Hostname{"test.example.com"}},
+			},
+		},
+		{
+			name: "delete",
+			route: &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-route",
+					Namespace:         "default",
+					DeletionTimestamp: &now,
+					Finalizers:        []string{finalizerName},
+					Annotations: map[string]string{
+						clusterIssuerAnnotation:    "letsencrypt",
+						managedHostnamesAnnotation: "old.example.com",
+					},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{Hostnames: []gatewayv1.Hostname{"old.example.com"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{existingListener},
+				},
+			}
+
+			var writes int
+			recorder := record.NewFakeRecorder(100)
+			r := newReconciler()
+			r.Client = writeCountingClient(&writes, gateway, tt.route)
+			r.Recorder = recorder
+			r.ObserveOnly = true
+			r.enableObserveOnly()
+			ctx := context.Background()
+
+			for range 2 {
+				_, err := r.Reconcile(ctx, ctrl.Request{
+					NamespacedName: types.NamespacedName{Name: "test-route", Namespace: "default"},
+				})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if writes != 0 {
+				t.Errorf("expected no writes in observe-only mode, got %d", writes)
+			}
+			if events := drainEvents(recorder); len(events) != 0 {
+				t.Errorf("expected no recorded events in observe-only mode, got %v", events)
+			}
+
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			if names := listenerNames(&gw); len(names) != 1 || names[0] != "https-old-example-com" {
+				t.Errorf("expected the Gateway listeners to be untouched, got %v", names)
+			}
+		})
+	}
+}
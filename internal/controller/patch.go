@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapplyv1 "sigs.k8s.io/gateway-api/applyconfiguration/apis/v1"
+)
+
+// Strategies for writing listener changes to the Gateway.
+const (
+	// PatchStrategyMerge sends a JSON merge patch of the listener list.
+	PatchStrategyMerge = "merge"
+	// PatchStrategyOptimistic sends a merge patch guarded by the
+	// resourceVersion, failing with a conflict on concurrent changes.
+	PatchStrategyOptimistic = "optimistic"
+	// PatchStrategyApply uses server-side apply, owning only the listeners
+	// the controller manages.
+	PatchStrategyApply = "apply"
+)
+
+// fieldManager is the server-side apply field manager of the controller.
+const fieldManager = "gateway-auto-listener"
+
+// errGatewayConflict marks a Gateway patch rejected because the Gateway
+// changed since it was read.
+var errGatewayConflict = errors.New("gateway changed since it was read")
+
+// patchGateway writes the listeners and labels of gateway, changed from
+// original, using the configured PatchStrategy. Writes beyond
+// GatewayMutationRate fail with a gatewayThrottledError. With server-side apply only
+// the listeners for which managed returns true are sent, so manual listeners
+// are never owned by the controller.
+func (r *HTTPRouteReconciler) patchGateway(ctx context.Context, gateway, original *gatewayv1.Gateway, managed func(name string) bool) error {
+	if d := r.gatewayLimiters.delay(client.ObjectKeyFromObject(gateway), r.GatewayMutationRate); d > 0 {
+		return &gatewayThrottledError{delay: d}
+	}
+
+	var err error
+	switch r.PatchStrategy {
+	case PatchStrategyApply:
+		ac, acErr := gatewayApplyConfiguration(gateway, managed, r.managedListenersKey())
+		if acErr != nil {
+			return acErr
+		}
+		err = r.Apply(ctx, ac, client.FieldOwner(r.fieldOwner()), client.ForceOwnership)
+	case PatchStrategyOptimistic:
+		err = r.Patch(ctx, gateway, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+	default:
+		err = r.Patch(ctx, gateway, client.MergeFrom(original))
+	}
+	switch {
+	case apierrors.IsConflict(err):
+		return fmt.Errorf("failed to patch gateway: %w: %w", errGatewayConflict, err)
+	case err != nil:
+		return fmt.Errorf("failed to patch gateway: %w", err)
+	}
+	return nil
+}
+
+// retryGatewayConflicts runs fn, which reads, modifies and patches a
+// Gateway, again while the patch fails with errGatewayConflict, up to
+// retry.DefaultRetry attempts. Each attempt re-reads the Gateway, and starts
+// from the summary as it was before the first, so retried work is counted
+// once.
+func retryGatewayConflicts(summary *reconcileSummary, fn func() error) error {
+	saved := summary.clone()
+	return retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return errors.Is(err, errGatewayConflict)
+	}, func() error {
+		*summary = saved.clone()
+		return fn()
+	})
+}
+
+// listenerOwnership reports which listeners of a Gateway the controller
+// manages: those tracked under annotationKey by other routes, GRPCRoutes
+// included when managed, plus the given ones of this route.
+func (r *HTTPRouteReconciler) listenerOwnership(ctx context.Context, httpRoute *gatewayv1.HTTPRoute, annotationKey string, owned map[string]bool) (func(name string) bool, error) {
+	managed, err := managedListenerNames(ctx, r, annotationKey, client.ObjectKeyFromObject(httpRoute))
+	if err != nil {
+		return nil, err
+	}
+	grpcManaged := make(map[string]bool)
+	if r.ManageGRPCRoutes {
+		if grpcManaged, err = r.grpcRouteListenerNames(ctx, annotationKey, client.ObjectKey{}, false); err != nil {
+			return nil, err
+		}
+	}
+	return func(name string) bool {
+		return managed[name] || grpcManaged[name] || owned[name]
+	}, nil
+}
+
+// gatewayApplyConfiguration builds the apply configuration holding the
+// managed-by label, the managed listeners of the gateway and, when set, its
+// managed-listeners annotation under annotationKey.
+func gatewayApplyConfiguration(gateway *gatewayv1.Gateway, managed func(name string) bool, annotationKey string) (*gatewayapplyv1.GatewayApplyConfiguration, error) {
+	spec := gatewayapplyv1.GatewaySpec()
+	for _, l := range gateway.Spec.Listeners {
+		if !managed(string(l.Name)) {
+			continue
+		}
+		data, err := json.Marshal(l)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode listener %s: %w", l.Name, err)
+		}
+		listener := gatewayapplyv1.Listener()
+		if err := json.Unmarshal(data, listener); err != nil {
+			return nil, fmt.Errorf("failed to decode listener %s: %w", l.Name, err)
+		}
+		spec.WithListeners(listener)
+	}
+	// An empty list relinquishes every listener previously applied
+	if spec.Listeners == nil {
+		spec.Listeners = []gatewayapplyv1.ListenerApplyConfiguration{}
+	}
+
+	ac := gatewayapplyv1.Gateway(gateway.Name, gateway.Namespace).
+		WithLabels(map[string]string{managedByLabel: managedByValue}).
+		WithSpec(spec)
+	if value, ok := gateway.Annotations[annotationKey]; ok {
+		ac.WithAnnotations(map[string]string{annotationKey: value})
+	}
+	return ac, nil
+}
+
+// managedListenerNames collects the listener names tracked by the given
+// managed-hostnames annotation of every route except the excluded one.
+func managedListenerNames(ctx context.Context, c client.Reader, annotationKey string, exclude client.ObjectKey) (map[string]bool, error) {
+	var routes gatewayv1.HTTPRouteList
+	if err := c.List(ctx, &routes); err != nil {
+		return nil, fmt.Errorf("failed to list httproutes: %w", err)
+	}
+	managed := make(map[string]bool)
+	for _, route := range routes.Items {
+		if client.ObjectKeyFromObject(&route) == exclude {
+			continue
+		}
+		if prev := route.Annotations[annotationKey]; prev != "" {
+			for _, name := range strings.Split(prev, ",") {
+				managed[name] = true
+			}
+		}
+	}
+	return managed, nil
+}
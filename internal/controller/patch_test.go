@@ -0,0 +1,255 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/managedfields"
+	clientgoapplyconfigurations "k8s.io/client-go/applyconfigurations"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapply "sigs.k8s.io/gateway-api/applyconfiguration"
+)
+
+func patchStrategyFixtures() (*gatewayv1.Gateway, *gatewayv1.HTTPRoute) {
+	manualHostname := gatewayv1.Hostname("manual.example.com")
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners: []gatewayv1.Listener{
+				{Name: "https-manual", Hostname: &manualHostname, Port: 443, Protocol: gatewayv1.HTTPSProtocolType},
+			},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+	return gateway, httpRoute
+}
+
+// newApplyReconciler builds a reconciler whose fake client understands the
+// Gateway API schema, so server-side apply merges listeners by name.
+func newApplyReconciler(objs ...client.Object) *HTTPRouteReconciler {
+	r := newReconciler()
+	r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(objs...).
+		WithTypeConverters(
+			gatewayapply.NewTypeConverter(scheme.Scheme),
+			clientgoapplyconfigurations.NewTypeConverter(scheme.Scheme),
+			managedfields.NewDeducedTypeConverter(),
+		).
+		Build()
+	r.PatchStrategy = PatchStrategyApply
+	return r
+}
+
+func listenerNames(gw *gatewayv1.Gateway) []string {
+	var names []string
+	for _, l := range gw.Spec.Listeners {
+		names = append(names, string(l.Name))
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestReconcile_PatchStrategies(t *testing.T) {
+	for _, strategy := range []string{PatchStrategyMerge, PatchStrategyOptimistic, PatchStrategyApply} {
+		t.Run(strategy, func(t *testing.T) {
+			gateway, httpRoute := patchStrategyFixtures()
+			var r *HTTPRouteReconciler
+			if strategy == PatchStrategyApply {
+				r = newApplyReconciler(gateway, httpRoute)
+			} else {
+				r = newReconciler(gateway, httpRoute)
+				r.PatchStrategy = strategy
+			}
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			want := []string{"https-app-example-com", "https-manual"}
+			if got := listenerNames(&gw); !slices.Equal(got, want) {
+				t.Fatalf("expected listeners %v, got %v", want, got)
+			}
+			if gw.Spec.GatewayClassName != "nginx" {
+				t.Errorf("expected gatewayClassName to be preserved, got %q", gw.Spec.GatewayClassName)
+			}
+			if gw.Labels[managedByLabel] != managedByValue {
+				t.Errorf("expected managed-by label, got %v", gw.Labels)
+			}
+
+			// Deleting the route removes only its listener
+			var route gatewayv1.HTTPRoute
+			_ = r.Get(ctx, req.NamespacedName, &route)
+			if err := r.Delete(ctx, &route); err != nil {
+				t.Fatalf("failed to delete route: %v", err)
+			}
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			if got := listenerNames(&gw); !slices.Equal(got, []string{"https-manual"}) {
+				t.Errorf("expected only the manual listener to remain, got %v", got)
+			}
+		})
+	}
+}
+
+func TestReconcile_ApplyKeepsOtherRoutesListeners(t *testing.T) {
+	gateway, httpRoute := patchStrategyFixtures()
+	other := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "api",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"api.example.com"},
+		},
+	}
+
+	r := newApplyReconciler(gateway, httpRoute, other)
+	ctx := context.Background()
+
+	for _, name := range []string{"api", "app"} {
+		if _, err := r.Reconcile(ctx, ctrl.Request{
+			NamespacedName: types.NamespacedName{Name: name, Namespace: "default"},
+		}); err != nil {
+			t.Fatalf("unexpected error reconciling %s: %v", name, err)
+		}
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	want := []string{"https-api-example-com", "https-app-example-com", "https-manual"}
+	if got := listenerNames(&gw); !slices.Equal(got, want) {
+		t.Errorf("expected listeners %v, got %v", want, got)
+	}
+}
+
+func TestReconcile_RetriesGatewayPatchConflicts(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Finalizers:  []string{finalizerName},
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+
+	// Each first patch attempt loses a race against another writer adding a
+	// listener, so the retry has to re-read the Gateway to keep it
+	var patches, races int
+	conflict := true
+	c := fake.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(gateway, httpRoute).
+		WithStatusSubresource(httpRoute).
+		WithIndex(&corev1.Namespace{}, hostnameClaimIndex, hostnameClaimIndexer("gateway-auto-listener/allowed-hostnames")).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if _, ok := obj.(*gatewayv1.Gateway); !ok {
+					return c.Patch(ctx, obj, patch, opts...)
+				}
+				patches++
+				if !conflict {
+					return c.Patch(ctx, obj, patch, opts...)
+				}
+				conflict = false
+				races++
+				var current gatewayv1.Gateway
+				if err := c.Get(ctx, client.ObjectKeyFromObject(obj), &current); err != nil {
+					return err
+				}
+				hostname := gatewayv1.Hostname(fmt.Sprintf("manual-%d.example.com", races))
+				current.Spec.Listeners = append(current.Spec.Listeners, gatewayv1.Listener{
+					Name: gatewayv1.SectionName(fmt.Sprintf("https-manual-%d", races)), Hostname: &hostname,
+					Port: 443, Protocol: gatewayv1.HTTPSProtocolType,
+				})
+				if err := c.Update(ctx, &current); err != nil {
+					return err
+				}
+				return apierrors.NewConflict(schema.GroupResource{Group: gatewayv1.GroupName, Resource: "gateways"},
+					obj.GetName(), errors.New("gateway changed"))
+			},
+		}).
+		Build()
+
+	r := newReconciler()
+	r.Client = c
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("expected the conflict to be retried, got %v", err)
+	}
+	if patches != 2 {
+		t.Errorf("expected one conflicting and one successful patch, got %d patches", patches)
+	}
+	var gw gatewayv1.Gateway
+	_ = c.Get(ctx, client.ObjectKeyFromObject(gateway), &gw)
+	if names := listenerNames(&gw); !slices.Equal(names, []string{"https-app-example-com", "https-manual-1"}) {
+		t.Fatalf("expected the listener added next to the concurrent one, got %v", names)
+	}
+
+	// Removal retries the same way
+	patches = 0
+	conflict = true
+	var route gatewayv1.HTTPRoute
+	_ = c.Get(ctx, req.NamespacedName, &route)
+	if err := c.Delete(ctx, &route); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("expected the conflict to be retried, got %v", err)
+	}
+	if patches != 2 {
+		t.Errorf("expected one conflicting and one successful patch, got %d patches", patches)
+	}
+	_ = c.Get(ctx, client.ObjectKeyFromObject(gateway), &gw)
+	if names := listenerNames(&gw); !slices.Equal(names, []string{"https-manual-1", "https-manual-2"}) {
+		t.Errorf("expected only the concurrent listeners to remain, got %v", names)
+	}
+}
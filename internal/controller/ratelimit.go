@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// gatewayLimiters holds one token bucket per Gateway, throttling listener
+// mutations so bursts of route changes are spread out over time.
+type gatewayLimiters struct {
+	mu       sync.Mutex
+	limiters map[types.NamespacedName]*rate.Limiter
+}
+
+// delay takes a token for a mutation of the Gateway and returns zero, or
+// returns how long to wait for one without taking it. A non-positive rate
+// never delays.
+func (g *gatewayLimiters) delay(gateway types.NamespacedName, perSecond float64) time.Duration {
+	if perSecond <= 0 {
+		return 0
+	}
+
+	g.mu.Lock()
+	limiter, ok := g.limiters[gateway]
+	if !ok {
+		if g.limiters == nil {
+			g.limiters = make(map[types.NamespacedName]*rate.Limiter)
+		}
+		limiter = rate.NewLimiter(rate.Limit(perSecond), 1)
+		g.limiters[gateway] = limiter
+	}
+	g.mu.Unlock()
+
+	reservation := limiter.Reserve()
+	if d := reservation.Delay(); d > 0 {
+		reservation.Cancel()
+		return d
+	}
+	return 0
+}
+
+// gatewayThrottledError reports a Gateway mutation deferred by the rate
+// limiter.
+type gatewayThrottledError struct {
+	delay time.Duration
+}
+
+func (e *gatewayThrottledError) Error() string {
+	return fmt.Sprintf("gateway mutation throttled, retry in %s", e.delay)
+}
+
+// throttleDelay returns how long to wait when err is a throttled mutation.
+func throttleDelay(err error) (time.Duration, bool) {
+	var throttled *gatewayThrottledError
+	if errors.As(err, &throttled) {
+		return throttled.delay, true
+	}
+	return 0, false
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestReconcile_GatewayMutationRate(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	objs := []client.Object{gateway}
+	for i := range 3 {
+		objs = append(objs, &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       fmt.Sprintf("app-%d", i),
+				Namespace:  "default",
+				Finalizers: []string{finalizerName},
+				Annotations: map[string]string{
+					"cert-manager.io/cluster-issuer": "letsencrypt",
+				},
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(fmt.Sprintf("app-%d.example.com", i))},
+			},
+		})
+	}
+
+	var gatewayWrites int
+	r := newReconciler()
+	r.GatewayMutationRate = 0.001
+	r.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).WithStatusSubresource(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if _, ok := obj.(*gatewayv1.Gateway); ok {
+					gatewayWrites++
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}).Build()
+	ctx := context.Background()
+
+	for i := range 3 {
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: fmt.Sprintf("app-%d", i), Namespace: "default"}}
+		result, err := r.Reconcile(ctx, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if i > 0 && result.RequeueAfter <= 0 {
+			t.Errorf("expected route %d to be requeued, got %+v", i, result)
+		}
+	}
+	if gatewayWrites != 1 {
+		t.Errorf("expected exactly one gateway write under the burst, got %d", gatewayWrites)
+	}
+
+	var gw gatewayv1.Gateway
+	if err := r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw); err != nil {
+		t.Fatalf("failed to get gateway: %v", err)
+	}
+	if len(gw.Spec.Listeners) != 1 {
+		t.Errorf("expected only the first listener, got %v", listenerNames(&gw))
+	}
+}
+
+func TestGatewayLimiters_Unlimited(t *testing.T) {
+	var limiters gatewayLimiters
+	key := types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}
+	for range 10 {
+		if d := limiters.delay(key, 0); d != 0 {
+			t.Fatalf("expected no delay without a rate, got %s", d)
+		}
+	}
+}
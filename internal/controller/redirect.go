@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"strconv"
+
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// httpRedirectAnnotation overrides CreateHTTPRedirect for the annotated route
+// with "true" or "false".
+const httpRedirectAnnotation = "gateway-auto-listener/http-redirect"
+
+// httpRedirectPort is the port of the plain HTTP listeners created next to
+// the HTTPS ones.
+const httpRedirectPort gatewayv1.PortNumber = 80
+
+// httpRedirect reports whether the route's hostnames get a plain HTTP
+// listener for redirecting to HTTPS. An unparseable annotation is ignored.
+func (r *HTTPRouteReconciler) httpRedirect(httpRoute *gatewayv1.HTTPRoute) bool {
+	if enabled, err := strconv.ParseBool(httpRoute.Annotations[httpRedirectAnnotation]); err == nil {
+		return enabled
+	}
+	return r.CreateHTTPRedirect
+}
+
+// httpListenerName returns the name of a hostname's plain HTTP listener,
+// shortened to MaxListenerNameLength when set.
+func (r *HTTPRouteReconciler) httpListenerName(hostname string) string {
+	return truncateName("http-"+sanitizeHostname(hostname), r.MaxListenerNameLength)
+}
+
+// hostnameListenerNames returns the names of every listener of a hostname:
+// one per port, plus the plain HTTP listener when redirect is set.
+func (r *HTTPRouteReconciler) hostnameListenerNames(hostname string, ports []gatewayv1.PortNumber, redirect bool) []string {
+	names := make([]string, 0, len(ports)+1)
+	for _, port := range ports {
+		names = append(names, r.listenerName(hostname, port))
+	}
+	if redirect {
+		names = append(names, r.httpListenerName(hostname))
+	}
+	return names
+}
+
+// buildHTTPListener constructs the plain HTTP listener for a hostname. The
+// redirect itself is left to an HTTPRoute RequestRedirect filter.
+func buildHTTPListener(name, hostname string, namespaces *gatewayv1.RouteNamespaces) gatewayv1.Listener {
+	hostnameVal := gatewayv1.Hostname(hostname)
+	return gatewayv1.Listener{
+		Name:     gatewayv1.SectionName(name),
+		Hostname: &hostnameVal,
+		Port:     httpRedirectPort,
+		Protocol: gatewayv1.HTTPProtocolType,
+		AllowedRoutes: &gatewayv1.AllowedRoutes{
+			Namespaces: namespaces,
+		},
+	}
+}
@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestReconcile_HTTPRedirect(t *testing.T) {
+	tests := []struct {
+		name       string
+		flag       bool
+		annotation string
+		want       []string
+	}{
+		{"disabled", false, "", []string{"https-app-example-com"}},
+		{"flag", true, "", []string{"http-app-example-com", "https-app-example-com"}},
+		{"annotation enables", false, "true", []string{"http-app-example-com", "https-app-example-com"}},
+		{"annotation disables", true, "false", []string{"https-app-example-com"}},
+		{"invalid annotation falls back to flag", true, "yes please", []string{"http-app-example-com", "https-app-example-com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{},
+				},
+			}
+			annotations := map[string]string{clusterIssuerAnnotation: "letsencrypt"}
+			if tt.annotation != "" {
+				annotations[httpRedirectAnnotation] = tt.annotation
+			}
+			httpRoute := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "app",
+					Namespace:   "default",
+					Finalizers:  []string{finalizerName},
+					Annotations: annotations,
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"app.example.com"},
+				},
+			}
+
+			r := newReconciler(gateway, httpRoute)
+			r.CreateHTTPRedirect = tt.flag
+			ctx := context.Background()
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			if names := listenerNames(&gw); !slices.Equal(names, tt.want) {
+				t.Fatalf("expected listeners %v, got %v", tt.want, names)
+			}
+			for _, l := range gw.Spec.Listeners {
+				if l.Name != "http-app-example-com" {
+					continue
+				}
+				if l.Port != 80 || l.Protocol != gatewayv1.HTTPProtocolType || l.TLS != nil {
+					t.Errorf("expected plain HTTP listener on port 80, got port %d protocol %s TLS %v", l.Port, l.Protocol, l.TLS)
+				}
+				if l.Hostname == nil || *l.Hostname != "app.example.com" {
+					t.Errorf("expected hostname app.example.com, got %v", l.Hostname)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcile_HTTPRedirectRemovedOnDeletion(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Finalizers:  []string{finalizerName},
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com", "api.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	r.CreateHTTPRedirect = true
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 4 {
+		t.Fatalf("expected HTTPS and HTTP listeners for both hostnames, got %v", listenerNames(&gw))
+	}
+
+	// Dropping a hostname removes both of its listeners
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	route.Spec.Hostnames = []gatewayv1.Hostname{"app.example.com"}
+	if err := r.Update(ctx, &route); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if names := listenerNames(&gw); !slices.Equal(names, []string{"http-app-example-com", "https-app-example-com"}) {
+		t.Fatalf("expected the dropped hostname's listeners to be removed, got %v", names)
+	}
+
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	if err := r.Delete(ctx, &route); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 0 {
+		t.Errorf("expected both listeners to be removed on deletion, got %v", listenerNames(&gw))
+	}
+}
@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+const (
+	// referenceGrantPrefix starts the name of the ReferenceGrant letting the
+	// Gateways of one namespace reference Secrets in another.
+	referenceGrantPrefix = "gateway-auto-listener-"
+	// grantReferencesAnnotation lists, on a managed ReferenceGrant, the
+	// listeners (gateway/listener) referencing a Secret it grants. The grant
+	// is deleted once the list is empty.
+	grantReferencesAnnotation = "gateway-auto-listener/grant-references"
+)
+
+// AddReferenceGrantsToScheme registers the ReferenceGrant type with the
+// scheme.
+func AddReferenceGrantsToScheme(s *runtime.Scheme) error {
+	return gatewayv1beta1.Install(s)
+}
+
+// referenceGrantName returns the name of the ReferenceGrant for the Gateways
+// of gatewayNamespace, scoped to this instance.
+func (r *HTTPRouteReconciler) referenceGrantName(gatewayNamespace string) string {
+	name := referenceGrantPrefix + gatewayNamespace
+	if r.InstanceID != "" {
+		name += "-" + r.InstanceID
+	}
+	return truncateName(name, validation.DNS1123SubdomainMaxLength)
+}
+
+// secretRefNamespace returns the namespace of a certificate ref pointing at
+// a Secret outside gatewayNamespace, or "" for any other ref.
+func secretRefNamespace(ref gatewayv1.SecretObjectReference, gatewayNamespace string) string {
+	if ref.Group != nil && *ref.Group != "" || ref.Kind != nil && *ref.Kind != "Secret" {
+		return ""
+	}
+	if ref.Namespace == nil || string(*ref.Namespace) == gatewayNamespace {
+		return ""
+	}
+	return string(*ref.Namespace)
+}
+
+// grantReferences collects, per Secret namespace other than the Gateway's,
+// the listeners of gateway referencing Secrets there and those Secrets'
+// names.
+func grantReferences(gateway *gatewayv1.Gateway, listeners, secrets map[string]map[string]bool) {
+	add := func(m map[string]map[string]bool, namespace, value string) {
+		if m[namespace] == nil {
+			m[namespace] = make(map[string]bool)
+		}
+		m[namespace][value] = true
+	}
+	for _, l := range gateway.Spec.Listeners {
+		if l.TLS == nil {
+			continue
+		}
+		for _, ref := range l.TLS.CertificateRefs {
+			if namespace := secretRefNamespace(ref, gateway.Namespace); namespace != "" {
+				add(listeners, namespace, gateway.Name+"/"+string(l.Name))
+				add(secrets, namespace, string(ref.Name))
+			}
+		}
+	}
+}
+
+// syncReferenceGrants reference counts the managed ReferenceGrants of the
+// Secret namespaces the listeners of original or gateway reference. A grant
+// lets the Gateways of gateway's namespace reference the Secrets their
+// listeners use; it is counted by the listeners of every managed Gateway in
+// that namespace and deleted with the last of them. Grants outside
+// ReferenceGrantNamespaces are refused with an event on the route.
+func (r *HTTPRouteReconciler) syncReferenceGrants(ctx context.Context, route client.Object, original, gateway *gatewayv1.Gateway) error {
+	if !r.ManageReferenceGrants {
+		return nil
+	}
+
+	touched := make(map[string]map[string]bool)
+	grantReferences(original, touched, make(map[string]map[string]bool))
+	grantReferences(gateway, touched, make(map[string]map[string]bool))
+	if len(touched) == 0 {
+		return nil
+	}
+
+	listeners := make(map[string]map[string]bool)
+	secrets := make(map[string]map[string]bool)
+	grantReferences(gateway, listeners, secrets)
+	for _, key := range r.managedGateways() {
+		if key.Namespace != gateway.Namespace || key.Name == gateway.Name {
+			continue
+		}
+		var other gatewayv1.Gateway
+		if err := r.Get(ctx, key, &other); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get gateway %s: %w", key, err)
+		}
+		grantReferences(&other, listeners, secrets)
+	}
+
+	for namespace := range touched {
+		if len(listeners[namespace]) > 0 && !r.referenceGrantAllowed(namespace) {
+			log.FromContext(ctx).Info("refusing to create a reference grant outside the allowed namespaces", "namespace", namespace)
+			if r.allowListenerEvent(route, gateway, namespace, "ReferenceGrantNotAllowed") {
+				r.recordListenerEvent(route, gateway, corev1.EventTypeWarning, "ReferenceGrantNotAllowed",
+					"no ReferenceGrant created in namespace %s for Gateways of namespace %s: the namespace is not in the ReferenceGrant allowlist",
+					namespace, gateway.Namespace)
+			}
+			continue
+		}
+		if err := r.syncReferenceGrant(ctx, gateway.Namespace, namespace, listeners[namespace], secrets[namespace]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// referenceGrantAllowed reports whether ManageReferenceGrants may create or
+// update a ReferenceGrant in namespace. Deleting an unused grant is always
+// allowed.
+func (r *HTTPRouteReconciler) referenceGrantAllowed(namespace string) bool {
+	return len(r.ReferenceGrantNamespaces) == 0 || slices.Contains(r.ReferenceGrantNamespaces, namespace)
+}
+
+// syncReferenceGrant creates, updates or, once no listener references a
+// Secret in namespace, deletes the managed ReferenceGrant there allowing
+// Gateways from gatewayNamespace to reference the given Secrets. A grant of
+// that name not labelled as managed is left alone. Grants are read through
+// the cached client, so the controller's RBAC must allow listing and watching
+// them.
+func (r *HTTPRouteReconciler) syncReferenceGrant(ctx context.Context, gatewayNamespace, namespace string, listeners, secrets map[string]bool) error {
+	log := log.FromContext(ctx)
+	key := types.NamespacedName{Name: r.referenceGrantName(gatewayNamespace), Namespace: namespace}
+
+	var grant gatewayv1beta1.ReferenceGrant
+	err := r.Get(ctx, key, &grant)
+	switch {
+	case apierrors.IsNotFound(err):
+		if len(listeners) == 0 {
+			return nil
+		}
+		grant = gatewayv1beta1.ReferenceGrant{ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			Labels:    map[string]string{managedByLabel: managedByValue},
+		}}
+		setReferenceGrant(&grant, gatewayNamespace, listeners, secrets)
+		log.Info("creating reference grant", "referenceGrant", key, "references", len(listeners))
+		if err := r.Create(ctx, &grant); err != nil {
+			return fmt.Errorf("failed to create reference grant %s: %w", key, err)
+		}
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to get reference grant %s: %w", key, err)
+	case grant.Labels[managedByLabel] != managedByValue:
+		return nil
+	}
+
+	if len(listeners) == 0 {
+		log.Info("deleting reference grant, no listener references it", "referenceGrant", key)
+		if err := r.Delete(ctx, &grant); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete reference grant %s: %w", key, err)
+		}
+		return nil
+	}
+	desired := grant.DeepCopy()
+	setReferenceGrant(desired, gatewayNamespace, listeners, secrets)
+	if reflect.DeepEqual(desired.Spec, grant.Spec) && reflect.DeepEqual(desired.Annotations, grant.Annotations) {
+		return nil
+	}
+	log.Info("updating reference grant", "referenceGrant", key, "references", len(listeners))
+	if err := r.Update(ctx, desired); err != nil {
+		return fmt.Errorf("failed to update reference grant %s: %w", key, err)
+	}
+	return nil
+}
+
+// setReferenceGrant sets the spec of grant to allow Gateways from
+// gatewayNamespace to reference the named Secrets, and records the listeners
+// referencing them.
+func setReferenceGrant(grant *gatewayv1beta1.ReferenceGrant, gatewayNamespace string, listeners, secrets map[string]bool) {
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	grant.Spec = gatewayv1beta1.ReferenceGrantSpec{
+		From: []gatewayv1beta1.ReferenceGrantFrom{{
+			Group:     gatewayv1.GroupName,
+			Kind:      "Gateway",
+			Namespace: gatewayv1beta1.Namespace(gatewayNamespace),
+		}},
+	}
+	for _, name := range names {
+		objectName := gatewayv1beta1.ObjectName(name)
+		grant.Spec.To = append(grant.Spec.To, gatewayv1beta1.ReferenceGrantTo{Group: "", Kind: "Secret", Name: &objectName})
+	}
+
+	references := make([]string, 0, len(listeners))
+	for ref := range listeners {
+		references = append(references, ref)
+	}
+	sort.Strings(references)
+	if grant.Annotations == nil {
+		grant.Annotations = make(map[string]string)
+	}
+	grant.Annotations[grantReferencesAnnotation] = strings.Join(references, ",")
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func init() {
+	_ = AddReferenceGrantsToScheme(scheme.Scheme)
+}
+
+func TestReconcile_ManageReferenceGrantsRefcount(t *testing.T) {
+	annotations := map[string]string{
+		tlsSecretNameAnnotation:      "wildcard-tls",
+		tlsSecretNamespaceAnnotation: "certs",
+	}
+	app := certificateRoute(annotations)
+	api := certificateRoute(annotations)
+	api.Name = "api"
+	api.Spec.Hostnames = []gatewayv1.Hostname{"api.example.com"}
+	r := newReconciler(emptyGateway(), app, api)
+	r.AllowedSecretNamespaces = []string{"certs"}
+	r.ManageReferenceGrants = true
+	ctx := context.Background()
+	grantKey := types.NamespacedName{Name: "gateway-auto-listener-nginx-gateway", Namespace: "certs"}
+	reconcile := func(name string) {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}}); err != nil {
+			t.Fatalf("unexpected error reconciling %s: %v", name, err)
+		}
+	}
+	deleteRoute := func(name string) {
+		t.Helper()
+		var route gatewayv1.HTTPRoute
+		_ = r.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, &route)
+		if err := r.Delete(ctx, &route); err != nil {
+			t.Fatalf("failed to delete route %s: %v", name, err)
+		}
+		reconcile(name)
+	}
+
+	reconcile("app")
+	reconcile("api")
+	var grant gatewayv1beta1.ReferenceGrant
+	if err := r.Get(ctx, grantKey, &grant); err != nil {
+		t.Fatalf("expected reference grant to be created: %v", err)
+	}
+	if got, want := grant.Annotations[grantReferencesAnnotation], "default/https-api-example-com,default/https-app-example-com"; got != want {
+		t.Errorf("expected grant references %q, got %q", want, got)
+	}
+	if len(grant.Spec.From) != 1 || grant.Spec.From[0].Kind != "Gateway" || grant.Spec.From[0].Namespace != "nginx-gateway" {
+		t.Errorf("expected the grant to allow Gateways from nginx-gateway, got %+v", grant.Spec.From)
+	}
+	if len(grant.Spec.To) != 1 || grant.Spec.To[0].Kind != "Secret" || *grant.Spec.To[0].Name != "wildcard-tls" {
+		t.Errorf("expected the grant to cover the wildcard-tls Secret, got %+v", grant.Spec.To)
+	}
+	if events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "CrossNamespaceSecret"); len(events) != 0 {
+		t.Errorf("expected no CrossNamespaceSecret events with managed grants, got %v", events)
+	}
+
+	deleteRoute("app")
+	if err := r.Get(ctx, grantKey, &grant); err != nil {
+		t.Fatalf("expected the grant still referenced by the other route to be kept: %v", err)
+	}
+	if got, want := grant.Annotations[grantReferencesAnnotation], "default/https-api-example-com"; got != want {
+		t.Errorf("expected grant references %q after deleting one route, got %q", want, got)
+	}
+
+	deleteRoute("api")
+	if err := r.Get(ctx, grantKey, &grant); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the grant to be deleted with its last reference, got %v", err)
+	}
+}
+
+func TestReconcile_ReferenceGrantNamespaces(t *testing.T) {
+	tests := []struct {
+		name        string
+		allowed     []string
+		wantGrant   bool
+		wantRefusal bool
+	}{
+		{name: "empty allowlist allows every namespace", wantGrant: true},
+		{name: "namespace in allowlist", allowed: []string{"certs"}, wantGrant: true},
+		{name: "namespace outside allowlist", allowed: []string{"other"}, wantRefusal: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReconciler(emptyGateway(), certificateRoute(map[string]string{
+				tlsSecretNameAnnotation:      "wildcard-tls",
+				tlsSecretNamespaceAnnotation: "certs",
+			}))
+			r.AllowedSecretNamespaces = []string{"certs"}
+			r.ManageReferenceGrants = true
+			r.ReferenceGrantNamespaces = tt.allowed
+			ctx := context.Background()
+
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var grant gatewayv1beta1.ReferenceGrant
+			err := r.Get(ctx, types.NamespacedName{Name: "gateway-auto-listener-nginx-gateway", Namespace: "certs"}, &grant)
+			if tt.wantGrant && err != nil {
+				t.Errorf("expected reference grant to be created: %v", err)
+			}
+			if !tt.wantGrant && !apierrors.IsNotFound(err) {
+				t.Errorf("expected no reference grant outside the allowlist, got %v", err)
+			}
+			events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "ReferenceGrantNotAllowed")
+			if tt.wantRefusal && len(events) != 1 {
+				t.Errorf("expected one ReferenceGrantNotAllowed event, got %v", events)
+			}
+			if !tt.wantRefusal && len(events) != 0 {
+				t.Errorf("expected no ReferenceGrantNotAllowed events, got %v", events)
+			}
+		})
+	}
+}
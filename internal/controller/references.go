@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// listenerReferences returns the listener names on the Gateway that other
+// live routes managed by this instance still rely on: those they track for
+// their admitted hostnames. A hostname a route merely lists in its spec, e.g.
+// one not allowed for its namespace, keeps nothing alive. Routes sharing a
+// hostname share its listener, so it is only removed once no route
+// references it.
+func (r *HTTPRouteReconciler) listenerReferences(ctx context.Context, httpRoute *gatewayv1.HTTPRoute, key types.NamespacedName) (map[string]bool, error) {
+	referenced, err := r.httpRouteReferences(ctx, client.ObjectKeyFromObject(httpRoute), key)
+	if err != nil || !r.ManageGRPCRoutes {
+		return referenced, err
+	}
+	grpcReferenced, err := r.grpcRouteListenerNames(ctx, r.managedHostnamesKey(key), client.ObjectKey{}, true)
+	if err != nil {
+		return nil, err
+	}
+	for name := range grpcReferenced {
+		referenced[name] = true
+	}
+	return referenced, nil
+}
+
+// httpRouteReferences returns the listener names on the Gateway that live
+// HTTPRoutes other than the excluded one reference, see listenerReferences.
+func (r *HTTPRouteReconciler) httpRouteReferences(ctx context.Context, exclude client.ObjectKey, key types.NamespacedName) (map[string]bool, error) {
+	var routes gatewayv1.HTTPRouteList
+	if err := r.List(ctx, &routes); err != nil {
+		return nil, fmt.Errorf("failed to list httproutes: %w", err)
+	}
+
+	annotationKey := r.managedHostnamesKey(key)
+	referenced := make(map[string]bool)
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if client.ObjectKeyFromObject(route) == exclude ||
+			!route.DeletionTimestamp.IsZero() ||
+			!controllerutil.ContainsFinalizer(route, r.finalizer()) ||
+			!r.shouldManage(route) || isIgnored(route) {
+			continue
+		}
+		if value := route.Annotations[annotationKey]; value != "" {
+			for _, name := range strings.Split(value, ",") {
+				referenced[name] = true
+			}
+		}
+	}
+	return referenced, nil
+}
@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func sharedHostnameRoutes() (*gatewayv1.Gateway, *gatewayv1.HTTPRoute, *gatewayv1.HTTPRoute) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	route := func(namespace string) *gatewayv1.HTTPRoute {
+		return &gatewayv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "shop",
+				Namespace:   namespace,
+				Finalizers:  []string{finalizerName},
+				Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+			},
+			Spec: gatewayv1.HTTPRouteSpec{
+				Hostnames: []gatewayv1.Hostname{"shop.example.com"},
+			},
+		}
+	}
+	return gateway, route("default"), route("storefront")
+}
+
+func TestReconcile_SharedHostnameListenerOutlivesOneRoute(t *testing.T) {
+	gateway, first, second := sharedHostnameRoutes()
+	r := newReconciler(gateway, first, second)
+	ctx := context.Background()
+	firstReq := ctrl.Request{NamespacedName: types.NamespacedName{Name: "shop", Namespace: "default"}}
+	secondReq := ctrl.Request{NamespacedName: types.NamespacedName{Name: "shop", Namespace: "storefront"}}
+
+	for _, req := range []ctrl.Request{firstReq, secondReq} {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	gatewayListeners := func() []string {
+		var gw gatewayv1.Gateway
+		_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+		return listenerNames(&gw)
+	}
+	want := []string{"https-shop-example-com"}
+	if names := gatewayListeners(); !slices.Equal(names, want) {
+		t.Fatalf("expected listeners %v, got %v", want, names)
+	}
+
+	// Deleting the route that created the listener keeps it for the other
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, firstReq.NamespacedName, &route)
+	if err := r.Delete(ctx, &route); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, firstReq); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names := gatewayListeners(); !slices.Equal(names, want) {
+		t.Fatalf("expected the shared listener to survive, got %v", names)
+	}
+
+	// Deleting the last route referencing it removes it
+	_ = r.Get(ctx, secondReq.NamespacedName, &route)
+	if err := r.Delete(ctx, &route); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, secondReq); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names := gatewayListeners(); len(names) != 0 {
+		t.Errorf("expected no listeners after deleting both routes, got %v", names)
+	}
+}
+
+func TestReconcile_SharedHostnameListenerOutlivesHostnameChange(t *testing.T) {
+	gateway, first, second := sharedHostnameRoutes()
+	r := newReconciler(gateway, first, second)
+	ctx := context.Background()
+	firstReq := ctrl.Request{NamespacedName: types.NamespacedName{Name: "shop", Namespace: "default"}}
+	secondReq := ctrl.Request{NamespacedName: types.NamespacedName{Name: "shop", Namespace: "storefront"}}
+
+	for _, req := range []ctrl.Request{firstReq, secondReq} {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, firstReq.NamespacedName, &route)
+	route.Spec.Hostnames = []gatewayv1.Hostname{"store.example.com"}
+	if err := r.Update(ctx, &route); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, firstReq); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	want := []string{"https-shop-example-com", "https-store-example-com"}
+	if names := listenerNames(&gw); !slices.Equal(names, want) {
+		t.Errorf("expected the listener still referenced by the other route to be kept, got %v", names)
+	}
+
+	_ = r.Get(ctx, firstReq.NamespacedName, &route)
+	if value := route.Annotations[managedHostnamesAnnotation]; value != "https-store-example-com" {
+		t.Errorf("expected the route to stop tracking the kept listener, got %q", value)
+	}
+}
+
+func TestReconcile_UnadmittedHostnameKeepsNoListener(t *testing.T) {
+	gateway, first, second := sharedHostnameRoutes()
+	// The other route's namespace is validated and not allowed the hostname
+	second.Namespace = "tenant-x"
+	r := newReconciler(gateway, first, second)
+	ctx := context.Background()
+	firstReq := ctrl.Request{NamespacedName: types.NamespacedName{Name: "shop", Namespace: "default"}}
+	secondReq := ctrl.Request{NamespacedName: types.NamespacedName{Name: "shop", Namespace: "tenant-x"}}
+
+	for _, req := range []ctrl.Request{firstReq, secondReq} {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	var other gatewayv1.HTTPRoute
+	_ = r.Get(ctx, secondReq.NamespacedName, &other)
+	if value := other.Annotations[managedHostnamesAnnotation]; value != "" {
+		t.Fatalf("expected the rejected route to track nothing, got %q", value)
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, firstReq.NamespacedName, &route)
+	if err := r.Delete(ctx, &route); err != nil {
+		t.Fatalf("failed to delete route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, firstReq); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if names := listenerNames(&gw); len(names) != 0 {
+		t.Errorf("expected a hostname the other route was not admitted for to keep no listener, got %v", names)
+	}
+}
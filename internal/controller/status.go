@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// controllerName identifies this controller's entries in route parent status.
+const controllerName gatewayv1.GatewayController = "gateway-auto-listener/controller"
+
+// Route condition types and reasons set by this controller.
+const (
+	conditionSecretSyncPending = "SecretSyncPending"
+	conditionListenersReady    = "ListenersReady"
+	conditionCertificateReady  = "CertificateReady"
+
+	reasonSecretNotSynced  = "ExternalSecretNotSynced"
+	reasonSecretsSynced    = "ExternalSecretsSynced"
+	reasonListenerCreated  = "ListenerCreated"
+	reasonNoListeners      = "NoListeners"
+	reasonHostnameRejected = "HostnameRejected"
+	reasonGatewayNotFound  = "GatewayNotFound"
+	reasonCertificateReady = "CertificatesReady"
+	reasonCertificateWait  = "CertificateNotReady"
+)
+
+// setRouteCondition sets a condition, owned by this controller, on the
+// route's parent status entry of every managed Gateway it attaches to, i.e.
+// the parentRefs it is reconciled against. Entries of Gateways it no longer
+// attaches to are dropped. The status is only written when it changed.
+func (r *HTTPRouteReconciler) setRouteCondition(ctx context.Context, httpRoute *gatewayv1.HTTPRoute, condition metav1.Condition) error {
+	condition.ObservedGeneration = httpRoute.Generation
+
+	refs := r.statusParentRefs(httpRoute)
+	wanted := make(map[types.NamespacedName]bool, len(refs))
+	for _, ref := range refs {
+		wanted[parentRefGateway(httpRoute, ref)] = true
+	}
+
+	changed := false
+	parents := httpRoute.Status.Parents[:0]
+	for _, parent := range httpRoute.Status.Parents {
+		if parent.ControllerName == controllerName && !wanted[parentRefGateway(httpRoute, parent.ParentRef)] {
+			changed = true
+			continue
+		}
+		parents = append(parents, parent)
+	}
+	httpRoute.Status.Parents = parents
+
+	for _, ref := range refs {
+		key := parentRefGateway(httpRoute, ref)
+		var parent *gatewayv1.RouteParentStatus
+		for i := range httpRoute.Status.Parents {
+			if httpRoute.Status.Parents[i].ControllerName == controllerName &&
+				parentRefGateway(httpRoute, httpRoute.Status.Parents[i].ParentRef) == key {
+				parent = &httpRoute.Status.Parents[i]
+				break
+			}
+		}
+		if parent == nil {
+			httpRoute.Status.Parents = append(httpRoute.Status.Parents, gatewayv1.RouteParentStatus{
+				ParentRef:      ref,
+				ControllerName: controllerName,
+			})
+			parent = &httpRoute.Status.Parents[len(httpRoute.Status.Parents)-1]
+			changed = true
+		}
+		if meta.SetStatusCondition(&parent.Conditions, condition) {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := r.Status().Update(ctx, httpRoute); err != nil {
+		return fmt.Errorf("failed to update httproute status: %w", err)
+	}
+	return nil
+}
+
+// statusParentRefs returns the parentRefs of the managed Gateways the route
+// attaches to, as the route spells them where it lists one. A route
+// attaching to none reports against the primary Gateway, so why nothing was
+// reconciled still has an entry.
+func (r *HTTPRouteReconciler) statusParentRefs(httpRoute *gatewayv1.HTTPRoute) []gatewayv1.ParentReference {
+	gateways := r.routeGateways(httpRoute)
+	if len(gateways) == 0 {
+		gateways = []types.NamespacedName{r.primaryGateway()}
+	}
+
+	refs := make([]gatewayv1.ParentReference, 0, len(gateways))
+	for _, key := range gateways {
+		i := slices.IndexFunc(httpRoute.Spec.ParentRefs, func(ref gatewayv1.ParentReference) bool {
+			return parentRefGateway(httpRoute, ref) == key
+		})
+		if i >= 0 {
+			refs = append(refs, httpRoute.Spec.ParentRefs[i])
+			continue
+		}
+		group := gatewayv1.Group(gatewayv1.GroupName)
+		kind := gatewayv1.Kind("Gateway")
+		namespace := gatewayv1.Namespace(key.Namespace)
+		refs = append(refs, gatewayv1.ParentReference{
+			Group:     &group,
+			Kind:      &kind,
+			Namespace: &namespace,
+			Name:      gatewayv1.ObjectName(key.Name),
+		})
+	}
+	return refs
+}
+
+// parentRefGateway returns the Gateway a parentRef of the route points at, or
+// the zero name when it points at another kind of parent.
+func parentRefGateway(httpRoute *gatewayv1.HTTPRoute, ref gatewayv1.ParentReference) types.NamespacedName {
+	if ref.Group != nil && *ref.Group != gatewayv1.GroupName || ref.Kind != nil && *ref.Kind != "Gateway" {
+		return types.NamespacedName{}
+	}
+	namespace := httpRoute.Namespace
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	return types.NamespacedName{Name: string(ref.Name), Namespace: namespace}
+}
+
+// setListenersReadyCondition reports the outcome of a successful reconcile in
+// the ListenersReady condition, naming the route's listeners on the managed
+// Gateways and any hostnames validation rejected. Dry-run routes are left
+// alone.
+func (r *HTTPRouteReconciler) setListenersReadyCondition(ctx context.Context, httpRoute *gatewayv1.HTTPRoute, summary *reconcileSummary) error {
+	if r.isDryRun(httpRoute) {
+		return nil
+	}
+
+	listeners := "none"
+	if len(summary.listeners) > 0 {
+		listeners = strings.Join(summary.listeners, ", ")
+	}
+	condition := metav1.Condition{
+		Type:    conditionListenersReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonListenerCreated,
+		Message: "managed listeners: " + listeners,
+	}
+	switch {
+	case len(summary.rejectedHostnames) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonHostnameRejected
+		condition.Message = fmt.Sprintf("rejected hostnames: %s; managed listeners: %s",
+			strings.Join(summary.rejectedHostnames, ", "), listeners)
+	case listeners == "none":
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonNoListeners
+	}
+	return r.setRouteCondition(ctx, httpRoute, condition)
+}
+
+// setGatewayNotFoundCondition sets the ListenersReady condition to
+// GatewayNotFound when err reports a missing managed Gateway, recording a
+// single WaitingForGateway event when the route starts waiting. Failing to
+// write the status is only logged; the reconcile is retried either way.
+func (r *HTTPRouteReconciler) setGatewayNotFoundCondition(ctx context.Context, httpRoute *gatewayv1.HTTPRoute, err error) {
+	if !errors.Is(err, errGatewayNotFound) || r.isDryRun(httpRoute) {
+		return
+	}
+	if !r.waitingForGateway(httpRoute) {
+		r.Recorder.Eventf(httpRoute, corev1.EventTypeNormal, "WaitingForGateway",
+			"%v; listeners are provisioned once it is created", err)
+	}
+	if err := r.setRouteCondition(ctx, httpRoute, metav1.Condition{
+		Type:    conditionListenersReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  reasonGatewayNotFound,
+		Message: err.Error(),
+	}); err != nil {
+		log.FromContext(ctx).Error(err, "failed to set gateway not found condition")
+	}
+}
+
+// waitingForGateway reports whether the route's ListenersReady condition
+// already records a missing Gateway.
+func (r *HTTPRouteReconciler) waitingForGateway(httpRoute *gatewayv1.HTTPRoute) bool {
+	for _, parent := range httpRoute.Status.Parents {
+		if parent.ControllerName != controllerName {
+			continue
+		}
+		condition := meta.FindStatusCondition(parent.Conditions, conditionListenersReady)
+		return condition != nil && condition.Reason == reasonGatewayNotFound
+	}
+	return false
+}
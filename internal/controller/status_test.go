@@ -0,0 +1,242 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func listenersReadyCondition(t *testing.T, r *HTTPRouteReconciler, key types.NamespacedName) *metav1.Condition {
+	t.Helper()
+	var route gatewayv1.HTTPRoute
+	if err := r.Get(context.Background(), key, &route); err != nil {
+		t.Fatalf("failed to get route: %v", err)
+	}
+	for _, parent := range route.Status.Parents {
+		if parent.ControllerName == controllerName {
+			return meta.FindStatusCondition(parent.Conditions, conditionListenersReady)
+		}
+	}
+	return nil
+}
+
+func TestReconcile_ListenersReadyCondition(t *testing.T) {
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "tenant-shop",
+			Finalizers:  []string{finalizerName},
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.tenant-shop.example.com", "api.tenant-shop.example.com"},
+		},
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-shop"}}
+	r := newReconciler(namespace, route)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "tenant-shop"}}
+
+	reconcile := func() {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, req); err != nil && !errors.Is(err, errGatewayNotFound) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	expect := func(status metav1.ConditionStatus, reason, message string) {
+		t.Helper()
+		condition := listenersReadyCondition(t, r, req.NamespacedName)
+		if condition == nil {
+			t.Fatal("expected ListenersReady condition")
+		}
+		if condition.Status != status || condition.Reason != reason || condition.Message != message {
+			t.Errorf("expected %s/%s %q, got %s/%s %q", status, reason, message,
+				condition.Status, condition.Reason, condition.Message)
+		}
+	}
+
+	// The Gateway does not exist yet
+	reconcile()
+	expect(metav1.ConditionFalse, reasonGatewayNotFound, "gateway not found: nginx-gateway/default")
+
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	if err := r.Create(ctx, gateway); err != nil {
+		t.Fatalf("failed to create gateway: %v", err)
+	}
+	reconcile()
+	expect(metav1.ConditionTrue, reasonListenerCreated,
+		"managed listeners: https-api-tenant-shop-example-com, https-app-tenant-shop-example-com")
+
+	var current gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &current)
+	current.Spec.Hostnames = []gatewayv1.Hostname{"app.tenant-shop.example.com", "app.other.org"}
+	if err := r.Update(ctx, &current); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	reconcile()
+	expect(metav1.ConditionFalse, reasonHostnameRejected,
+		"rejected hostnames: app.other.org; managed listeners: https-app-tenant-shop-example-com")
+
+	_ = r.Get(ctx, req.NamespacedName, &current)
+	current.Spec.Hostnames = []gatewayv1.Hostname{"app.other.org"}
+	if err := r.Update(ctx, &current); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	reconcile()
+	expect(metav1.ConditionFalse, reasonHostnameRejected, "rejected hostnames: app.other.org; managed listeners: none")
+}
+
+func TestReconcile_ListenersReadyConditionWrittenOnce(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Finalizers:  []string{finalizerName},
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+
+	var writes int
+	r := newReconciler()
+	r.Client = writeCountingClient(&writes, gateway, route)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if listenersReadyCondition(t, r, req.NamespacedName) == nil {
+		t.Fatal("expected ListenersReady condition")
+	}
+
+	// Forget the fingerprint so the next pass does the full work again
+	r.fingerprints.forget(req.NamespacedName)
+	writes = 0
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if writes != 0 {
+		t.Errorf("expected an unchanged condition not to be written again, got %d writes", writes)
+	}
+}
+
+func TestReconcile_ListenersReadyConditionOnReconciledParents(t *testing.T) {
+	internal := emptyGateway()
+	internal.Name = "internal"
+	gatewayNamespace := gatewayv1.Namespace("nginx-gateway")
+	route := certificateRoute(map[string]string{clusterIssuerAnnotation: "letsencrypt"})
+	route.Spec.ParentRefs = []gatewayv1.ParentReference{{Name: "internal", Namespace: &gatewayNamespace}}
+	r := newReconciler(emptyGateway(), internal, route)
+	r.AdditionalGateways = []types.NamespacedName{{Name: "internal", Namespace: "nginx-gateway"}}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+	parentGateways := func() []string {
+		t.Helper()
+		var current gatewayv1.HTTPRoute
+		if err := r.Get(ctx, req.NamespacedName, &current); err != nil {
+			t.Fatalf("failed to get route: %v", err)
+		}
+		var names []string
+		for _, parent := range current.Status.Parents {
+			if parent.ControllerName != controllerName {
+				continue
+			}
+			if !meta.IsStatusConditionTrue(parent.Conditions, conditionListenersReady) {
+				t.Errorf("expected ListenersReady on parent %s, got %v", parent.ParentRef.Name, parent.Conditions)
+			}
+			names = append(names, string(parent.ParentRef.Name))
+		}
+		return names
+	}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := parentGateways(); !slices.Equal(got, []string{"internal"}) {
+		t.Errorf("expected the condition on the internal parentRef only, got %v", got)
+	}
+
+	// Attaching to both Gateways reports on both, dropping none
+	var current gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &current)
+	current.Spec.ParentRefs = append(current.Spec.ParentRefs, gatewayv1.ParentReference{Name: "default", Namespace: &gatewayNamespace})
+	if err := r.Update(ctx, &current); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := parentGateways(); !slices.Equal(got, []string{"internal", "default"}) {
+		t.Errorf("expected the condition on both parentRefs, got %v", got)
+	}
+
+	// Detaching from one Gateway drops its entry
+	_ = r.Get(ctx, req.NamespacedName, &current)
+	current.Spec.ParentRefs = current.Spec.ParentRefs[1:]
+	if err := r.Update(ctx, &current); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := parentGateways(); !slices.Equal(got, []string{"default"}) {
+		t.Errorf("expected the detached parentRef to be dropped, got %v", got)
+	}
+}
+
+func TestReconcile_ListenersReadyConditionDryRun(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+			Annotations: map[string]string{
+				clusterIssuerAnnotation: "letsencrypt",
+				dryRunAnnotation:        "true",
+			},
+			Finalizers: []string{finalizerName},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+	r := newReconciler(gateway, route)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if condition := listenersReadyCondition(t, r, req.NamespacedName); condition != nil {
+		t.Errorf("expected no condition on a dry-run route, got %v", condition)
+	}
+}
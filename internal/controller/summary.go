@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"slices"
+
+	"github.com/go-logr/logr"
+)
+
+// reconcileSummary collects the outcome of a single reconcile for the
+// ReconcileSummaryLog line and the ListenersReady condition.
+type reconcileSummary struct {
+	added              int
+	removed            int
+	validationFailures int
+	gatewayPatched     bool
+	listeners          []string
+	rejectedHostnames  []string
+	// issuerMissing is set when listeners were skipped for a missing
+	// issuer, so the route is requeued.
+	issuerMissing bool
+}
+
+// log emits the summary as one structured info line.
+func (s *reconcileSummary) log(log logr.Logger) {
+	log.Info("reconcile summary",
+		"listenersAdded", s.added,
+		"listenersRemoved", s.removed,
+		"validationFailures", s.validationFailures,
+		"gatewayPatched", s.gatewayPatched)
+}
+
+// clone returns a copy of the summary that shares no slices with it.
+func (s reconcileSummary) clone() reconcileSummary {
+	s.listeners = slices.Clone(s.listeners)
+	s.rejectedHostnames = slices.Clone(s.rejectedHostnames)
+	return s
+}
+
+// insertSorted adds value to the sorted list unless it is already present.
+func insertSorted(list []string, value string) []string {
+	i, found := slices.BinarySearch(list, value)
+	if found {
+		return list
+	}
+	return slices.Insert(list, i, value)
+}
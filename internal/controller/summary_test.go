@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestReconcile_SummaryLog(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a"}}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "tenant-a",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.tenant-a.example.com", "app.other.org"},
+		},
+	}
+
+	var lines []string
+	sink := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{})
+	ctx := ctrl.LoggerInto(context.Background(), sink)
+
+	r := newReconciler(gateway, ns, httpRoute)
+	r.ReconcileSummaryLog = true
+	_, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "app", Namespace: "tenant-a"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var summaries []string
+	for _, line := range lines {
+		if strings.Contains(line, `"msg"="reconcile summary"`) {
+			summaries = append(summaries, line)
+		}
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("expected one summary line, got %v", summaries)
+	}
+	for _, field := range []string{
+		`"listenersAdded"=1`,
+		`"listenersRemoved"=0`,
+		`"validationFailures"=1`,
+		`"gatewayPatched"=true`,
+	} {
+		if !strings.Contains(summaries[0], field) {
+			t.Errorf("expected %s in summary line, got %s", field, summaries[0])
+		}
+	}
+}
+
+func TestReconcile_SummaryLogDisabled(t *testing.T) {
+	var lines []string
+	sink := funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{})
+	ctx := ctrl.LoggerInto(context.Background(), sink)
+
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+			},
+		},
+	}
+	r := newReconciler(httpRoute)
+	if _, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, line := range lines {
+		if strings.Contains(line, "reconcile summary") {
+			t.Errorf("expected no summary line by default, got %s", line)
+		}
+	}
+}
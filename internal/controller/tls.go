@@ -0,0 +1,287 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// defaultTLSOptionsAnnotation holds a JSON object of TLS options applied to
+	// every listener created on the annotated Gateway.
+	defaultTLSOptionsAnnotation = "gateway-auto-listener/default-tls-options"
+	// tlsOptionsAnnotation holds comma-separated key=value TLS options for the
+	// listeners of the annotated route, overriding the Gateway defaults.
+	tlsOptionsAnnotation = "gateway-auto-listener/tls-options"
+	// defaultCertSecretAnnotation names the default certificate Secret the
+	// annotated Gateway serves when a listener has no certificate ref.
+	defaultCertSecretAnnotation = "gateway-auto-listener/default-cert-secret"
+	// tlsSecretNameAnnotation and tlsSecretNamespaceAnnotation override the
+	// certificate Secret the annotated route's listeners reference, e.g. to
+	// share a wildcard certificate kept in a dedicated namespace.
+	tlsSecretNameAnnotation      = "gateway-auto-listener/tls-secret-name"
+	tlsSecretNamespaceAnnotation = "gateway-auto-listener/tls-secret-namespace"
+	// tlsModeAnnotation sets the TLS mode of the annotated route's listeners:
+	// Terminate, the default, or Passthrough, leaving TLS to the backend.
+	tlsModeAnnotation = "gateway-auto-listener/tls-mode"
+	// aggregateCertAnnotation makes the annotated route's listeners share
+	// one certificate Secret covering all its hostnames when "true".
+	aggregateCertAnnotation = "gateway-auto-listener/aggregate-cert"
+)
+
+// isAggregateCert reports whether the route asks for one certificate Secret
+// shared by all its listeners. An unparseable value does not.
+func isAggregateCert(httpRoute *gatewayv1.HTTPRoute) bool {
+	aggregate, err := strconv.ParseBool(httpRoute.Annotations[aggregateCertAnnotation])
+	return err == nil && aggregate
+}
+
+// isPassthrough reports whether the route asks for TLS passthrough
+// listeners. Only HTTPRoutes honour tlsModeAnnotation: a GRPCRoute cannot
+// attach to a passthrough listener, so it never makes one managed.
+func isPassthrough(route client.Object) bool {
+	if _, ok := route.(*gatewayv1.HTTPRoute); !ok {
+		return false
+	}
+	return route.GetAnnotations()[tlsModeAnnotation] == string(gatewayv1.TLSModePassthrough)
+}
+
+// listenerTLSMode returns the TLS mode of the route's listeners: Terminate by
+// default, or Passthrough when the tls-mode annotation asks for it. Invalid
+// values are reported and ignored.
+func (r *HTTPRouteReconciler) listenerTLSMode(ctx context.Context, httpRoute *gatewayv1.HTTPRoute) gatewayv1.TLSModeType {
+	value, ok := httpRoute.Annotations[tlsModeAnnotation]
+	if !ok {
+		return gatewayv1.TLSModeTerminate
+	}
+	switch mode := gatewayv1.TLSModeType(value); mode {
+	case gatewayv1.TLSModeTerminate, gatewayv1.TLSModePassthrough:
+		return mode
+	}
+	log.FromContext(ctx).Info("ignoring invalid tls-mode annotation", "mode", value)
+	r.Recorder.Eventf(httpRoute, corev1.EventTypeWarning, "InvalidTLSMode",
+		"annotation %s must be %s or %s, got %q; using %s", tlsModeAnnotation,
+		gatewayv1.TLSModeTerminate, gatewayv1.TLSModePassthrough, value, gatewayv1.TLSModeTerminate)
+	return gatewayv1.TLSModeTerminate
+}
+
+// makePassthrough turns a built listener into a TLS passthrough listener.
+// Passthrough needs the TLS protocol and carries no certificate refs.
+func makePassthrough(l *gatewayv1.Listener) {
+	mode := gatewayv1.TLSModePassthrough
+	l.Protocol = gatewayv1.TLSProtocolType
+	l.TLS.Mode = &mode
+	l.TLS.CertificateRefs = nil
+}
+
+// parseGatewayTLSOptions reads the default TLS options from a Gateway
+// annotation.
+func parseGatewayTLSOptions(gateway *gatewayv1.Gateway) (map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue, error) {
+	value := gateway.Annotations[defaultTLSOptionsAnnotation]
+	if value == "" {
+		return nil, nil
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", defaultTLSOptionsAnnotation, err)
+	}
+
+	options := make(map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue, len(raw))
+	for k, v := range raw {
+		if err := validateTLSOptionKey(k); err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %w", defaultTLSOptionsAnnotation, err)
+		}
+		options[gatewayv1.AnnotationKey(k)] = gatewayv1.AnnotationValue(v)
+	}
+	return options, nil
+}
+
+// parseRouteTLSOptions reads the TLS option overrides from a route annotation.
+func parseRouteTLSOptions(httpRoute *gatewayv1.HTTPRoute) (map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue, error) {
+	value := httpRoute.Annotations[tlsOptionsAnnotation]
+	if value == "" {
+		return nil, nil
+	}
+
+	options, err := ParseTLSOptions(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", tlsOptionsAnnotation, err)
+	}
+	return options, nil
+}
+
+// ParseTLSOptions parses comma-separated key=value TLS options. Keys must be
+// qualified names, as the Gateway API requires of option keys. It returns nil
+// when no options are set.
+func ParseTLSOptions(value string) (map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue, error) {
+	var options map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("entry %q is not key=value", pair)
+		}
+		if err := validateTLSOptionKey(strings.TrimSpace(k)); err != nil {
+			return nil, err
+		}
+		if options == nil {
+			options = make(map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue)
+		}
+		options[gatewayv1.AnnotationKey(strings.TrimSpace(k))] = gatewayv1.AnnotationValue(strings.TrimSpace(v))
+	}
+	return options, nil
+}
+
+// validateTLSOptionKey checks that a TLS option key is a qualified name, e.g.
+// example.com/min-version.
+func validateTLSOptionKey(key string) error {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Errorf("option key %q is invalid: %s", key, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// mergeTLSOptions layers the given option sets in order, later sets taking
+// precedence. It returns nil when no options are set.
+func mergeTLSOptions(sets ...map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue) map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue {
+	var merged map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue
+	for _, set := range sets {
+		for k, v := range set {
+			if merged == nil {
+				merged = make(map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue)
+			}
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// listenerTLSOptions resolves the TLS options for the route's listeners on the
+// Gateway: ListenerTLSOptions, overridden by the Gateway defaults, overridden
+// by the route's own. Invalid annotations are reported and skipped rather than
+// failing the reconcile.
+func (r *HTTPRouteReconciler) listenerTLSOptions(ctx context.Context, gateway *gatewayv1.Gateway, httpRoute *gatewayv1.HTTPRoute) map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue {
+	log := log.FromContext(ctx)
+
+	gatewayOptions, err := parseGatewayTLSOptions(gateway)
+	if err != nil {
+		log.Error(err, "ignoring gateway default TLS options")
+		r.Recorder.Eventf(gateway, corev1.EventTypeWarning, "InvalidTLSOptions", "%v", err)
+	}
+	routeOptions, err := parseRouteTLSOptions(httpRoute)
+	if err != nil {
+		log.Error(err, "ignoring route TLS options")
+		r.Recorder.Eventf(httpRoute, corev1.EventTypeWarning, "InvalidTLSOptions", "%v", err)
+	}
+
+	return mergeTLSOptions(r.ListenerTLSOptions, gatewayOptions, routeOptions)
+}
+
+// omitCertificateRefs reports whether listeners created on the Gateway leave
+// out their certificate ref in favour of the Gateway's default certificate.
+// Terminate listeners need either certificate refs or TLS options, so the ref
+// is only omitted when the listeners carry TLS options.
+func (r *HTTPRouteReconciler) omitCertificateRefs(ctx context.Context, gateway *gatewayv1.Gateway, httpRoute *gatewayv1.HTTPRoute,
+	tlsOptions map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue) bool {
+	if !r.SkipCertRefIfDefault || gateway.Annotations[defaultCertSecretAnnotation] == "" {
+		return false
+	}
+	if len(tlsOptions) == 0 {
+		log.FromContext(ctx).Info("keeping certificate refs: Terminate listeners without TLS options need one",
+			"defaultCertSecret", gateway.Annotations[defaultCertSecretAnnotation])
+		r.Recorder.Eventf(httpRoute, corev1.EventTypeWarning, "CertificateRefRequired",
+			"Gateway %s/%s has a default certificate, but Terminate listeners without TLS options need a certificate ref",
+			gateway.Namespace, gateway.Name)
+		return false
+	}
+	return true
+}
+
+// routeSecretOverride reads the certificate Secret override of a route: the
+// Secret name, shared by all its listeners, and namespace. Either is empty
+// when not overridden. Invalid values are dropped and returned as an error
+// for the caller to report.
+func routeSecretOverride(route client.Object) (namespace, name string, err error) {
+	var errs []string
+	name = strings.TrimSpace(route.GetAnnotations()[tlsSecretNameAnnotation])
+	if msgs := validation.IsDNS1123Subdomain(name); name != "" && len(msgs) > 0 {
+		errs = append(errs, fmt.Sprintf("annotation %s: %s", tlsSecretNameAnnotation, strings.Join(msgs, ", ")))
+		name = ""
+	}
+	namespace = strings.TrimSpace(route.GetAnnotations()[tlsSecretNamespaceAnnotation])
+	if msgs := validation.IsDNS1123Label(namespace); namespace != "" && len(msgs) > 0 {
+		errs = append(errs, fmt.Sprintf("annotation %s: %s", tlsSecretNamespaceAnnotation, strings.Join(msgs, ", ")))
+		namespace = ""
+	}
+	if len(errs) > 0 {
+		err = errors.New(strings.Join(errs, "; "))
+	}
+	return namespace, name, err
+}
+
+// hasSecretOverride reports whether the route, of any kind, names an
+// existing certificate Secret for its listeners, e.g. one synced from a vault
+// rather than issued by cert-manager.
+func hasSecretOverride(route client.Object) bool {
+	_, name, _ := routeSecretOverride(route)
+	return name != ""
+}
+
+// secretNamespaceAllowed reports whether listeners on a Gateway in
+// gatewayNamespace may reference Secrets in namespace.
+func (r *HTTPRouteReconciler) secretNamespaceAllowed(gatewayNamespace, namespace string) bool {
+	return namespace == "" || namespace == gatewayNamespace || slices.Contains(r.AllowedSecretNamespaces, namespace)
+}
+
+// listenerSecretRef returns the namespace and name override of the
+// certificate Secret the route's listeners on the Gateway reference. The
+// namespace defaults to the Gateway's; the name is empty when generated per
+// hostname. Invalid overrides and namespaces outside AllowedSecretNamespaces
+// are reported and ignored, and a Secret outside the Gateway namespace is
+// reported as needing a ReferenceGrant unless ManageReferenceGrants creates
+// it.
+func (r *HTTPRouteReconciler) listenerSecretRef(ctx context.Context, gateway *gatewayv1.Gateway, route client.Object) (namespace, name string) {
+	log := log.FromContext(ctx)
+
+	namespace, name, err := routeSecretOverride(route)
+	if err != nil {
+		log.Info("ignoring invalid TLS secret override", "reason", err.Error())
+		r.Recorder.Eventf(route, corev1.EventTypeWarning, "InvalidTLSSecret", "%v", err)
+	}
+	if !r.secretNamespaceAllowed(gateway.Namespace, namespace) {
+		log.Info("ignoring TLS secret namespace not allowed for listeners", "secretNamespace", namespace)
+		r.Recorder.Eventf(route, corev1.EventTypeWarning, "SecretNamespaceNotAllowed",
+			"annotation %s: listeners on Gateway %s/%s may not reference Secrets in namespace %s",
+			tlsSecretNamespaceAnnotation, gateway.Namespace, gateway.Name, namespace)
+		namespace = ""
+	}
+	if namespace == "" {
+		return gateway.Namespace, name
+	}
+	if namespace != gateway.Namespace && !r.ManageReferenceGrants {
+		secret := namespace + "/" + name
+		if name == "" {
+			secret = "secrets in namespace " + namespace
+		}
+		log.Info("listeners reference a secret outside the gateway namespace, a ReferenceGrant is required",
+			"secretNamespace", namespace, "secretName", name, "gatewayNamespace", gateway.Namespace)
+		r.Recorder.Eventf(route, corev1.EventTypeWarning, "CrossNamespaceSecret",
+			"listeners on Gateway %s/%s reference %s; a ReferenceGrant in namespace %s must allow Gateways from namespace %s to reference Secrets",
+			gateway.Namespace, gateway.Name, secret, namespace, gateway.Namespace)
+	}
+	return namespace, name
+}
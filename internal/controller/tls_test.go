@@ -0,0 +1,666 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestParseGatewayTLSOptions(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				defaultTLSOptionsAnnotation: `{"example.com/min-version":"1.2","example.com/ciphers":"modern"}`,
+			},
+		},
+	}
+
+	options, err := parseGatewayTLSOptions(gateway)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(options) != 2 || options["example.com/min-version"] != "1.2" {
+		t.Errorf("unexpected options: %v", options)
+	}
+
+	gateway.Annotations[defaultTLSOptionsAnnotation] = "not-json"
+	if _, err := parseGatewayTLSOptions(gateway); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+
+	gateway.Annotations[defaultTLSOptionsAnnotation] = `{"example.com/min version":"1.2"}`
+	if _, err := parseGatewayTLSOptions(gateway); err == nil {
+		t.Error("expected error for a key that is not a qualified name")
+	}
+}
+
+func TestParseRouteTLSOptions(t *testing.T) {
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				tlsOptionsAnnotation: "example.com/min-version=1.3, example.com/ciphers = strict,",
+			},
+		},
+	}
+
+	options, err := parseRouteTLSOptions(httpRoute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if options["example.com/min-version"] != "1.3" || options["example.com/ciphers"] != "strict" {
+		t.Errorf("unexpected options: %v", options)
+	}
+
+	httpRoute.Annotations[tlsOptionsAnnotation] = "missing-value"
+	if _, err := parseRouteTLSOptions(httpRoute); err == nil {
+		t.Error("expected error for entry without '='")
+	}
+}
+
+func TestParseTLSOptions(t *testing.T) {
+	options, err := ParseTLSOptions("example.com/min-version=1.2,ciphers=modern")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(options) != 2 || options["example.com/min-version"] != "1.2" || options["ciphers"] != "modern" {
+		t.Errorf("unexpected options: %v", options)
+	}
+
+	if options, err := ParseTLSOptions(" , "); err != nil || options != nil {
+		t.Errorf("expected no options for an empty value, got %v, %v", options, err)
+	}
+
+	for _, value := range []string{"-bad=1", "example.com/=1", "Example_.com/key=1", "a/b/c=1"} {
+		if _, err := ParseTLSOptions(value); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestMergeTLSOptions(t *testing.T) {
+	if merged := mergeTLSOptions(nil, nil); merged != nil {
+		t.Errorf("expected nil for no options, got %v", merged)
+	}
+
+	merged := mergeTLSOptions(
+		map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{"a": "gateway", "b": "gateway"},
+		map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{"b": "route"},
+	)
+	if merged["a"] != "gateway" || merged["b"] != "route" {
+		t.Errorf("later option sets should take precedence, got %v", merged)
+	}
+}
+
+func TestReconcile_GatewayDefaultTLSOptions(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "default",
+			Namespace: "nginx-gateway",
+			Annotations: map[string]string{
+				defaultTLSOptionsAnnotation: `{"example.com/min-version":"1.2","example.com/ciphers":"modern"}`,
+			},
+		},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-route",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+				tlsOptionsAnnotation:             "example.com/min-version=1.3",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"test.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "test-route", Namespace: "default"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 || gw.Spec.Listeners[0].TLS == nil {
+		t.Fatalf("expected 1 TLS listener, got %v", gw.Spec.Listeners)
+	}
+
+	options := gw.Spec.Listeners[0].TLS.Options
+	if options["example.com/ciphers"] != "modern" {
+		t.Errorf("expected gateway default to be merged, got %v", options)
+	}
+	if options["example.com/min-version"] != "1.3" {
+		t.Errorf("expected route option to override gateway default, got %v", options)
+	}
+}
+
+func TestReconcile_ListenerTLSOptions(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-route",
+			Namespace:  "default",
+			Finalizers: []string{finalizerName},
+			Annotations: map[string]string{
+				"cert-manager.io/cluster-issuer": "letsencrypt",
+				tlsOptionsAnnotation:             "example.com/min-version=1.3",
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"test.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	r.ListenerTLSOptions = map[gatewayv1.AnnotationKey]gatewayv1.AnnotationValue{
+		"example.com/min-version": "1.2",
+		"example.com/ciphers":     "modern",
+	}
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "test-route", Namespace: "default"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 || gw.Spec.Listeners[0].TLS == nil {
+		t.Fatalf("expected 1 TLS listener, got %v", gw.Spec.Listeners)
+	}
+
+	options := gw.Spec.Listeners[0].TLS.Options
+	if len(options) != 2 || options["example.com/ciphers"] != "modern" {
+		t.Errorf("expected the flag options to be set, got %v", options)
+	}
+	if options["example.com/min-version"] != "1.3" {
+		t.Errorf("expected the route annotation to override the flag option, got %v", options)
+	}
+	if r.ListenerTLSOptions["example.com/min-version"] != "1.2" {
+		t.Errorf("expected the configured options to be left unchanged, got %v", r.ListenerTLSOptions)
+	}
+}
+
+func TestReconcile_SkipCertRefIfDefault(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		skip        bool
+		wantRefs    int
+		wantEvent   bool
+	}{
+		{
+			name: "omitted with default cert and TLS options",
+			annotations: map[string]string{
+				defaultCertSecretAnnotation: "wildcard-tls",
+				defaultTLSOptionsAnnotation: `{"example.com/min-version":"1.2"}`,
+			},
+			skip:     true,
+			wantRefs: 0,
+		},
+		{
+			name:        "kept without TLS options",
+			annotations: map[string]string{defaultCertSecretAnnotation: "wildcard-tls"},
+			skip:        true,
+			wantRefs:    1,
+			wantEvent:   true,
+		},
+		{
+			name:        "kept without default cert",
+			annotations: map[string]string{defaultTLSOptionsAnnotation: `{"example.com/min-version":"1.2"}`},
+			skip:        true,
+			wantRefs:    1,
+		},
+		{
+			name: "kept when disabled",
+			annotations: map[string]string{
+				defaultCertSecretAnnotation: "wildcard-tls",
+				defaultTLSOptionsAnnotation: `{"example.com/min-version":"1.2"}`,
+			},
+			wantRefs: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "default",
+					Namespace:   "nginx-gateway",
+					Annotations: tt.annotations,
+				},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{},
+				},
+			}
+			httpRoute := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-route",
+					Namespace:  "default",
+					Finalizers: []string{finalizerName},
+					Annotations: map[string]string{
+						"cert-manager.io/cluster-issuer": "letsencrypt",
+					},
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"test.example.com"},
+				},
+			}
+
+			r := newReconciler(gateway, httpRoute)
+			r.SkipCertRefIfDefault = tt.skip
+			ctx := context.Background()
+
+			_, err := r.Reconcile(ctx, ctrl.Request{
+				NamespacedName: types.NamespacedName{Name: "test-route", Namespace: "default"},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			if len(gw.Spec.Listeners) != 1 || gw.Spec.Listeners[0].TLS == nil {
+				t.Fatalf("expected 1 TLS listener, got %v", gw.Spec.Listeners)
+			}
+			tls := gw.Spec.Listeners[0].TLS
+			if len(tls.CertificateRefs) != tt.wantRefs {
+				t.Errorf("expected %d certificate refs, got %v", tt.wantRefs, tls.CertificateRefs)
+			}
+			if len(tls.CertificateRefs) == 0 && len(tls.Options) == 0 {
+				t.Error("Terminate listener needs certificate refs or options")
+			}
+			events := eventsWithReason(drainEvents(r.Recorder.(*record.FakeRecorder)), "CertificateRefRequired")
+			if got := len(events) > 0; got != tt.wantEvent {
+				t.Errorf("expected CertificateRefRequired event %v, got %v", tt.wantEvent, events)
+			}
+		})
+	}
+}
+
+func TestReconcile_TLSSecretOverride(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		allowed       []string
+		wantNamespace string
+		wantSecrets   map[string]string
+		wantEvents    []string
+	}{
+		{
+			name:          "generated",
+			wantNamespace: "nginx-gateway",
+			wantSecrets:   map[string]string{"https-app-example-com": "app-example-com-tls", "https-api-example-com": "api-example-com-tls"},
+		},
+		{
+			name:          "shared secret in gateway namespace",
+			annotations:   map[string]string{tlsSecretNameAnnotation: "wildcard-example-com"},
+			wantNamespace: "nginx-gateway",
+			wantSecrets:   map[string]string{"https-app-example-com": "wildcard-example-com", "https-api-example-com": "wildcard-example-com"},
+		},
+		{
+			name:          "shared secret in another namespace",
+			annotations:   map[string]string{tlsSecretNameAnnotation: "wildcard-example-com", tlsSecretNamespaceAnnotation: "certs"},
+			allowed:       []string{"certs"},
+			wantNamespace: "certs",
+			wantSecrets:   map[string]string{"https-app-example-com": "wildcard-example-com", "https-api-example-com": "wildcard-example-com"},
+			wantEvents:    []string{"CrossNamespaceSecret"},
+		},
+		{
+			name:          "namespace only",
+			annotations:   map[string]string{tlsSecretNamespaceAnnotation: "certs"},
+			allowed:       []string{"certs"},
+			wantNamespace: "certs",
+			wantSecrets:   map[string]string{"https-app-example-com": "app-example-com-tls", "https-api-example-com": "api-example-com-tls"},
+			wantEvents:    []string{"CrossNamespaceSecret"},
+		},
+		{
+			name:          "namespace not allowed",
+			annotations:   map[string]string{tlsSecretNameAnnotation: "wildcard-example-com", tlsSecretNamespaceAnnotation: "kube-system"},
+			allowed:       []string{"certs"},
+			wantNamespace: "nginx-gateway",
+			wantSecrets:   map[string]string{"https-app-example-com": "wildcard-example-com", "https-api-example-com": "wildcard-example-com"},
+			wantEvents:    []string{"SecretNamespaceNotAllowed"},
+		},
+		{
+			name:          "no namespace allowed",
+			annotations:   map[string]string{tlsSecretNamespaceAnnotation: "certs"},
+			wantNamespace: "nginx-gateway",
+			wantSecrets:   map[string]string{"https-app-example-com": "app-example-com-tls", "https-api-example-com": "api-example-com-tls"},
+			wantEvents:    []string{"SecretNamespaceNotAllowed"},
+		},
+		{
+			name:          "invalid override ignored",
+			annotations:   map[string]string{tlsSecretNameAnnotation: "Not_A_Secret", tlsSecretNamespaceAnnotation: "certs.example"},
+			wantNamespace: "nginx-gateway",
+			wantSecrets:   map[string]string{"https-app-example-com": "app-example-com-tls", "https-api-example-com": "api-example-com-tls"},
+			wantEvents:    []string{"InvalidTLSSecret"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{},
+				},
+			}
+			annotations := map[string]string{clusterIssuerAnnotation: "letsencrypt"}
+			for k, v := range tt.annotations {
+				annotations[k] = v
+			}
+			httpRoute := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "app",
+					Namespace:   "default",
+					Finalizers:  []string{finalizerName},
+					Annotations: annotations,
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"app.example.com", "api.example.com"},
+				},
+			}
+
+			r := newReconciler(gateway, httpRoute)
+			r.AllowedSecretNamespaces = tt.allowed
+			ctx := context.Background()
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			if len(gw.Spec.Listeners) != len(tt.wantSecrets) {
+				t.Fatalf("expected %d listeners, got %v", len(tt.wantSecrets), listenerNames(&gw))
+			}
+			for _, l := range gw.Spec.Listeners {
+				ref := l.TLS.CertificateRefs[0]
+				if string(ref.Name) != tt.wantSecrets[string(l.Name)] {
+					t.Errorf("listener %s: expected secret %s, got %s", l.Name, tt.wantSecrets[string(l.Name)], ref.Name)
+				}
+				if ref.Namespace == nil || string(*ref.Namespace) != tt.wantNamespace {
+					t.Errorf("listener %s: expected secret namespace %s, got %v", l.Name, tt.wantNamespace, ref.Namespace)
+				}
+			}
+
+			events := drainEvents(r.Recorder.(*record.FakeRecorder))
+			for _, reason := range []string{"CrossNamespaceSecret", "InvalidTLSSecret", "SecretNameCollision", "SecretNamespaceNotAllowed"} {
+				want := slices.Contains(tt.wantEvents, reason)
+				if got := len(eventsWithReason(events, reason)) > 0; got != want {
+					t.Errorf("expected %s event: %v, got events %v", reason, want, events)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcile_TLSMode(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		wantMode     gatewayv1.TLSModeType
+		wantProtocol gatewayv1.ProtocolType
+		wantRefs     int
+		wantEvent    string
+	}{
+		{
+			name:         "terminate by default",
+			annotations:  map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+			wantMode:     gatewayv1.TLSModeTerminate,
+			wantProtocol: gatewayv1.HTTPSProtocolType,
+			wantRefs:     1,
+		},
+		{
+			name:         "passthrough without issuer",
+			annotations:  map[string]string{tlsModeAnnotation: "Passthrough"},
+			wantMode:     gatewayv1.TLSModePassthrough,
+			wantProtocol: gatewayv1.TLSProtocolType,
+		},
+		{
+			name:         "invalid mode",
+			annotations:  map[string]string{clusterIssuerAnnotation: "letsencrypt", tlsModeAnnotation: "passthru"},
+			wantMode:     gatewayv1.TLSModeTerminate,
+			wantProtocol: gatewayv1.HTTPSProtocolType,
+			wantRefs:     1,
+			wantEvent:    "InvalidTLSMode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{},
+				},
+			}
+			httpRoute := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "app",
+					Namespace:   "default",
+					Annotations: tt.annotations,
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"app.example.com"},
+				},
+			}
+
+			r := newReconciler(gateway, httpRoute)
+			r.ValidateListeners = true
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+			for range 2 {
+				if _, err := r.Reconcile(ctx, req); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			if len(gw.Spec.Listeners) != 1 || gw.Spec.Listeners[0].TLS == nil {
+				t.Fatalf("expected 1 TLS listener, got %v", gw.Spec.Listeners)
+			}
+			l := gw.Spec.Listeners[0]
+			if l.TLS.Mode == nil || *l.TLS.Mode != tt.wantMode {
+				t.Errorf("expected TLS mode %s, got %v", tt.wantMode, l.TLS.Mode)
+			}
+			if l.Protocol != tt.wantProtocol {
+				t.Errorf("expected protocol %s, got %s", tt.wantProtocol, l.Protocol)
+			}
+			if len(l.TLS.CertificateRefs) != tt.wantRefs {
+				t.Errorf("expected %d certificate refs, got %v", tt.wantRefs, l.TLS.CertificateRefs)
+			}
+
+			events := drainEvents(r.Recorder.(*record.FakeRecorder))
+			if tt.wantEvent != "" && len(eventsWithReason(events, tt.wantEvent)) == 0 {
+				t.Errorf("expected a %s event, got %v", tt.wantEvent, events)
+			}
+		})
+	}
+}
+
+func TestReconcile_TLSModeSwitchToPassthrough(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+		Spec: gatewayv1.GatewaySpec{
+			GatewayClassName: "nginx",
+			Listeners:        []gatewayv1.Listener{},
+		},
+	}
+	httpRoute := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "default",
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			Hostnames: []gatewayv1.Hostname{"app.example.com"},
+		},
+	}
+
+	r := newReconciler(gateway, httpRoute)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+	for range 2 {
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	route.Annotations[tlsModeAnnotation] = "Passthrough"
+	if err := r.Update(ctx, &route); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 {
+		t.Fatalf("expected 1 listener, got %v", listenerNames(&gw))
+	}
+	if err := validateListener(gw.Spec.Listeners[0]); err != nil {
+		t.Errorf("expected a valid passthrough listener, got: %v", err)
+	}
+	if l := gw.Spec.Listeners[0]; *l.TLS.Mode != gatewayv1.TLSModePassthrough || len(l.TLS.CertificateRefs) != 0 {
+		t.Errorf("expected the listener switched to passthrough, got %+v", l.TLS)
+	}
+}
+
+func TestReconcile_TLSModeSwitchToTerminate(t *testing.T) {
+	httpRoute := certificateRoute(map[string]string{
+		clusterIssuerAnnotation: "letsencrypt",
+		tlsModeAnnotation:       "Passthrough",
+	})
+	r := newReconciler(emptyGateway(), httpRoute)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var route gatewayv1.HTTPRoute
+	_ = r.Get(ctx, req.NamespacedName, &route)
+	delete(route.Annotations, tlsModeAnnotation)
+	if err := r.Update(ctx, &route); err != nil {
+		t.Fatalf("failed to update route: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gw gatewayv1.Gateway
+	_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+	if len(gw.Spec.Listeners) != 1 {
+		t.Fatalf("expected 1 listener, got %v", listenerNames(&gw))
+	}
+	l := gw.Spec.Listeners[0]
+	if l.Protocol != gatewayv1.HTTPSProtocolType {
+		t.Errorf("expected the HTTPS protocol after leaving passthrough, got %s", l.Protocol)
+	}
+	if *l.TLS.Mode != gatewayv1.TLSModeTerminate || len(l.TLS.CertificateRefs) != 1 {
+		t.Errorf("expected the listener switched to terminate with a certificate ref, got %+v", l.TLS)
+	}
+	if err := validateListener(l); err != nil {
+		t.Errorf("expected a valid terminate listener, got: %v", err)
+	}
+}
+
+func TestReconcile_BringYourOwnSecret(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantSecret  string
+	}{
+		{
+			name:        "secret without issuer",
+			annotations: map[string]string{tlsSecretNameAnnotation: "vault-app-example-com"},
+			wantSecret:  "vault-app-example-com",
+		},
+		{
+			name:        "invalid secret without issuer",
+			annotations: map[string]string{tlsSecretNameAnnotation: "Not_A_Secret"},
+		},
+		{
+			name: "neither issuer nor secret",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway := &gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "nginx-gateway"},
+				Spec: gatewayv1.GatewaySpec{
+					GatewayClassName: "nginx",
+					Listeners:        []gatewayv1.Listener{},
+				},
+			}
+			httpRoute := &gatewayv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "app",
+					Namespace:   "default",
+					Annotations: tt.annotations,
+				},
+				Spec: gatewayv1.HTTPRouteSpec{
+					Hostnames: []gatewayv1.Hostname{"app.example.com"},
+				},
+			}
+
+			r := newReconciler(gateway, httpRoute)
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "app", Namespace: "default"}}
+			for range 2 {
+				if _, err := r.Reconcile(ctx, req); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			var gw gatewayv1.Gateway
+			_ = r.Get(ctx, types.NamespacedName{Name: "default", Namespace: "nginx-gateway"}, &gw)
+			if tt.wantSecret == "" {
+				if len(gw.Spec.Listeners) != 0 {
+					t.Errorf("expected the route to be skipped, got listeners %v", listenerNames(&gw))
+				}
+				return
+			}
+			if len(gw.Spec.Listeners) != 1 || gw.Spec.Listeners[0].TLS == nil || len(gw.Spec.Listeners[0].TLS.CertificateRefs) != 1 {
+				t.Fatalf("expected 1 listener with a certificate ref, got %v", gw.Spec.Listeners)
+			}
+			if ref := gw.Spec.Listeners[0].TLS.CertificateRefs[0]; string(ref.Name) != tt.wantSecret {
+				t.Errorf("expected certificate ref %s, got %s", tt.wantSecret, ref.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// HostnameValidator is a validating admission webhook rejecting HTTPRoutes
+// whose hostnames the reconciler would refuse listeners for, so tenants learn
+// about a disallowed hostname when applying the route rather than from a
+// later event. It runs the reconciler's own hostname validation.
+type HostnameValidator struct {
+	*HTTPRouteReconciler
+}
+
+var _ admission.CustomValidator = &HostnameValidator{}
+
+// SetupWithManager registers the webhook with the manager's webhook server,
+// at /validate-gateway-networking-k8s-io-v1-httproute.
+func (v *HostnameValidator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&gatewayv1.HTTPRoute{}).
+		WithValidator(v).
+		Complete()
+}
+
+func (v *HostnameValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	httpRoute, ok := obj.(*gatewayv1.HTTPRoute)
+	if !ok {
+		return nil, fmt.Errorf("expected an HTTPRoute, got %T", obj)
+	}
+	return v.validateRoute(ctx, httpRoute, nil)
+}
+
+// ValidateUpdate only checks hostnames the update adds, so a route admitted
+// before the webhook was enabled can still be updated, e.g. to drop the
+// finalizer.
+func (v *HostnameValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldRoute, ok := oldObj.(*gatewayv1.HTTPRoute)
+	if !ok {
+		return nil, fmt.Errorf("expected an HTTPRoute, got %T", oldObj)
+	}
+	httpRoute, ok := newObj.(*gatewayv1.HTTPRoute)
+	if !ok {
+		return nil, fmt.Errorf("expected an HTTPRoute, got %T", newObj)
+	}
+	if !httpRoute.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	return v.validateRoute(ctx, httpRoute, oldRoute.Spec.Hostnames)
+}
+
+func (v *HostnameValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateRoute validates the hostnames of a route the controller manages,
+// skipping those in existing. Hostnames the reconciler would skip anyway,
+// such as those excluded by the include pattern, are not validated. In
+// shadow mode rejections are returned as warnings.
+func (v *HostnameValidator) validateRoute(ctx context.Context, httpRoute *gatewayv1.HTTPRoute,
+	existing []gatewayv1.Hostname) (admission.Warnings, error) {
+	if !v.shouldManage(httpRoute) || isIgnored(httpRoute) || !v.targetsManagedGateway(httpRoute) {
+		return nil, nil
+	}
+
+	var warnings admission.Warnings
+	var errs []error
+	for _, hostname := range httpRoute.Spec.Hostnames {
+		if hostname == "" || slices.Contains(existing, hostname) {
+			continue
+		}
+		normalized, err := normalizeHostname(string(hostname))
+		if err != nil {
+			continue
+		}
+		if v.HostnameIncludePattern != nil && !v.HostnameIncludePattern.MatchString(normalized) {
+			continue
+		}
+		if err := v.validateHostname(ctx, normalized, httpRoute.Namespace); err != nil {
+			if !isPolicyRejection(err) {
+				return nil, fmt.Errorf("failed to validate hostname %s: %w", normalized, err)
+			}
+			if v.ValidationShadowMode {
+				warnings = append(warnings, fmt.Sprintf("hostname %s would be rejected: %v", normalized, err))
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		log.FromContext(ctx).Info("rejecting httproute", "route", httpRoute.Namespace+"/"+httpRoute.Name,
+			"reason", errors.Join(errs...).Error())
+	}
+	return warnings, errors.Join(errs...)
+}
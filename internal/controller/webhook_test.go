@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func webhookRoute(hostnames ...gatewayv1.Hostname) *gatewayv1.HTTPRoute {
+	return &gatewayv1.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{APIVersion: gatewayv1.GroupVersion.String(), Kind: "HTTPRoute"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "tenant-acme",
+			Annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt"},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{Hostnames: hostnames},
+	}
+}
+
+// admissionRequest encodes the routes into an admission request; old is only
+// set for updates.
+func admissionRequest(t *testing.T, operation admissionv1.Operation, route, old *gatewayv1.HTTPRoute) admission.Request {
+	t.Helper()
+	encode := func(route *gatewayv1.HTTPRoute) runtime.RawExtension {
+		raw, err := json.Marshal(route)
+		if err != nil {
+			t.Fatalf("failed to encode route: %v", err)
+		}
+		return runtime.RawExtension{Raw: raw}
+	}
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		UID:       "uid",
+		Operation: operation,
+		Kind:      metav1.GroupVersionKind{Group: gatewayv1.GroupName, Version: "v1", Kind: "HTTPRoute"},
+		Namespace: route.Namespace,
+		Name:      route.Name,
+		Object:    encode(route),
+	}}
+	if old != nil {
+		req.OldObject = encode(old)
+	}
+	return req
+}
+
+func TestHostnameValidator(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "tenant-acme",
+			Annotations: map[string]string{"gateway-auto-listener/allowed-hostnames": "acme.com"},
+		},
+	}
+	r := newReconciler(ns)
+	handler := admission.WithCustomValidator(scheme.Scheme, &gatewayv1.HTTPRoute{}, &HostnameValidator{HTTPRouteReconciler: r})
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		operation admissionv1.Operation
+		route     *gatewayv1.HTTPRoute
+		old       *gatewayv1.HTTPRoute
+		allowed   bool
+		reason    string
+	}{
+		{
+			name:      "default subdomain",
+			operation: admissionv1.Create,
+			route:     webhookRoute("app.tenant-acme.example.com"),
+			allowed:   true,
+		},
+		{
+			name:      "allowed custom domain",
+			operation: admissionv1.Create,
+			route:     webhookRoute("shop.acme.com"),
+			allowed:   true,
+		},
+		{
+			name:      "disallowed hostname",
+			operation: admissionv1.Create,
+			route:     webhookRoute("shop.acme.com", "evil.com"),
+			reason:    "hostname evil.com not allowed for namespace tenant-acme",
+		},
+		{
+			name:      "hostname added by an update",
+			operation: admissionv1.Update,
+			route:     webhookRoute("shop.acme.com", "evil.com"),
+			old:       webhookRoute("shop.acme.com"),
+			reason:    "hostname evil.com not allowed for namespace tenant-acme",
+		},
+		{
+			name:      "hostname kept by an update",
+			operation: admissionv1.Update,
+			route:     webhookRoute("evil.com"),
+			old:       webhookRoute("evil.com"),
+			allowed:   true,
+		},
+		{
+			name:      "route without issuer",
+			operation: admissionv1.Create,
+			route: func() *gatewayv1.HTTPRoute {
+				route := webhookRoute("evil.com")
+				route.Annotations = nil
+				return route
+			}(),
+			allowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := handler.Handle(ctx, admissionRequest(t, tt.operation, tt.route, tt.old))
+			if resp.Allowed != tt.allowed {
+				t.Fatalf("expected allowed=%v, got %v: %v", tt.allowed, resp.Allowed, resp.Result)
+			}
+			if tt.reason != "" && (resp.Result == nil || !strings.Contains(resp.Result.Message, tt.reason)) {
+				t.Errorf("expected denial message containing %q, got %v", tt.reason, resp.Result)
+			}
+		})
+	}
+}
+
+func TestHostnameValidator_ShadowMode(t *testing.T) {
+	r := newReconciler(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-acme"}})
+	r.ValidationShadowMode = true
+	handler := admission.WithCustomValidator(scheme.Scheme, &gatewayv1.HTTPRoute{}, &HostnameValidator{HTTPRouteReconciler: r})
+
+	resp := handler.Handle(context.Background(), admissionRequest(t, admissionv1.Create, webhookRoute("evil.com"), nil))
+	if !resp.Allowed {
+		t.Fatalf("expected shadow mode to admit the route, got %v", resp.Result)
+	}
+	if len(resp.Warnings) != 1 || !strings.Contains(resp.Warnings[0], "evil.com would be rejected") {
+		t.Errorf("expected a warning for the rejected hostname, got %v", resp.Warnings)
+	}
+}